@@ -31,6 +31,7 @@ import (
 	"github.com/dolthub/dolt/go/libraries/doltcore/table"
 	"github.com/dolthub/dolt/go/libraries/doltcore/table/pipeline"
 	"github.com/dolthub/dolt/go/libraries/doltcore/table/typed/noms"
+	"github.com/dolthub/dolt/go/libraries/doltcore/table/untyped/csv"
 	"github.com/dolthub/dolt/go/libraries/utils/argparser"
 	"github.com/dolthub/dolt/go/libraries/utils/filesys"
 	"github.com/dolthub/dolt/go/libraries/utils/funcitr"
@@ -42,22 +43,48 @@ var exportDocs = cli.CommandDocumentationContent{
 	LongDesc: `{{.EmphasisLeft}}dolt table export{{.EmphasisRight}} will export the contents of {{.LessThan}}table{{.GreaterThan}} to {{.LessThan}}|file{{.GreaterThan}}
 
 See the help for {{.EmphasisLeft}}dolt table import{{.EmphasisRight}} as the options are the same.
+
+An interrupted export can be resumed by exporting to a new file with {{.EmphasisLeft}}--offset{{.EmphasisRight}} set to the number of rows already exported, and {{.EmphasisLeft}}--no-header{{.EmphasisRight}} so that the new file can be appended to the earlier one.
+
+String values that aren't valid UTF-8 are written to csv and psv files unchanged by default. {{.EmphasisLeft}}--invalid-utf8{{.EmphasisRight}} can be set to {{.EmphasisLeft}}error{{.EmphasisRight}} to fail the export instead, {{.EmphasisLeft}}replace{{.EmphasisRight}} to write each invalid byte as the replacement character U+FFFD, or {{.EmphasisLeft}}hex{{.EmphasisRight}} to write each invalid byte as a \xNN escape.
+
+{{.EmphasisLeft}}--where{{.EmphasisRight}} limits the rows written to csv and psv files to those satisfying an expression of the form {{.LessThan}}column{{.GreaterThan}} {{.LessThan}}op{{.GreaterThan}} {{.LessThan}}value{{.GreaterThan}}, where {{.LessThan}}op{{.GreaterThan}} is one of ==, !=, <, >, <= or >=, such as {{.EmphasisLeft}}--where "status == active"{{.EmphasisRight}}. Numeric columns are compared numerically, and rows whose column is NULL are never written.
+
+{{.EmphasisLeft}}--summary{{.EmphasisRight}} appends a summary row to csv and psv files after the rows written. Columns of integers or floats are summed, and every other column holds the number of its values that aren't NULL. The first column of the summary row holds a label in place of its aggregate, {{.EmphasisLeft}}TOTAL{{.EmphasisRight}} unless another is given with {{.EmphasisLeft}}--summary-label{{.EmphasisRight}}.
 `,
 	Synopsis: []string{
-		"[-f] [-pk {{.LessThan}}field{{.GreaterThan}}] [-schema {{.LessThan}}file{{.GreaterThan}}] [-map {{.LessThan}}file{{.GreaterThan}}] [-continue] [-file-type {{.LessThan}}type{{.GreaterThan}}] {{.LessThan}}table{{.GreaterThan}} {{.LessThan}}file{{.GreaterThan}}",
+		"[-f] [-pk {{.LessThan}}field{{.GreaterThan}}] [-schema {{.LessThan}}file{{.GreaterThan}}] [-map {{.LessThan}}file{{.GreaterThan}}] [-continue] [-file-type {{.LessThan}}type{{.GreaterThan}}] [-typed-header] [--offset {{.LessThan}}n{{.GreaterThan}}] [--no-header] [--invalid-utf8 {{.LessThan}}policy{{.GreaterThan}}] [--where {{.LessThan}}expression{{.GreaterThan}}] [--summary [--summary-label {{.LessThan}}label{{.GreaterThan}}]] {{.LessThan}}table{{.GreaterThan}} {{.LessThan}}file{{.GreaterThan}}",
 	},
 }
 
 type exportOptions struct {
-	tableName   string
-	contOnErr   bool
-	force       bool
-	schFile     string
-	mappingFile string
-	primaryKeys []string
-	src         mvdata.TableDataLocation
-	dest        mvdata.DataLocation
-	srcOptions  interface{}
+	tableName    string
+	contOnErr    bool
+	force        bool
+	schFile      string
+	mappingFile  string
+	primaryKeys  []string
+	src          mvdata.TableDataLocation
+	dest         mvdata.DataLocation
+	srcOptions   interface{}
+	typedHeader  bool
+	noHeader     bool
+	invalidUTF8  csv.InvalidUTF8Policy
+	where        *csv.RowPredicate
+	summaryLabel string
+	columnOrder  []string
+}
+
+var _ mvdata.CsvWriterOptions = exportOptions{}
+
+// defaultSummaryLabel is the label of the summary row written with --summary if --summary-label isn't given
+const defaultSummaryLabel = "TOTAL"
+
+// invalidUTF8Policies maps the values of --invalid-utf8 to the policies they select
+var invalidUTF8Policies = map[string]csv.InvalidUTF8Policy{
+	"error":   csv.InvalidUTF8Error,
+	"replace": csv.InvalidUTF8Replace,
+	"hex":     csv.InvalidUTF8EscapeHex,
 }
 
 func (m exportOptions) checkOverwrite(ctx context.Context, root *doltdb.RootValue, fs filesys.ReadableFS) (bool, error) {
@@ -74,6 +101,36 @@ func (m exportOptions) WritesToTable() bool {
 	return false
 }
 
+// TypedHeader implements mvdata.CsvWriterOptions
+func (m exportOptions) TypedHeader() bool {
+	return m.typedHeader
+}
+
+// NoHeader implements mvdata.CsvWriterOptions
+func (m exportOptions) NoHeader() bool {
+	return m.noHeader
+}
+
+// InvalidUTF8 implements mvdata.CsvWriterOptions
+func (m exportOptions) InvalidUTF8() csv.InvalidUTF8Policy {
+	return m.invalidUTF8
+}
+
+// Where implements mvdata.CsvWriterOptions
+func (m exportOptions) Where() *csv.RowPredicate {
+	return m.where
+}
+
+// SummaryLabel implements mvdata.CsvWriterOptions
+func (m exportOptions) SummaryLabel() string {
+	return m.summaryLabel
+}
+
+// ColumnOrder implements mvdata.CsvWriterOptions
+func (m exportOptions) ColumnOrder() []string {
+	return m.columnOrder
+}
+
 func (m exportOptions) SrcName() string {
 	return m.src.Name
 }
@@ -152,15 +209,71 @@ func parseExportArgs(ap *argparser.ArgParser, commandStr string, args []string)
 	pks := funcitr.MapStrings(strings.Split(val, ","), strings.TrimSpace)
 	pks = funcitr.FilterStrings(pks, func(s string) bool { return s != "" })
 
+	var srcOpts interface{}
+	if apr.Contains(offsetParam) {
+		offset, ok := apr.GetUint(offsetParam)
+		if !ok {
+			return nil, errhand.BuildDError("invalid --%s value, expected a non-negative number of rows", offsetParam).Build()
+		}
+		srcOpts = mvdata.TableReadOptions{Offset: offset}
+	}
+
+	invalidUTF8 := csv.InvalidUTF8Passthrough
+	if policyName, ok := apr.GetValue(invalidUTF8Param); ok {
+		invalidUTF8, ok = invalidUTF8Policies[policyName]
+		if !ok {
+			return nil, errhand.BuildDError("invalid --%s value '%s', expected one of error, replace or hex", invalidUTF8Param, policyName).Build()
+		}
+	}
+
+	var where *csv.RowPredicate
+	if expr, ok := apr.GetValue(whereParam); ok {
+		var err error
+		where, err = csv.ParseRowPredicate(expr)
+		if err != nil {
+			return nil, errhand.BuildDError("invalid --%s value", whereParam).AddCause(err).Build()
+		}
+	}
+
+	var summaryLabel string
+	if apr.Contains(summaryParam) {
+		summaryLabel = defaultSummaryLabel
+		if label, ok := apr.GetValue(summaryLabelParam); ok {
+			if label == "" {
+				return nil, errhand.BuildDError("invalid --%s value, the label can't be empty", summaryLabelParam).Build()
+			}
+			summaryLabel = label
+		}
+	} else if apr.Contains(summaryLabelParam) {
+		return nil, errhand.BuildDError("--%s can only be used with --%s", summaryLabelParam, summaryParam).Build()
+	}
+
+	var columnOrder []string
+	if val, ok := apr.GetValue(columnOrderParam); ok {
+		columnOrder = funcitr.MapStrings(strings.Split(val, ","), strings.TrimSpace)
+		for _, name := range columnOrder {
+			if name == "" {
+				return nil, errhand.BuildDError("invalid --%s value, column names can't be empty", columnOrderParam).Build()
+			}
+		}
+	}
+
 	return &exportOptions{
-		tableName:   tableName,
-		contOnErr:   apr.Contains(contOnErrParam),
-		force:       apr.Contains(forceParam),
-		schFile:     schemaFile,
-		mappingFile: mappingFile,
-		primaryKeys: pks,
-		src:         tableLoc,
-		dest:        fileLoc,
+		tableName:    tableName,
+		contOnErr:    apr.Contains(contOnErrParam),
+		force:        apr.Contains(forceParam),
+		schFile:      schemaFile,
+		mappingFile:  mappingFile,
+		primaryKeys:  pks,
+		src:          tableLoc,
+		dest:         fileLoc,
+		srcOptions:   srcOpts,
+		typedHeader:  apr.Contains(typedHeaderParam),
+		noHeader:     apr.Contains(noHeaderParam),
+		invalidUTF8:  invalidUTF8,
+		where:        where,
+		summaryLabel: summaryLabel,
+		columnOrder:  columnOrder,
 	}, nil
 }
 
@@ -192,6 +305,14 @@ func (cmd ExportCmd) createArgParser() *argparser.ArgParser {
 	ap.SupportsString(mappingFileParam, "m", "mapping_file", "A file that lays out how fields should be mapped from input data to output data.")
 	ap.SupportsString(primaryKeyParam, "pk", "primary_key", "Explicitly define the name of the field in the schema which should be used as the primary key.")
 	ap.SupportsString(fileTypeParam, "", "file_type", "Explicitly define the type of the file if it can't be inferred from the file extension.")
+	ap.SupportsFlag(typedHeaderParam, "", "Include each column's type in the header line of csv and psv output, as name:type.")
+	ap.SupportsString(offsetParam, "", "n", "Skip the first n rows of the table, to resume an interrupted export.")
+	ap.SupportsFlag(noHeaderParam, "", "Leave the header line out of csv and psv output.")
+	ap.SupportsString(invalidUTF8Param, "", "policy", "How string values that aren't valid UTF-8 are written to csv and psv output: error, replace or hex. By default they are written unchanged.")
+	ap.SupportsString(whereParam, "", "expression", "Only write rows satisfying an expression of the form column op value to csv and psv output, where op is one of ==, !=, <, >, <= or >=.")
+	ap.SupportsFlag(summaryParam, "", "Append a summary row to csv and psv output, summing numeric columns and counting the values of others.")
+	ap.SupportsString(summaryLabelParam, "", "label", "The label written in the first column of the summary row. Defaults to "+defaultSummaryLabel+".")
+	ap.SupportsString(columnOrderParam, "", "columns", "A comma separated column order for csv and psv output, such as the header of an earlier export. Listed columns are written in that order, other columns are written after them, and listed columns the table doesn't have are left empty.")
 	return ap
 }
 
@@ -227,6 +348,11 @@ func (cmd ExportCmd) Exec(ctx context.Context, commandStr string, args []string,
 		cli.PrintErrln(color.YellowString("Lines skipped: %d", skipped))
 	}
 	if verr != nil {
+		if fbc, ok := mover.Wr.(mvdata.FlushedByteCounter); ok && fbc.BytesFlushed() > 0 {
+			cli.PrintErrln(color.YellowString("Partial output was written to %s and is incomplete.", exOpts.DestName()))
+		} else if ok {
+			cli.PrintErrln(color.YellowString("No output was written to %s.", exOpts.DestName()))
+		}
 		return commands.HandleVErrAndExitCode(verr, usage)
 	}
 
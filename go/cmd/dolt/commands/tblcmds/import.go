@@ -43,18 +43,28 @@ import (
 )
 
 const (
-	createParam      = "create-table"
-	updateParam      = "update-table"
-	replaceParam     = "replace-table"
-	tableParam       = "table"
-	fileParam        = "file"
-	schemaParam      = "schema"
-	mappingFileParam = "map"
-	forceParam       = "force"
-	contOnErrParam   = "continue"
-	primaryKeyParam  = "pk"
-	fileTypeParam    = "file-type"
-	delimParam       = "delim"
+	createParam            = "create-table"
+	updateParam            = "update-table"
+	replaceParam           = "replace-table"
+	tableParam             = "table"
+	fileParam              = "file"
+	schemaParam            = "schema"
+	mappingFileParam       = "map"
+	forceParam             = "force"
+	contOnErrParam         = "continue"
+	primaryKeyParam        = "pk"
+	fileTypeParam          = "file-type"
+	delimParam             = "delim"
+	quotedEmptyAsNullParam = "quoted-empty-as-null"
+	nullValuesParam        = "null-values"
+	typedHeaderParam       = "typed-header"
+	offsetParam            = "offset"
+	noHeaderParam          = "no-header"
+	invalidUTF8Param       = "invalid-utf8"
+	whereParam             = "where"
+	summaryParam           = "summary"
+	summaryLabelParam      = "summary-label"
+	columnOrderParam       = "column-order"
 )
 
 var importDocs = cli.CommandDocumentationContent{
@@ -76,7 +86,9 @@ A mapping file can be used to map fields between the file being imported and the
 ` + schcmds.MappingFileHelp +
 
 		`
-In create, update, and replace scenarios the file's extension is used to infer the type of the file.  If a file does not have the expected extension then the {{.EmphasisLeft}}--file-type{{.EmphasisRight}} parameter should be used to explicitly define the format of the file in one of the supported formats (csv, psv, json, xlsx).  For files separated by a delimiter other than a ',' (type csv) or a '|' (type psv), the --delim parameter can be used to specify a delimeter`,
+In create, update, and replace scenarios the file's extension is used to infer the type of the file.  If a file does not have the expected extension then the {{.EmphasisLeft}}--file-type{{.EmphasisRight}} parameter should be used to explicitly define the format of the file in one of the supported formats (csv, psv, json, xlsx).  For files separated by a delimiter other than a ',' (type csv) or a '|' (type psv), the --delim parameter can be used to specify a delimeter.
+
+When importing csv files, unquoted empty fields are imported as NULL and quoted empty fields ({{.EmphasisLeft}}""{{.EmphasisRight}}) are imported as empty strings. Use the {{.EmphasisLeft}}--quoted-empty-as-null{{.EmphasisRight}} flag to import both as NULL. Other unquoted values can be imported as NULL by listing them with {{.EmphasisLeft}}--null-values{{.EmphasisRight}}, e.g. {{.EmphasisLeft}}--null-values '\N,NULL'{{.EmphasisRight}}.`,
 
 	Synopsis: []string{
 		"-c [-f] [--pk {{.LessThan}}field{{.GreaterThan}}] [--schema {{.LessThan}}file{{.GreaterThan}}] [--map {{.LessThan}}file{{.GreaterThan}}] [--continue] [--file-type {{.LessThan}}type{{.GreaterThan}}] {{.LessThan}}table{{.GreaterThan}} {{.LessThan}}file{{.GreaterThan}}",
@@ -161,6 +173,12 @@ func getImportMoveOptions(ctx context.Context, apr *argparser.ArgParseResults, d
 	fType, _ := apr.GetValue(fileTypeParam)
 	srcLoc := mvdata.NewDataLocation(path, fType)
 	delim, hasDelim := apr.GetValue(delimParam)
+	quotedEmptyAsNull := apr.Contains(quotedEmptyAsNullParam)
+	nullValues, hasNullValues := apr.GetValue(nullValuesParam)
+	var nullValueList []string
+	if hasNullValues {
+		nullValueList = strings.Split(nullValues, ",")
+	}
 
 	schemaFile, _ := apr.GetValue(schemaParam)
 	force := apr.Contains(forceParam)
@@ -185,7 +203,9 @@ func getImportMoveOptions(ctx context.Context, apr *argparser.ArgParseResults, d
 				srcLoc = val
 			}
 
-			srcOpts = mvdata.CsvOptions{Delim: delim}
+			srcOpts = mvdata.CsvOptions{Delim: delim, QuotedEmptyAsNull: quotedEmptyAsNull, NullValues: nullValueList}
+		} else if quotedEmptyAsNull || hasNullValues {
+			srcOpts = mvdata.CsvOptions{QuotedEmptyAsNull: quotedEmptyAsNull, NullValues: nullValueList}
 		}
 
 		if val.Format == mvdata.XlsxFile {
@@ -201,8 +221,8 @@ func getImportMoveOptions(ctx context.Context, apr *argparser.ArgParseResults, d
 			srcLoc = val
 		}
 
-		if hasDelim {
-			srcOpts = mvdata.CsvOptions{Delim: delim}
+		if hasDelim || quotedEmptyAsNull || hasNullValues {
+			srcOpts = mvdata.CsvOptions{Delim: delim, QuotedEmptyAsNull: quotedEmptyAsNull, NullValues: nullValueList}
 		}
 	}
 
@@ -403,6 +423,8 @@ func createArgParser() *argparser.ArgParser {
 	ap.SupportsString(primaryKeyParam, "pk", "primary_key", "Explicitly define the name of the field in the schema which should be used as the primary key.")
 	ap.SupportsString(fileTypeParam, "", "file_type", "Explicitly define the type of the file if it can't be inferred from the file extension.")
 	ap.SupportsString(delimParam, "", "delimiter", "Specify a delimeter for a csv style file with a non-comma delimiter.")
+	ap.SupportsString(nullValuesParam, "", "null_values", "A comma separated list of strings, such as \\N or NULL, that represent NULL when they appear as unquoted csv fields.")
+	ap.SupportsFlag(quotedEmptyAsNullParam, "", "Import quoted empty csv fields as NULL rather than as empty strings. Unquoted empty fields are always imported as NULL.")
 	return ap
 }
 
@@ -0,0 +1,253 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/apache/arrow/go/arrow"
+	"github.com/apache/arrow/go/arrow/array"
+	"github.com/apache/arrow/go/arrow/memory"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/row"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/store/diff"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+const (
+	// ArrowDiffTypeField is the name of the field of a diff record holding each row's change type
+	ArrowDiffTypeField = "diff_type"
+
+	arrowDiffTypeAdded    = "added"
+	arrowDiffTypeModified = "modified"
+	arrowDiffTypeRemoved  = "removed"
+
+	// arrowPollTimeout is how long WriteArrowRecords waits on each call to GetDiffs
+	arrowPollTimeout = 100 * time.Millisecond
+)
+
+var ErrInvalidRecordSize = errors.New("record size must be greater than zero")
+
+// ArrowRecordFunc is called by WriteArrowRecords with each record it builds. The record is released when the
+// function returns, so it must be retained to be used afterwards.
+type ArrowRecordFunc func(rec array.Record) error
+
+// ArrowDiffSchema returns the Arrow schema of the records WriteArrowRecords builds for a table with schema |sch|.
+// Records have a field for each primary key column, holding the key of the changed row, then a diff_type field
+// holding one of "added", "modified" or "removed", then from_ and to_ fields for each non primary key column,
+// holding the old and new values. Keyless tables have no key fields. Int, uint, float, bool and string columns
+// keep their types; columns of any other type are formatted as strings. Every field is nullable.
+func ArrowDiffSchema(sch schema.Schema) *arrow.Schema {
+	var fields []arrow.Field
+	_ = sch.GetPKCols().Iter(func(tag uint64, col schema.Column) (stop bool, err error) {
+		fields = append(fields, arrow.Field{Name: col.Name, Type: arrowType(col), Nullable: true})
+		return false, nil
+	})
+
+	fields = append(fields, arrow.Field{Name: ArrowDiffTypeField, Type: arrow.BinaryTypes.String, Nullable: true})
+
+	_ = sch.GetNonPKCols().Iter(func(tag uint64, col schema.Column) (stop bool, err error) {
+		fields = append(fields,
+			arrow.Field{Name: From + "_" + col.Name, Type: arrowType(col), Nullable: true},
+			arrow.Field{Name: To + "_" + col.Name, Type: arrowType(col), Nullable: true})
+		return false, nil
+	})
+
+	return arrow.NewSchema(fields, nil)
+}
+
+func arrowType(col schema.Column) arrow.DataType {
+	switch col.Kind {
+	case types.IntKind:
+		return arrow.PrimitiveTypes.Int64
+	case types.UintKind:
+		return arrow.PrimitiveTypes.Uint64
+	case types.FloatKind:
+		return arrow.PrimitiveTypes.Float64
+	case types.BoolKind:
+		return arrow.FixedWidthTypes.Boolean
+	default:
+		return arrow.BinaryTypes.String
+	}
+}
+
+// WriteArrowRecords reads every difference from |rd|, which must already be started, and calls |cb| with Arrow
+// records of up to |recordSize| differences each, allocated from |mem|. The records have the schema returned by
+// ArrowDiffSchema for |sch|, which must be the schema of the diffed table. For keyless tables each added or
+// removed copy of a row is its own record row. |rd| is not closed.
+func WriteArrowRecords(ctx context.Context, rd RowDiffer, sch schema.Schema, recordSize int, mem memory.Allocator, cb ArrowRecordFunc) error {
+	if recordSize <= 0 {
+		return ErrInvalidRecordSize
+	}
+
+	bld := array.NewRecordBuilder(mem, ArrowDiffSchema(sch))
+	defer bld.Release()
+
+	rows := 0
+	flush := func() error {
+		rec := bld.NewRecord()
+		defer rec.Release()
+		rows = 0
+		return cb(rec)
+	}
+
+	for {
+		diffs, more, err := rd.GetDiffs(recordSize-rows, arrowPollTimeout)
+		if err != nil {
+			return err
+		}
+
+		for _, d := range diffs {
+			err = appendArrowDiff(bld, sch, d)
+			if err != nil {
+				return err
+			}
+			rows++
+		}
+
+		if rows == recordSize || (!more && rows > 0) {
+			err = flush()
+			if err != nil {
+				return err
+			}
+		}
+
+		if !more {
+			return nil
+		}
+
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+	}
+}
+
+// appendArrowDiff appends |d| to the fields of |bld| as laid out by ArrowDiffSchema.
+func appendArrowDiff(bld *array.RecordBuilder, sch schema.Schema, d *diff.Difference) error {
+	diffType, err := diffTypeName(d.ChangeType)
+	if err != nil {
+		return err
+	}
+
+	oldRow, err := diffSideRow(sch, d.KeyValue, d.OldValue)
+	if err != nil {
+		return err
+	}
+
+	newRow, err := diffSideRow(sch, d.KeyValue, d.NewValue)
+	if err != nil {
+		return err
+	}
+
+	keyRow := newRow
+	if keyRow == nil {
+		keyRow = oldRow
+	}
+
+	i := 0
+	err = sch.GetPKCols().Iter(func(tag uint64, col schema.Column) (stop bool, err error) {
+		err = appendArrowValue(bld.Field(i), col, keyRow)
+		i++
+		return err != nil, err
+	})
+	if err != nil {
+		return err
+	}
+
+	bld.Field(i).(*array.StringBuilder).Append(diffType)
+	i++
+
+	err = sch.GetNonPKCols().Iter(func(tag uint64, col schema.Column) (stop bool, err error) {
+		err = appendArrowValue(bld.Field(i), col, oldRow)
+		if err != nil {
+			return true, err
+		}
+
+		err = appendArrowValue(bld.Field(i+1), col, newRow)
+		i += 2
+		return err != nil, err
+	})
+
+	return err
+}
+
+// diffTypeName returns the name of |ct| written in the diff_type field of a diff record.
+func diffTypeName(ct types.DiffChangeType) (string, error) {
+	switch ct {
+	case types.DiffChangeAdded:
+		return arrowDiffTypeAdded, nil
+	case types.DiffChangeRemoved:
+		return arrowDiffTypeRemoved, nil
+	case types.DiffChangeModified:
+		return arrowDiffTypeModified, nil
+	default:
+		return "", fmt.Errorf("unexpected DiffChange type %d", ct)
+	}
+}
+
+// diffSideRow returns the row with key |key| and value |val|, or nil if |val| is nil because the row doesn't exist
+// on that side of the diff.
+func diffSideRow(sch schema.Schema, key, val types.Value) (row.Row, error) {
+	if val == nil {
+		return nil, nil
+	}
+	return row.FromNoms(sch, key.(types.Tuple), val.(types.Tuple))
+}
+
+// appendArrowValue appends the value of |col| in |r| to |fb|, or a null if |r| is nil or has no value for |col|.
+func appendArrowValue(fb array.Builder, col schema.Column, r row.Row) error {
+	var val types.Value
+	if r != nil {
+		val, _ = r.GetColVal(col.Tag)
+	}
+
+	if types.IsNull(val) {
+		fb.AppendNull()
+		return nil
+	}
+
+	switch b := fb.(type) {
+	case *array.Int64Builder:
+		b.Append(int64(val.(types.Int)))
+	case *array.Uint64Builder:
+		b.Append(uint64(val.(types.Uint)))
+	case *array.Float64Builder:
+		b.Append(float64(val.(types.Float)))
+	case *array.BooleanBuilder:
+		b.Append(bool(val.(types.Bool)))
+	case *array.StringBuilder:
+		if s, ok := val.(types.String); ok {
+			b.Append(string(s))
+			return nil
+		}
+
+		str, err := col.TypeInfo.FormatValue(val)
+		if err != nil {
+			return err
+		} else if str == nil {
+			b.AppendNull()
+		} else {
+			b.Append(*str)
+		}
+	default:
+		return fmt.Errorf("unexpected arrow builder %T for column %s", fb, col.Name)
+	}
+
+	return nil
+}
@@ -0,0 +1,141 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"context"
+	"testing"
+
+	"github.com/apache/arrow/go/arrow"
+	"github.com/apache/arrow/go/arrow/array"
+	"github.com/apache/arrow/go/arrow/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// arrowRecordRows returns the rows of the records written by WriteArrowRecords as slices of Go values, with nil
+// for nulls, along with the number of rows in each record.
+func arrowRecordRows(t *testing.T, sch *arrow.Schema, recs []array.Record) ([][]interface{}, []int64) {
+	var rows [][]interface{}
+	var sizes []int64
+	for _, rec := range recs {
+		require.True(t, sch.Equal(rec.Schema()))
+		sizes = append(sizes, rec.NumRows())
+
+		for i := 0; i < int(rec.NumRows()); i++ {
+			var r []interface{}
+			for _, col := range rec.Columns() {
+				if col.IsNull(i) {
+					r = append(r, nil)
+					continue
+				}
+
+				switch arr := col.(type) {
+				case *array.Int64:
+					r = append(r, arr.Value(i))
+				case *array.String:
+					r = append(r, arr.Value(i))
+				default:
+					t.Fatalf("unexpected array %T", col)
+				}
+			}
+			rows = append(rows, r)
+		}
+	}
+	return rows, sizes
+}
+
+func TestWriteArrowRecords(t *testing.T) {
+	ctx := context.Background()
+	vrw := types.NewMemoryValueStore()
+
+	tests := []struct {
+		name      string
+		sch       schema.Schema
+		rd        RowDiffer
+		from, to  types.Map
+		expFields []string
+		expRows   [][]interface{}
+		expSizes  []int64
+	}{
+		{
+			name:      "keyed",
+			sch:       testKeyedSch,
+			rd:        NewRowDiffer(ctx, testKeyedSch, testKeyedSch, 8),
+			from:      keyedTestMap(t, vrw, 1, 1, 2, 2, 4, 4),
+			to:        keyedTestMap(t, vrw, 1, 1, 2, 5, 3, 3),
+			expFields: []string{"pk", "diff_type", "from_val", "to_val"},
+			expRows: [][]interface{}{
+				{int64(2), "modified", int64(2), int64(5)},
+				{int64(3), "added", nil, int64(3)},
+				{int64(4), "removed", int64(4), nil},
+			},
+			expSizes: []int64{2, 1},
+		},
+		{
+			name:      "keyless",
+			sch:       testKeylessSch,
+			rd:        NewRowDiffer(ctx, testKeylessSch, testKeylessSch, 8),
+			from:      keylessTestMap(t, vrw, 7, 1, 8, 2),
+			to:        keylessTestMap(t, vrw, 7, 3),
+			expFields: []string{"diff_type", "from_val", "to_val"},
+			expRows: [][]interface{}{
+				{"added", nil, int64(7)},
+				{"added", nil, int64(7)},
+				{"removed", int64(8), nil},
+				{"removed", int64(8), nil},
+			},
+			expSizes: []int64{2, 2},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			arrowSch := ArrowDiffSchema(test.sch)
+			var fields []string
+			for _, f := range arrowSch.Fields() {
+				fields = append(fields, f.Name)
+			}
+			assert.Equal(t, test.expFields, fields)
+
+			mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+			defer mem.AssertSize(t, 0)
+
+			var recs []array.Record
+			test.rd.Start(ctx, test.from, test.to)
+			defer test.rd.Close()
+			err := WriteArrowRecords(ctx, test.rd, test.sch, 2, mem, func(rec array.Record) error {
+				rec.Retain()
+				recs = append(recs, rec)
+				return nil
+			})
+			require.NoError(t, err)
+
+			rows, sizes := arrowRecordRows(t, arrowSch, recs)
+			assert.Equal(t, test.expRows, rows)
+			assert.Equal(t, test.expSizes, sizes)
+
+			for _, rec := range recs {
+				rec.Release()
+			}
+		})
+	}
+
+	err := WriteArrowRecords(ctx, NewRowDiffer(ctx, testKeyedSch, testKeyedSch, 8), testKeyedSch, 0, memory.NewGoAllocator(), nil)
+	assert.Equal(t, ErrInvalidRecordSize, err)
+}
@@ -39,22 +39,133 @@ type RowDiffer interface {
 	Close() error
 }
 
-func NewRowDiffer(ctx context.Context, fromSch, toSch schema.Schema, buf int) RowDiffer {
-	ad := NewAsyncDiffer(buf)
-
+// NewRowDiffer returns a RowDiffer for the rows of a table whose schema changed from |fromSch| to |toSch|. Options
+// that change the differences produced are applied in the order given, each to the differences produced by the
+// ones before it.
+func NewRowDiffer(ctx context.Context, fromSch, toSch schema.Schema, buf int, opts ...RowDifferOption) RowDiffer {
 	// assumes no PK changes
 	// mixed diffing of keyless and pk tables no supported
-	if schema.IsKeyless(fromSch) || schema.IsKeyless(toSch) {
-		return &keylessDiffer{AsyncDiffer: ad}
+	o := &rowDifferOptions{keyless: schema.IsKeyless(fromSch) || schema.IsKeyless(toSch)}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	ad := NewAsyncDiffer(buf)
+	if o.descend != nil {
+		ad.descend = o.descend
+	}
+	if o.formatKey != nil {
+		ad.formatKey = o.formatKey
+	}
+	ad.keepPartial = o.keepPartial
+	ad.budget = o.budget
+	ad.buffers += o.buffers
+	ad.extraBuffered = o.extraBuffered
+
+	if o.diffFn != nil {
+		ad.diffFn = o.diffFn
+	}
+	for _, pg := range o.pauses {
+		ad.diffFn = pg.wrap(ad.diffFn)
+	}
+	for _, wrap := range o.mapDiffWrappers {
+		ad.diffFn = wrap(ad.diffFn)
+	}
+	for _, wrap := range o.wrappers {
+		ad.diffFn = wrap(ad.diffFn)
+	}
+
+	if o.keyless {
+		if o.workers > 0 {
+			// the conversion workers add a buffer of converted differences, and hold those awaiting conversion
+			ad.buffers++
+			ad.extraBuffered += o.workers * conversionWindowPerWorker
+		}
+		return &keylessDiffer{AsyncDiffer: ad, workers: o.workers}
 	}
 
 	return ad
 }
 
+// RowDifferOption configures a RowDiffer created by NewRowDiffer.
+type RowDifferOption func(o *rowDifferOptions)
+
+type rowDifferOptions struct {
+	// keyless is set when either schema is keyless, before any options are applied
+	keyless bool
+
+	descend     diff.ShouldDescFunc
+	formatKey   KeyFormatter
+	keepPartial bool
+	workers     int
+
+	budget *BufferBudget
+	// buffers is the number of buffers of the RowDiffer's buffer size that the wrappers add
+	buffers int
+	// extraBuffered is the number of differences that the wrappers hold besides their buffers
+	extraBuffered int
+
+	// diffFn, if non-nil, replaces the map diff
+	diffFn mapDiffFunc
+	// pauses gate the map diff before anything else is applied to it
+	pauses []*pauseGate
+	// mapDiffWrappers are applied to the gated map diff in order, before wrappers
+	mapDiffWrappers []func(mapDiffFunc) mapDiffFunc
+	// wrappers are applied to the map diff in order
+	wrappers []func(mapDiffFunc) mapDiffFunc
+}
+
+// wrap adds |w| to the functions applied to the map diff. |w| is expected to buffer the differences it reads with a
+// buffer of the RowDiffer's buffer size, as pipeDiffs does.
+func (o *rowDifferOptions) wrap(w func(mapDiffFunc) mapDiffFunc) {
+	o.wrapUnbuffered(w)
+	o.buffers++
+}
+
+// wrapUnbuffered adds |w| to the functions applied to the map diff. |w| must send the differences it reads straight
+// to its output rather than buffering them.
+func (o *rowDifferOptions) wrapUnbuffered(w func(mapDiffFunc) mapDiffFunc) {
+	o.wrappers = append(o.wrappers, w)
+}
+
+// wrapMapDiff adds |w| to the functions applied to the map diff before those added by wrap and wrapUnbuffered,
+// whatever order the options were given in, so that |w| can run the map diff more than once without running the
+// other options again. Unlike wrap, it doesn't count a buffer for |w|.
+func (o *rowDifferOptions) wrapMapDiff(w func(mapDiffFunc) mapDiffFunc) {
+	o.mapDiffWrappers = append(o.mapDiffWrappers, w)
+}
+
+// WithDescend makes a RowDiffer descend into values for which |descend| returns true, rather than reporting them as
+// modified. Differences found beneath a row carry the row's key in RootKeyValue, and the path of the change within
+// the row in NestedPath().
+func WithDescend(descend diff.ShouldDescFunc) RowDifferOption {
+	return func(o *rowDifferOptions) {
+		o.descend = descend
+	}
+}
+
+// mapDiffFunc sends the differences between |from| and |to| on |out|. It must not close |out|.
+type mapDiffFunc func(ctx context.Context, from, to types.Map, out chan<- diff.Difference) error
+
 // todo: make package private
 type AsyncDiffer struct {
 	diffChan   chan diff.Difference
 	bufferSize int
+	descend    diff.ShouldDescFunc
+	diffFn     mapDiffFunc
+
+	// formatKey renders keys in error messages
+	formatKey KeyFormatter
+
+	// budget, if non-nil, is the BufferBudget that Start reserves the buffers of the diff from
+	budget *BufferBudget
+	// buffers is the number of buffers of size bufferSize used by the diff, including diffChan
+	buffers int
+	// extraBuffered is the number of differences held by the diff besides its buffers
+	extraBuffered int
+
+	// keepPartial is set when GetDiffs returns the differences collected so far if the diff is cancelled
+	keepPartial bool
 
 	eg       *errgroup.Group
 	egCtx    context.Context
@@ -65,13 +176,17 @@ var _ RowDiffer = &AsyncDiffer{}
 
 // todo: make package private once dolthub is migrated
 func NewAsyncDiffer(bufferedDiffs int) *AsyncDiffer {
-	return &AsyncDiffer{
-		make(chan diff.Difference, bufferedDiffs),
-		bufferedDiffs,
-		nil,
-		context.Background(),
-		func() {},
+	ad := &AsyncDiffer{
+		diffChan:   make(chan diff.Difference, bufferedDiffs),
+		bufferSize: bufferedDiffs,
+		buffers:    1,
+		descend:    tableDontDescendLists,
+		formatKey:  DefaultKeyFormatter,
+		egCtx:      context.Background(),
+		egCancel:   func() {},
 	}
+	ad.diffFn = ad.diffMaps
+	return ad
 }
 
 func tableDontDescendLists(v1, v2 types.Value) bool {
@@ -79,17 +194,104 @@ func tableDontDescendLists(v1, v2 types.Value) bool {
 	return !types.IsPrimitiveKind(kind) && kind != types.TupleKind && kind == v2.Kind() && kind != types.RefKind
 }
 
+// Start implements RowDiffer. If |ad| has a BufferBudget, Start blocks until its buffers are reserved from it or
+// |ctx| is done.
 func (ad *AsyncDiffer) Start(ctx context.Context, from, to types.Map) {
 	ad.eg, ad.egCtx = errgroup.WithContext(ctx)
+	size, release, resErr := ad.bufferReservation(ad.egCtx)
+	if ad.budget != nil {
+		ad.diffChan = make(chan diff.Difference, size)
+	}
+
 	ad.egCancel = async.GoWithCancel(ad.egCtx, ad.eg, func(ctx context.Context) (err error) {
 		defer close(ad.diffChan)
+		if resErr != nil {
+			return resErr
+		}
+		defer release()
+		defer func() {
+			if r := recover(); r != nil {
+				err = fmt.Errorf("panic in diff.Diff: %v", r)
+			}
+		}()
+		return ad.diffFn(ctx, from, to, ad.diffChan)
+	})
+}
+
+func (ad *AsyncDiffer) diffMaps(ctx context.Context, from, to types.Map, out chan<- diff.Difference) error {
+	return diff.Diff(ctx, from, to, out, true, ad.descend)
+}
+
+// pipeDiffs runs |diffFn| on its own goroutine and calls |f| with each difference it produces. |f| forwards zero
+// or more differences to |out| by calling |send|. Up to cap(|out|) differences are buffered between |diffFn| and |f|.
+func pipeDiffs(ctx context.Context, from, to types.Map, out chan<- diff.Difference, diffFn mapDiffFunc, f func(d diff.Difference, send func(diff.Difference) error) error) error {
+	return runDiffPipe(ctx, from, to, out, diffFn, cap(out), 0, nil, f)
+}
+
+// pipeDiffsUnbuffered is pipeDiffs without a buffer, so that |diffFn| blocks on each difference it produces until
+// |f| has returned from the one before.
+func pipeDiffsUnbuffered(ctx context.Context, from, to types.Map, out chan<- diff.Difference, diffFn mapDiffFunc, f func(d diff.Difference, send func(diff.Difference) error) error) error {
+	return runDiffPipe(ctx, from, to, out, diffFn, 0, 0, nil, f)
+}
+
+// pipeDiffsWithTicks is pipeDiffs that also calls |tick| every |interval| until |diffFn| has produced its last
+// difference. |tick| is called from the goroutine that calls |f|.
+func pipeDiffsWithTicks(ctx context.Context, from, to types.Map, out chan<- diff.Difference, diffFn mapDiffFunc, interval time.Duration, tick func(), f func(d diff.Difference, send func(diff.Difference) error) error) error {
+	return runDiffPipe(ctx, from, to, out, diffFn, cap(out), interval, tick, f)
+}
+
+func runDiffPipe(ctx context.Context, from, to types.Map, out chan<- diff.Difference, diffFn mapDiffFunc, buffered int, interval time.Duration, tick func(), f func(d diff.Difference, send func(diff.Difference) error) error) error {
+	eg, ctx := errgroup.WithContext(ctx)
+	in := make(chan diff.Difference, buffered)
+
+	eg.Go(func() (err error) {
+		defer close(in)
 		defer func() {
 			if r := recover(); r != nil {
 				err = fmt.Errorf("panic in diff.Diff: %v", r)
 			}
 		}()
-		return diff.Diff(ctx, from, to, ad.diffChan, true, tableDontDescendLists)
+		return diffFn(ctx, from, to, in)
 	})
+
+	send := func(d diff.Difference) error {
+		select {
+		case out <- d:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	eg.Go(func() error {
+		var ticks <-chan time.Time
+		if tick != nil {
+			ticker := time.NewTicker(interval)
+			defer ticker.Stop()
+			ticks = ticker.C
+		}
+
+		for {
+			select {
+			case d, ok := <-in:
+				if !ok {
+					return nil
+				}
+
+				if err := f(d, send); err != nil {
+					return err
+				}
+
+			case <-ticks:
+				tick()
+
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+	})
+
+	return eg.Wait()
 }
 
 func (ad *AsyncDiffer) Close() error {
@@ -97,8 +299,11 @@ func (ad *AsyncDiffer) Close() error {
 	return ad.eg.Wait()
 }
 
+// minDiffsCap is the smallest capacity allocated for the slice returned by GetDiffs
+const minDiffsCap = 16
+
 func (ad *AsyncDiffer) GetDiffs(numDiffs int, timeout time.Duration) ([]*diff.Difference, bool, error) {
-	diffs := make([]*diff.Difference, 0, ad.bufferSize)
+	diffs := make([]*diff.Difference, 0, ad.initialDiffsCap(numDiffs))
 	timeoutChan := time.After(timeout)
 	for {
 		select {
@@ -109,21 +314,50 @@ func (ad *AsyncDiffer) GetDiffs(numDiffs int, timeout time.Duration) ([]*diff.Di
 					return diffs, true, nil
 				}
 			} else {
-				return diffs, false, ad.eg.Wait()
+				return diffs, false, ad.incompleteErr(ad.eg.Wait())
 			}
 		case <-timeoutChan:
 			return diffs, true, nil
 		case <-ad.egCtx.Done():
-			return nil, false, ad.eg.Wait()
+			err := ad.eg.Wait()
+			if ad.keepPartial {
+				return ad.drainPartial(diffs, numDiffs), false, ad.incompleteErr(err)
+			}
+			return nil, false, err
 		}
 	}
 }
 
+// initialDiffsCap returns the capacity to allocate for a GetDiffs result. Rather than allocating for a full buffer,
+// which wastes memory for sparse diffs, it allocates for the differences that are already buffered, bounded by
+// |numDiffs|, and lets append grow the slice if more arrive before the timeout.
+func (ad *AsyncDiffer) initialDiffsCap(numDiffs int) int {
+	c := len(ad.diffChan)
+	if c < minDiffsCap {
+		c = minDiffsCap
+	}
+
+	if numDiffs > 0 && c > numDiffs {
+		c = numDiffs
+	}
+
+	return c
+}
+
 type keylessDiffer struct {
 	*AsyncDiffer
 
 	df         diff.Difference
 	copiesLeft uint64
+
+	// workers is the number of goroutines converting differences, or zero if GetDiffs converts them itself
+	workers int
+	// converted, if non-nil, receives differences converted by the conversion workers
+	converted chan keylessConversion
+
+	// distinctChanges and cardinalityDelta count the differences converted and the copies they expanded to
+	distinctChanges  uint64
+	cardinalityDelta uint64
 }
 
 var _ RowDiffer = &keylessDiffer{}
@@ -136,7 +370,9 @@ func (kd *keylessDiffer) GetDiffs(numDiffs int, timeout time.Duration) (diffs []
 	for {
 		// first populate |diffs| with copies of |kd.df|
 		for (idx < numDiffs) && (kd.copiesLeft > 0) {
-			diffs[idx] = &kd.df
+			// each copy gets its own Difference, as |kd.df| is overwritten by the next conversion
+			df := kd.df
+			diffs[idx] = &df
 
 			idx++
 			kd.copiesLeft--
@@ -149,28 +385,62 @@ func (kd *keylessDiffer) GetDiffs(numDiffs int, timeout time.Duration) (diffs []
 		var d diff.Difference
 		select {
 		case <-timeoutChan:
-			return diffs, true, nil
+			return diffs[:idx], true, nil
 
 		case <-kd.egCtx.Done():
-			return nil, false, kd.eg.Wait()
+			err = kd.eg.Wait()
+			if kd.keepPartial {
+				return diffs[:idx], false, kd.incompleteErr(err)
+			}
+			return nil, false, err
 
-		case d, more = <-kd.diffChan:
+		case d, more = <-kd.rawDiffs():
 			if !more {
-				return diffs[:idx], more, nil
+				if kd.keepPartial {
+					err = kd.incompleteErr(kd.eg.Wait())
+				}
+				return diffs[:idx], more, err
 			}
 
-			kd.df, kd.copiesLeft, err = convertDiff(d)
+			var card uint64
+			kd.df, card, err = convertDiff(d, kd.formatKey)
 			if err != nil {
 				return nil, false, err
 			}
+			kd.setCopies(card)
+
+		case c, ok := <-kd.converted:
+			if !ok {
+				return diffs[:idx], false, kd.incompleteErr(kd.eg.Wait())
+			}
+
+			kd.df = c.df
+			kd.setCopies(c.card)
 		}
 	}
 
 }
 
+// ExpandKeylessDiff expands a difference between keyless rows into one difference per added or removed copy of
+// the row, as reported by the RowDiffer for keyless tables. Modifications are converted to additions or removals
+// according to the change in the row's cardinality.
+func ExpandKeylessDiff(df diff.Difference) ([]diff.Difference, error) {
+	df, card, err := convertDiff(df, DefaultKeyFormatter)
+	if err != nil {
+		return nil, err
+	}
+
+	expanded := make([]diff.Difference, card)
+	for i := range expanded {
+		expanded[i] = df
+	}
+
+	return expanded, nil
+}
+
 // convertDiff reports the cardinality of a change,
 // and converts updates to adds or deletes
-func convertDiff(df diff.Difference) (diff.Difference, uint64, error) {
+func convertDiff(df diff.Difference, formatKey KeyFormatter) (diff.Difference, uint64, error) {
 	var oldCard uint64
 	if df.OldValue != nil {
 		v, err := df.OldValue.(types.Tuple).Get(row.KeylessCardinalityValIdx)
@@ -207,7 +477,11 @@ func convertDiff(df diff.Difference) (diff.Difference, uint64, error) {
 			df.NewValue = nil
 			return df, uint64(-delta), nil
 		} else {
-			panic(fmt.Sprintf("diff with delta = 0 for key: %s", df.KeyValue.HumanReadableString()))
+			key, err := formatKey(df.KeyValue)
+			if err != nil {
+				return df, 0, err
+			}
+			return df, 0, fmt.Errorf("diff with delta = 0 for key: %s", key)
 		}
 	default:
 		return df, 0, fmt.Errorf("unexpected DiffChange type %d", df.ChangeType)
@@ -0,0 +1,377 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/store/diff"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+const (
+	testPkTag  = uint64(0)
+	testValTag = uint64(1)
+)
+
+var testKeyedSch = schema.MustSchemaFromCols(mustColColl(
+	schema.NewColumn("pk", testPkTag, types.IntKind, true, schema.NotNullConstraint{}),
+	schema.NewColumn("val", testValTag, types.IntKind, false),
+))
+
+var testKeylessSch = schema.UnkeyedSchemaFromCols(mustColColl(
+	schema.NewColumn("val", testValTag, types.IntKind, false),
+))
+
+func mustColColl(cols ...schema.Column) *schema.ColCollection {
+	cc, err := schema.NewColCollection(cols...)
+	if err != nil {
+		panic(err)
+	}
+	return cc
+}
+
+// keyedTestMap builds a row map for |testKeyedSch| from pairs of (pk, val).
+func keyedTestMap(t testing.TB, vrw types.ValueReadWriter, pkVals ...int) types.Map {
+	require.True(t, len(pkVals)%2 == 0)
+
+	kvs := make([]types.Value, 0, len(pkVals))
+	for i := 0; i < len(pkVals); i += 2 {
+		k, err := types.NewTuple(vrw.Format(), types.Uint(testPkTag), types.Int(pkVals[i]))
+		require.NoError(t, err)
+		v, err := types.NewTuple(vrw.Format(), types.Uint(testValTag), types.Int(pkVals[i+1]))
+		require.NoError(t, err)
+		kvs = append(kvs, k, v)
+	}
+
+	m, err := types.NewMap(context.Background(), vrw, kvs...)
+	require.NoError(t, err)
+	return m
+}
+
+// keylessTestMap builds a row map for |testKeylessSch| from pairs of (val, cardinality).
+func keylessTestMap(t testing.TB, vrw types.ValueReadWriter, valCards ...int) types.Map {
+	require.True(t, len(valCards)%2 == 0)
+
+	kvs := make([]types.Value, 0, len(valCards))
+	for i := 0; i < len(valCards); i += 2 {
+		k, err := types.NewTuple(vrw.Format(), types.Uint(schema.KeylessRowIdTag), types.Int(valCards[i]))
+		require.NoError(t, err)
+		v, err := types.NewTuple(vrw.Format(),
+			types.Uint(schema.KeylessRowCardinalityTag), types.Uint(valCards[i+1]),
+			types.Uint(testValTag), types.Int(valCards[i]))
+		require.NoError(t, err)
+		kvs = append(kvs, k, v)
+	}
+
+	m, err := types.NewMap(context.Background(), vrw, kvs...)
+	require.NoError(t, err)
+	return m
+}
+
+// drainDiffs reads every difference from a started RowDiffer and closes it.
+func drainDiffs(t *testing.T, rd RowDiffer) []*diff.Difference {
+	var all []*diff.Difference
+	for {
+		diffs, more, err := rd.GetDiffs(16, time.Second)
+		require.NoError(t, err)
+		all = append(all, diffs...)
+		if !more {
+			break
+		}
+	}
+	require.NoError(t, rd.Close())
+	return all
+}
+
+func TestDeepRowDifferReportsNestedPath(t *testing.T) {
+	ctx := context.Background()
+	vrw := types.NewMemoryValueStore()
+	nbf := vrw.Format()
+
+	key, err := types.NewTuple(nbf, types.Uint(testPkTag), types.Int(1))
+	require.NoError(t, err)
+	fromVal, err := types.NewMap(ctx, vrw, types.String("x"), types.Int(1), types.String("y"), types.Int(2))
+	require.NoError(t, err)
+	toVal, err := types.NewMap(ctx, vrw, types.String("x"), types.Int(1), types.String("y"), types.Int(3))
+	require.NoError(t, err)
+
+	from, err := types.NewMap(ctx, vrw, key, fromVal)
+	require.NoError(t, err)
+	to, err := types.NewMap(ctx, vrw, key, toVal)
+	require.NoError(t, err)
+
+	rd := NewRowDiffer(ctx, testKeyedSch, testKeyedSch, 8, WithDescend(diff.ShouldDescend))
+	rd.Start(ctx, from, to)
+	diffs := drainDiffs(t, rd)
+
+	require.Len(t, diffs, 1)
+	d := diffs[0]
+	assert.Equal(t, types.DiffChangeModified, d.ChangeType)
+	assert.Equal(t, 2, d.Depth())
+	assert.Equal(t, `["y"]`, d.NestedPath().String())
+	assert.True(t, key.Equals(d.RootKeyValue))
+	assert.Equal(t, types.String("y"), d.KeyValue)
+	assert.Equal(t, types.Int(2), d.OldValue)
+	assert.Equal(t, types.Int(3), d.NewValue)
+}
+
+func TestInitialDiffsCap(t *testing.T) {
+	ad := NewAsyncDiffer(1024)
+	assert.Equal(t, minDiffsCap, ad.initialDiffsCap(0))
+	assert.Equal(t, 4, ad.initialDiffsCap(4))
+
+	for i := 0; i < 100; i++ {
+		ad.diffChan <- diff.Difference{}
+	}
+	assert.Equal(t, 100, ad.initialDiffsCap(0))
+	assert.Equal(t, 100, ad.initialDiffsCap(500))
+	assert.Equal(t, 50, ad.initialDiffsCap(50))
+}
+
+func BenchmarkGetDiffsAllocs(b *testing.B) {
+	ctx := context.Background()
+	vrw := types.NewMemoryValueStore()
+
+	var fromVals, sparseVals, denseVals []int
+	for i := 0; i < 10000; i++ {
+		fromVals = append(fromVals, i, i)
+		if i%1000 == 0 {
+			sparseVals = append(sparseVals, i, -i)
+		} else {
+			sparseVals = append(sparseVals, i, i)
+		}
+		denseVals = append(denseVals, i, -i)
+	}
+	from := keyedTestMap(b, vrw, fromVals...)
+
+	benchmarks := []struct {
+		name string
+		to   types.Map
+	}{
+		{"sparse", keyedTestMap(b, vrw, sparseVals...)},
+		{"dense", keyedTestMap(b, vrw, denseVals...)},
+	}
+
+	for _, bm := range benchmarks {
+		b.Run(bm.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				rd := NewRowDiffer(ctx, testKeyedSch, testKeyedSch, 64*1024)
+				rd.Start(ctx, from, bm.to)
+				for {
+					_, more, err := rd.GetDiffs(0, time.Millisecond)
+					if err != nil {
+						b.Fatal(err)
+					}
+					if !more {
+						break
+					}
+				}
+				if err := rd.Close(); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func TestExpandKeylessDiff(t *testing.T) {
+	nbf := types.Format_Default
+
+	key, err := types.NewTuple(nbf, types.Uint(schema.KeylessRowIdTag), types.Int(1))
+	require.NoError(t, err)
+	rowWithCard := func(card uint64) types.Value {
+		v, err := types.NewTuple(nbf,
+			types.Uint(schema.KeylessRowCardinalityTag), types.Uint(card),
+			types.Uint(testValTag), types.Int(1))
+		require.NoError(t, err)
+		return v
+	}
+
+	tests := []struct {
+		name     string
+		df       diff.Difference
+		expType  types.DiffChangeType
+		expCount int
+		expOld   bool
+		expNew   bool
+	}{
+		{
+			name:     "added",
+			df:       diff.Difference{ChangeType: types.DiffChangeAdded, KeyValue: key, NewValue: rowWithCard(3)},
+			expType:  types.DiffChangeAdded,
+			expCount: 3,
+			expNew:   true,
+		},
+		{
+			name:     "removed",
+			df:       diff.Difference{ChangeType: types.DiffChangeRemoved, KeyValue: key, OldValue: rowWithCard(2)},
+			expType:  types.DiffChangeRemoved,
+			expCount: 2,
+			expOld:   true,
+		},
+		{
+			name:     "cardinality increased",
+			df:       diff.Difference{ChangeType: types.DiffChangeModified, KeyValue: key, OldValue: rowWithCard(1), NewValue: rowWithCard(4)},
+			expType:  types.DiffChangeAdded,
+			expCount: 3,
+			expNew:   true,
+		},
+		{
+			name:     "cardinality decreased",
+			df:       diff.Difference{ChangeType: types.DiffChangeModified, KeyValue: key, OldValue: rowWithCard(5), NewValue: rowWithCard(2)},
+			expType:  types.DiffChangeRemoved,
+			expCount: 3,
+			expOld:   true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			expanded, err := ExpandKeylessDiff(test.df)
+			require.NoError(t, err)
+			require.Len(t, expanded, test.expCount)
+
+			for _, d := range expanded {
+				assert.Equal(t, test.expType, d.ChangeType)
+				assert.True(t, key.Equals(d.KeyValue))
+				assert.Equal(t, test.expOld, d.OldValue != nil)
+				assert.Equal(t, test.expNew, d.NewValue != nil)
+			}
+		})
+	}
+
+	_, err = ExpandKeylessDiff(diff.Difference{ChangeType: types.DiffChangeType(42)})
+	assert.Error(t, err)
+}
+
+func TestKeylessDifferGetDiffs(t *testing.T) {
+	ctx := context.Background()
+	vrw := types.NewMemoryValueStore()
+
+	from := keylessTestMap(t, vrw, 1, 1, 2, 5, 3, 2)
+	to := keylessTestMap(t, vrw, 2, 2, 3, 4, 4, 1)
+
+	t.Run("partial fill then close", func(t *testing.T) {
+		rd := NewRowDiffer(ctx, testKeylessSch, testKeylessSch, 8)
+		rd.Start(ctx, from, to)
+
+		diffs, more, err := rd.GetDiffs(100, time.Second)
+		require.NoError(t, err)
+		assert.False(t, more)
+		require.NoError(t, rd.Close())
+
+		counts := make(map[types.DiffChangeType]int)
+		for _, d := range diffs {
+			require.NotNil(t, d)
+			counts[d.ChangeType]++
+		}
+		assert.Equal(t, 4, counts[types.DiffChangeRemoved])
+		assert.Equal(t, 3, counts[types.DiffChangeAdded])
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		key, err := types.NewTuple(vrw.Format(), types.Uint(schema.KeylessRowIdTag), types.Int(1))
+		require.NoError(t, err)
+		val, err := types.NewTuple(vrw.Format(),
+			types.Uint(schema.KeylessRowCardinalityTag), types.Uint(2),
+			types.Uint(testValTag), types.Int(1))
+		require.NoError(t, err)
+
+		// the diff channel is never closed, so GetDiffs times out after returning the copies it has
+		ad := NewAsyncDiffer(8)
+		ad.diffChan <- diff.Difference{ChangeType: types.DiffChangeAdded, KeyValue: key, NewValue: val}
+		kd := &keylessDiffer{AsyncDiffer: ad}
+
+		diffs, more, err := kd.GetDiffs(10, 10*time.Millisecond)
+		require.NoError(t, err)
+		assert.True(t, more)
+		require.Len(t, diffs, 2)
+		for _, d := range diffs {
+			require.NotNil(t, d)
+			assert.Equal(t, types.DiffChangeAdded, d.ChangeType)
+		}
+	})
+}
+
+func TestKeylessDifferCardinalityDecreased(t *testing.T) {
+	ctx := context.Background()
+	vrw := types.NewMemoryValueStore()
+
+	from := keylessTestMap(t, vrw, 1, 5)
+	to := keylessTestMap(t, vrw, 1, 2)
+
+	rd := NewRowDiffer(ctx, testKeylessSch, testKeylessSch, 8)
+	rd.Start(ctx, from, to)
+
+	diffs, more, err := rd.GetDiffs(100, time.Second)
+	require.NoError(t, err)
+	assert.False(t, more)
+	require.NoError(t, rd.Close())
+
+	require.Len(t, diffs, 3)
+	for _, d := range diffs {
+		assert.Equal(t, types.DiffChangeRemoved, d.ChangeType)
+		assert.NotNil(t, d.OldValue)
+		assert.Nil(t, d.NewValue)
+	}
+}
+
+func TestRowDifferOptionsCompose(t *testing.T) {
+	ctx := context.Background()
+	vrw := types.NewMemoryValueStore()
+
+	from := keyedTestMap(t, vrw, 1, 1, 2, 2, 3, 3, 5, 5, 7, 7)
+	to := keyedTestMap(t, vrw, 0, 0, 2, 20, 3, 3, 4, 4, 5, 50, 8, 8)
+
+	pc := NewPauseControl()
+	pr := NewProgressReporter(time.Hour)
+	rd := NewRowDiffer(ctx, testKeyedSch, testKeyedSch, 4,
+		WithChangeTypes(types.DiffChangeModified, types.DiffChangeRemoved),
+		WithProgress(pr),
+		WithPauseControl(pc))
+
+	pc.Pause()
+	rd.Start(ctx, from, to)
+
+	diffs, _, err := rd.GetDiffs(10, 50*time.Millisecond)
+	require.NoError(t, err)
+	assert.Empty(t, diffs)
+
+	pc.Resume()
+	diffs = drainDiffs(t, rd)
+
+	var pks []int
+	for _, d := range diffs {
+		pk, err := d.KeyValue.(types.Tuple).Get(1)
+		require.NoError(t, err)
+		pks = append(pks, int(pk.(types.Int)))
+	}
+	assert.Equal(t, []int{1, 2, 5, 7}, pks)
+
+	// progress counts the differences that passed the filter
+	var last DiffProgress
+	for p := range pr.Progress() {
+		last = p
+	}
+	assert.Equal(t, DiffProgress{Removed: 2, Modified: 2}, last)
+}
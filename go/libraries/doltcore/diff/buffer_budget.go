@@ -0,0 +1,143 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// ErrBufferBudgetTooSmall is returned by a RowDiffer created with WithBufferBudget whose diff needs more buffered
+// differences than its BufferBudget holds.
+var ErrBufferBudgetTooSmall = errors.New("diff needs more buffered differences than its buffer budget holds")
+
+// BufferBudget bounds the total number of differences buffered by the RowDiffers created with WithBufferBudget
+// that share it. Each RowDiffer reserves the differences its diff may buffer from the budget when it's started, and
+// returns them when its diff ends.
+type BufferBudget struct {
+	mu    sync.Mutex
+	size  int
+	inUse int
+	// released is closed, and replaced, each time differences are returned to the budget
+	released chan struct{}
+}
+
+// NewBufferBudget returns a BufferBudget of |size| buffered differences, which must be at least one.
+func NewBufferBudget(size int) *BufferBudget {
+	if size < 1 {
+		panic("buffer budget must be at least one difference")
+	}
+
+	return &BufferBudget{size: size, released: make(chan struct{})}
+}
+
+// InUse returns the number of buffered differences currently reserved from the budget.
+func (b *BufferBudget) InUse() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.inUse
+}
+
+// reserve reserves between |min| and |want| buffered differences, as many as are available, and returns the number
+// reserved. It blocks until at least |min| are available, or |ctx| is done.
+func (b *BufferBudget) reserve(ctx context.Context, min, want int) (int, error) {
+	if min > b.size {
+		return 0, ErrBufferBudgetTooSmall
+	}
+
+	if want < min {
+		want = min
+	}
+
+	for {
+		b.mu.Lock()
+		if avail := b.size - b.inUse; avail >= min {
+			n := want
+			if n > avail {
+				n = avail
+			}
+
+			b.inUse += n
+			b.mu.Unlock()
+			return n, nil
+		}
+
+		released := b.released
+		b.mu.Unlock()
+
+		select {
+		case <-released:
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		}
+	}
+}
+
+// release returns |n| reserved differences to the budget.
+func (b *BufferBudget) release(n int) {
+	if n <= 0 {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.inUse -= n
+	close(b.released)
+	b.released = make(chan struct{})
+}
+
+// WithBufferBudget makes a RowDiffer reserve the differences its diff may buffer from |b|. This includes its own
+// buffer, the buffers between the stages added by other options, and the differences held by options such as
+// WithCoalescing and WithChangeTypeOrder with a limit. Differences held by WithChangeTypeOrder without a limit are
+// not counted. When the budget is short the buffers are made smaller than requested, and when it's exhausted Start
+// blocks until differences are returned to it or its context is done, in which case the diff fails with the
+// context's error. The reservation is returned when the diff ends, whether or not the RowDiffer is closed.
+func WithBufferBudget(b *BufferBudget) RowDifferOption {
+	return func(o *rowDifferOptions) {
+		o.budget = b
+	}
+}
+
+// bufferReservation returns the capacity of each of |ad|'s buffers, reserving them from its budget if it has one,
+// along with a function that returns the reservation.
+func (ad *AsyncDiffer) bufferReservation(ctx context.Context) (int, func(), error) {
+	if ad.budget == nil {
+		return ad.bufferSize, func() {}, nil
+	}
+
+	// each buffer has room for at least one difference, unless the differ is unbuffered
+	perBuffer := 1
+	if ad.bufferSize == 0 {
+		perBuffer = 0
+	}
+
+	min := ad.buffers*perBuffer + ad.extraBuffered
+	want := ad.buffers*ad.bufferSize + ad.extraBuffered
+
+	n, err := ad.budget.reserve(ctx, min, want)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	budget := ad.budget
+	size := ad.bufferSize
+	if ad.buffers > 0 {
+		size = (n - ad.extraBuffered) / ad.buffers
+	}
+
+	return size, func() { budget.release(n) }, nil
+}
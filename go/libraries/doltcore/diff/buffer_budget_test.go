@@ -0,0 +1,183 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// budgetTestMaps returns maps with 500 modified rows between them.
+func budgetTestMaps(t *testing.T) (from, to types.Map) {
+	vrw := types.NewMemoryValueStore()
+
+	var fromVals, toVals []int
+	for i := 0; i < 500; i++ {
+		fromVals = append(fromVals, i, i)
+		toVals = append(toVals, i, -i-1)
+	}
+	return keyedTestMap(t, vrw, fromVals...), keyedTestMap(t, vrw, toVals...)
+}
+
+func TestBufferBudget(t *testing.T) {
+	ctx := context.Background()
+	from, to := budgetTestMaps(t)
+
+	const budgetSize = 64
+	budget := NewBufferBudget(budgetSize)
+
+	// |peak| is the most buffered differences reserved at once, sampled each time a differ is started
+	var mu sync.Mutex
+	peak := 0
+
+	wg := &sync.WaitGroup{}
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			rd := NewRowDiffer(ctx, testKeyedSch, testKeyedSch, 16, WithBufferBudget(budget))
+			rd.Start(ctx, from, to)
+			mu.Lock()
+			if inUse := budget.InUse(); inUse > peak {
+				peak = inUse
+			}
+			mu.Unlock()
+
+			ad := rd.(*AsyncDiffer)
+			assert.True(t, cap(ad.diffChan) >= 1 && cap(ad.diffChan) <= 16)
+			assert.Len(t, drainDiffs(t, rd), 500)
+		}()
+	}
+	wg.Wait()
+
+	assert.LessOrEqual(t, peak, budgetSize)
+	assert.Equal(t, 0, budget.InUse())
+}
+
+func TestBufferBudgetShrinksAndBlocks(t *testing.T) {
+	ctx := context.Background()
+	from, to := budgetTestMaps(t)
+	budget := NewBufferBudget(10)
+
+	// neither diff is read, so both hold their reservations until they're closed
+	rd1 := NewRowDiffer(ctx, testKeyedSch, testKeyedSch, 8, WithBufferBudget(budget))
+	rd1.Start(ctx, from, to)
+	assert.Equal(t, 8, cap(rd1.(*AsyncDiffer).diffChan))
+
+	// only part of the request is available
+	rd2 := NewRowDiffer(ctx, testKeyedSch, testKeyedSch, 8, WithBufferBudget(budget))
+	rd2.Start(ctx, from, to)
+	assert.Equal(t, 2, cap(rd2.(*AsyncDiffer).diffChan))
+	assert.Equal(t, 10, budget.InUse())
+
+	rd3 := NewRowDiffer(ctx, testKeyedSch, testKeyedSch, 8, WithBufferBudget(budget))
+	started := make(chan struct{})
+	go func() {
+		defer close(started)
+		rd3.Start(ctx, from, to)
+	}()
+
+	select {
+	case <-started:
+		t.Fatal("differ started while the budget was exhausted")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	require.NoError(t, rd1.Close())
+	<-started
+	assert.Equal(t, 8, cap(rd3.(*AsyncDiffer).diffChan))
+
+	require.NoError(t, rd2.Close())
+	require.NoError(t, rd3.Close())
+	assert.Equal(t, 0, budget.InUse())
+
+	// differs created without a budget are unbounded
+	rd := NewRowDiffer(ctx, testKeyedSch, testKeyedSch, 100)
+	rd.Start(ctx, from, to)
+	assert.Equal(t, 100, cap(rd.(*AsyncDiffer).diffChan))
+	require.NoError(t, rd.Close())
+}
+
+func TestBufferBudgetHonoursContext(t *testing.T) {
+	from, to := budgetTestMaps(t)
+	budget := NewBufferBudget(8)
+
+	rd1 := NewRowDiffer(context.Background(), testKeyedSch, testKeyedSch, 8, WithBufferBudget(budget))
+	rd1.Start(context.Background(), from, to)
+	defer rd1.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	rd2 := NewRowDiffer(ctx, testKeyedSch, testKeyedSch, 8, WithBufferBudget(budget))
+	rd2.Start(ctx, from, to)
+	_, _, err := rd2.GetDiffs(1, time.Second)
+	assert.Equal(t, context.DeadlineExceeded, err)
+	assert.Equal(t, context.DeadlineExceeded, rd2.Close())
+	assert.Equal(t, 8, budget.InUse())
+}
+
+func TestBufferBudgetCountsInternalBuffers(t *testing.T) {
+	ctx := context.Background()
+	from, to := budgetTestMaps(t)
+	budget := NewBufferBudget(100)
+
+	// the coalescing window adds a buffer and holds up to 4 differences, and the key order check adds nothing
+	rd := NewRowDiffer(ctx, testKeyedSch, testKeyedSch, 8, WithCoalescing(4), WithKeyOrderCheck(), WithBufferBudget(budget))
+	rd.Start(ctx, from, to)
+	assert.Equal(t, 2*8+4, budget.InUse())
+	require.NoError(t, rd.Close())
+	assert.Equal(t, 0, budget.InUse())
+
+	// the reservation is returned once the diff ends, even if the differ isn't closed
+	rd = NewRowDiffer(ctx, testKeyedSch, testKeyedSch, 8, WithBufferBudget(budget))
+	rd.Start(ctx, from, to)
+	for {
+		_, more, err := rd.GetDiffs(16, time.Second)
+		require.NoError(t, err)
+		if !more {
+			break
+		}
+	}
+	assert.Equal(t, 0, budget.InUse())
+
+	small := NewBufferBudget(3)
+	rd = NewRowDiffer(ctx, testKeyedSch, testKeyedSch, 8, WithCoalescing(4), WithBufferBudget(small))
+	rd.Start(ctx, from, to)
+	_, _, err := rd.GetDiffs(1, time.Second)
+	assert.Equal(t, ErrBufferBudgetTooSmall, err)
+	assert.Equal(t, ErrBufferBudgetTooSmall, rd.Close())
+}
+
+func TestBufferBudgetKeylessConversionWorkers(t *testing.T) {
+	ctx := context.Background()
+	vrw := types.NewMemoryValueStore()
+	from := keylessTestMap(t, vrw, 1, 1, 2, 1)
+	to := keylessTestMap(t, vrw, 1, 2, 3, 1)
+	budget := NewBufferBudget(1000)
+
+	rd := NewRowDiffer(ctx, testKeylessSch, testKeylessSch, 8, WithConversionWorkers(2), WithBufferBudget(budget))
+	rd.Start(ctx, from, to)
+	assert.Len(t, drainDiffs(t, rd), 3)
+	assert.Equal(t, 0, budget.InUse())
+}
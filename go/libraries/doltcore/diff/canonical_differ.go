@@ -0,0 +1,109 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"context"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/row"
+	"github.com/dolthub/dolt/go/store/diff"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// CanonicalizeFunc returns the canonical form of |val|, the value of the column with tag |tag|. Values whose
+// canonical forms are equal are considered unchanged. |val| is types.NullValue for columns missing from a row.
+type CanonicalizeFunc func(tag uint64, val types.Value) (types.Value, error)
+
+// WithCanonicalizer makes a RowDiffer drop modifications in which every differing column has the same canonical form,
+// according to |canonicalize|, in the old and new rows. This hides changes to how values are encoded that don't change
+// what they mean, such as an int column migrated to strings of digits. Keyless rows are identified by their encoded
+// values, so for keyless tables such changes are still reported, as a removal and an addition.
+func WithCanonicalizer(canonicalize CanonicalizeFunc) RowDifferOption {
+	return func(o *rowDifferOptions) {
+		if o.keyless {
+			return
+		}
+
+		o.wrap(func(diffFn mapDiffFunc) mapDiffFunc {
+			return dropCanonicallyEqual(diffFn, canonicalize)
+		})
+	}
+}
+
+func dropCanonicallyEqual(diffFn mapDiffFunc, canonicalize CanonicalizeFunc) mapDiffFunc {
+	return func(ctx context.Context, from, to types.Map, out chan<- diff.Difference) error {
+		return pipeDiffs(ctx, from, to, out, diffFn, func(d diff.Difference, send func(diff.Difference) error) error {
+			if d.ChangeType != types.DiffChangeModified {
+				return send(d)
+			}
+
+			equal, err := canonicallyEqual(d.OldValue, d.NewValue, canonicalize)
+			if err != nil {
+				return err
+			}
+
+			if equal {
+				return nil
+			}
+			return send(d)
+		})
+	}
+}
+
+// canonicallyEqual returns whether every column that differs between the row values |oldVal| and |newVal| has the
+// same canonical form in both.
+func canonicallyEqual(oldVal, newVal types.Value, canonicalize CanonicalizeFunc) (bool, error) {
+	oldTVs, err := row.ParseTaggedValues(oldVal.(types.Tuple))
+	if err != nil {
+		return false, err
+	}
+
+	newTVs, err := row.ParseTaggedValues(newVal.(types.Tuple))
+	if err != nil {
+		return false, err
+	}
+
+	tags := make(map[uint64]struct{}, len(oldTVs)+len(newTVs))
+	for tag := range oldTVs {
+		tags[tag] = struct{}{}
+	}
+	for tag := range newTVs {
+		tags[tag] = struct{}{}
+	}
+
+	for tag := range tags {
+		o := oldTVs.GetWithDefault(tag, types.NullValue)
+		n := newTVs.GetWithDefault(tag, types.NullValue)
+		if o.Equals(n) {
+			continue
+		}
+
+		o, err = canonicalize(tag, o)
+		if err != nil {
+			return false, err
+		}
+
+		n, err = canonicalize(tag, n)
+		if err != nil {
+			return false, err
+		}
+
+		if !o.Equals(n) {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
@@ -0,0 +1,70 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func TestCanonicalizingRowDiffer(t *testing.T) {
+	ctx := context.Background()
+	vrw := types.NewMemoryValueStore()
+
+	rowMap := func(vals map[int]types.Value) types.Map {
+		kvs := make([]types.Value, 0, 2*len(vals))
+		for pk, val := range vals {
+			k, err := types.NewTuple(vrw.Format(), types.Uint(testPkTag), types.Int(pk))
+			require.NoError(t, err)
+			v, err := types.NewTuple(vrw.Format(), types.Uint(testValTag), val)
+			require.NoError(t, err)
+			kvs = append(kvs, k, v)
+		}
+
+		m, err := types.NewMap(ctx, vrw, kvs...)
+		require.NoError(t, err)
+		return m
+	}
+
+	from := rowMap(map[int]types.Value{1: types.Int(1), 2: types.Int(2), 3: types.Int(3)})
+	to := rowMap(map[int]types.Value{1: types.String("1"), 2: types.String("20"), 3: types.Int(3), 4: types.String("4")})
+
+	intsAsStrings := func(tag uint64, val types.Value) (types.Value, error) {
+		if i, ok := val.(types.Int); ok && tag == testValTag {
+			return types.String(strconv.FormatInt(int64(i), 10)), nil
+		}
+		return val, nil
+	}
+
+	rd := NewRowDiffer(ctx, testKeyedSch, testKeyedSch, 8)
+	rd.Start(ctx, from, to)
+	assert.Len(t, drainDiffs(t, rd), 3)
+
+	rd = NewRowDiffer(ctx, testKeyedSch, testKeyedSch, 8, WithCanonicalizer(intsAsStrings))
+	rd.Start(ctx, from, to)
+	diffs := drainDiffs(t, rd)
+
+	require.Len(t, diffs, 2)
+	assert.Equal(t, types.DiffChangeModified, diffs[0].ChangeType)
+	assertValuesEqual(t, types.Int(2), mustTupleGet(t, diffs[0].KeyValue.(types.Tuple), 1))
+	assert.Equal(t, types.DiffChangeAdded, diffs[1].ChangeType)
+	assertValuesEqual(t, types.Int(4), mustTupleGet(t, diffs[1].KeyValue.(types.Tuple), 1))
+}
@@ -0,0 +1,131 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"context"
+	"errors"
+
+	"github.com/dolthub/dolt/go/store/diff"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// changeTypeOrder is the order in which a RowDiffer created with WithChangeTypeOrder emits its groups.
+var changeTypeOrder = []types.DiffChangeType{types.DiffChangeAdded, types.DiffChangeModified, types.DiffChangeRemoved}
+
+var errBufferLimitExceeded = errors.New("buffer limit exceeded")
+
+// changeTypeFunc returns the change type a difference is reported as.
+type changeTypeFunc func(d diff.Difference) (types.DiffChangeType, error)
+
+// WithChangeTypeOrder makes a RowDiffer emit all additions, then all modifications, then all removals, each group in
+// key order. Nothing is emitted until the whole diff has been buffered in memory, so this is not suited to streaming
+// large diffs. If |maxBuffered| is greater than zero and the diff contains more than |maxBuffered| differences, the
+// buffer is dropped and the maps are instead diffed once per change type, trading memory for repeated walks of the
+// maps. For keyless tables, rows are grouped by whether copies were added or removed. The differences of the map diff
+// are ordered before any other options are applied to them, whatever order the options are given in, so the other
+// options see each difference once even when the maps are diffed more than once.
+func WithChangeTypeOrder(maxBuffered int) RowDifferOption {
+	return func(o *rowDifferOptions) {
+		changeType := keyedChangeType
+		if o.keyless {
+			changeType = keylessChangeType
+		}
+
+		if maxBuffered > 0 {
+			o.extraBuffered += maxBuffered
+		}
+
+		// orderByChangeType pipes the map diff through a buffer of the RowDiffer's buffer size
+		o.buffers++
+		o.wrapMapDiff(func(diffFn mapDiffFunc) mapDiffFunc {
+			return orderByChangeType(diffFn, maxBuffered, changeType)
+		})
+	}
+}
+
+func keyedChangeType(d diff.Difference) (types.DiffChangeType, error) {
+	return d.ChangeType, nil
+}
+
+func keylessChangeType(d diff.Difference) (types.DiffChangeType, error) {
+	d, _, err := convertDiff(d, DefaultKeyFormatter)
+	return d.ChangeType, err
+}
+
+func orderByChangeType(diffFn mapDiffFunc, maxBuffered int, changeType changeTypeFunc) mapDiffFunc {
+	return func(ctx context.Context, from, to types.Map, out chan<- diff.Difference) error {
+		groups := make(map[types.DiffChangeType][]diff.Difference, len(changeTypeOrder))
+		buffered := 0
+
+		err := pipeDiffs(ctx, from, to, out, diffFn, func(d diff.Difference, _ func(diff.Difference) error) error {
+			if maxBuffered > 0 && buffered >= maxBuffered {
+				return errBufferLimitExceeded
+			}
+
+			ct, err := changeType(d)
+			if err != nil {
+				return err
+			}
+
+			groups[ct] = append(groups[ct], d)
+			buffered++
+			return nil
+		})
+
+		if err == errBufferLimitExceeded {
+			groups = nil
+			return diffPerChangeType(ctx, from, to, out, diffFn, changeType)
+		} else if err != nil {
+			return err
+		}
+
+		for _, ct := range changeTypeOrder {
+			for _, d := range groups[ct] {
+				select {
+				case out <- d:
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			}
+		}
+
+		return nil
+	}
+}
+
+// diffPerChangeType diffs |from| and |to| once for each change type in |changeTypeOrder|, forwarding only the
+// differences of that type.
+func diffPerChangeType(ctx context.Context, from, to types.Map, out chan<- diff.Difference, diffFn mapDiffFunc, changeType changeTypeFunc) error {
+	for _, want := range changeTypeOrder {
+		err := pipeDiffs(ctx, from, to, out, diffFn, func(d diff.Difference, send func(diff.Difference) error) error {
+			ct, err := changeType(d)
+			if err != nil {
+				return err
+			}
+
+			if ct != want {
+				return nil
+			}
+			return send(d)
+		})
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,139 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func TestChangeTypeOrderedRowDiffer(t *testing.T) {
+	ctx := context.Background()
+	vrw := types.NewMemoryValueStore()
+
+	from := keyedTestMap(t, vrw, 1, 1, 2, 2, 3, 3, 5, 5, 7, 7)
+	to := keyedTestMap(t, vrw, 0, 0, 2, 20, 3, 3, 4, 4, 5, 50, 8, 8)
+
+	type change struct {
+		ct types.DiffChangeType
+		pk int
+	}
+	expected := []change{
+		{types.DiffChangeAdded, 0},
+		{types.DiffChangeAdded, 4},
+		{types.DiffChangeAdded, 8},
+		{types.DiffChangeModified, 2},
+		{types.DiffChangeModified, 5},
+		{types.DiffChangeRemoved, 1},
+		{types.DiffChangeRemoved, 7},
+	}
+
+	tests := []struct {
+		name        string
+		maxBuffered int
+	}{
+		{"unbounded", 0},
+		{"under limit", 100},
+		{"over limit", 2},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rd := NewRowDiffer(ctx, testKeyedSch, testKeyedSch, 4, WithChangeTypeOrder(test.maxBuffered))
+			rd.Start(ctx, from, to)
+			diffs := drainDiffs(t, rd)
+
+			var actual []change
+			for _, d := range diffs {
+				pk, err := d.KeyValue.(types.Tuple).Get(1)
+				require.NoError(t, err)
+				actual = append(actual, change{d.ChangeType, int(pk.(types.Int))})
+			}
+			assert.Equal(t, expected, actual)
+		})
+	}
+}
+
+func TestChangeTypeOrderedRowDifferKeyless(t *testing.T) {
+	ctx := context.Background()
+	vrw := types.NewMemoryValueStore()
+
+	from := keylessTestMap(t, vrw, 1, 1, 2, 3)
+	to := keylessTestMap(t, vrw, 2, 1, 3, 2)
+
+	rd := NewRowDiffer(ctx, testKeylessSch, testKeylessSch, 4, WithChangeTypeOrder(0))
+	rd.Start(ctx, from, to)
+	diffs := drainDiffs(t, rd)
+
+	var changeTypes []types.DiffChangeType
+	for _, d := range diffs {
+		changeTypes = append(changeTypes, d.ChangeType)
+	}
+	assert.Equal(t, []types.DiffChangeType{
+		types.DiffChangeAdded, types.DiffChangeAdded,
+		types.DiffChangeRemoved, types.DiffChangeRemoved, types.DiffChangeRemoved,
+	}, changeTypes)
+}
+
+func TestChangeTypeOrderedRowDifferWithOtherOptions(t *testing.T) {
+	ctx := context.Background()
+	vrw := types.NewMemoryValueStore()
+
+	// 20 removals, 20 modifications and 20 additions, more than the differ buffers
+	var fromVals, toVals []int
+	for i := 0; i < 40; i++ {
+		fromVals = append(fromVals, i, i)
+	}
+	for i := 20; i < 60; i++ {
+		v := i
+		if i < 40 {
+			v = -i
+		}
+		toVals = append(toVals, i, v)
+	}
+	from := keyedTestMap(t, vrw, fromVals...)
+	to := keyedTestMap(t, vrw, toVals...)
+
+	pr := NewProgressReporter(time.Millisecond)
+	logger := &recordingEventLogger{}
+	rd := NewRowDiffer(ctx, testKeyedSch, testKeyedSch, 8, WithProgress(pr), WithEventLog(logger, time.Millisecond), WithChangeTypeOrder(5))
+	rd.Start(ctx, from, to)
+
+	var last DiffProgress
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for p := range pr.Progress() {
+			last = p
+		}
+	}()
+
+	diffs := drainDiffs(t, rd)
+	require.Len(t, diffs, 60)
+	for i, d := range diffs {
+		assert.Equal(t, changeTypeOrder[i/20], d.ChangeType)
+	}
+
+	<-done
+	assert.Equal(t, DiffProgress{Added: 20, Removed: 20, Modified: 20}, last)
+	assert.Equal(t, []DiffEventKind{DiffStarted, DiffFirstDifference, DiffCompleted}, logger.kinds())
+	assert.Equal(t, uint64(60), logger.last().Differences)
+}
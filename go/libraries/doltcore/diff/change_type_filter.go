@@ -0,0 +1,66 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"context"
+
+	"github.com/dolthub/dolt/go/store/diff"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// WithChangeTypes makes a RowDiffer emit only the differences whose change type is one of |changeTypes|. Unwanted
+// differences are dropped before they are sent to the differ's channel. An empty |changeTypes| keeps every difference.
+// For keyless tables, the change type is the one a difference is reported as, so a modification that removes copies of
+// a row is kept when removals are requested.
+func WithChangeTypes(changeTypes ...types.DiffChangeType) RowDifferOption {
+	return func(o *rowDifferOptions) {
+		if len(changeTypes) == 0 {
+			return
+		}
+
+		changeType := keyedChangeType
+		if o.keyless {
+			changeType = keylessChangeType
+		}
+
+		o.wrap(func(diffFn mapDiffFunc) mapDiffFunc {
+			return filterChangeTypes(diffFn, changeTypes, changeType)
+		})
+	}
+}
+
+// filterChangeTypes returns a mapDiffFunc that forwards only the differences from |diffFn| whose change type, as
+// returned by |changeType|, is in |changeTypes|.
+func filterChangeTypes(diffFn mapDiffFunc, changeTypes []types.DiffChangeType, changeType changeTypeFunc) mapDiffFunc {
+	wanted := make(map[types.DiffChangeType]bool, len(changeTypes))
+	for _, ct := range changeTypes {
+		wanted[ct] = true
+	}
+
+	return func(ctx context.Context, from, to types.Map, out chan<- diff.Difference) error {
+		return pipeDiffs(ctx, from, to, out, diffFn, func(d diff.Difference, send func(diff.Difference) error) error {
+			ct, err := changeType(d)
+			if err != nil {
+				return err
+			}
+
+			if !wanted[ct] {
+				return nil
+			}
+			return send(d)
+		})
+	}
+}
@@ -0,0 +1,110 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func TestRowDifferWithFilter(t *testing.T) {
+	ctx := context.Background()
+	vrw := types.NewMemoryValueStore()
+
+	from := keyedTestMap(t, vrw, 1, 1, 2, 2, 3, 3, 5, 5, 7, 7)
+	to := keyedTestMap(t, vrw, 0, 0, 2, 20, 3, 3, 4, 4, 5, 50, 8, 8)
+
+	type change struct {
+		ct types.DiffChangeType
+		pk int
+	}
+
+	tests := []struct {
+		name        string
+		changeTypes []types.DiffChangeType
+		expected    []change
+	}{
+		{
+			name:        "removes",
+			changeTypes: []types.DiffChangeType{types.DiffChangeRemoved},
+			expected:    []change{{types.DiffChangeRemoved, 1}, {types.DiffChangeRemoved, 7}},
+		},
+		{
+			name:        "adds and modifications",
+			changeTypes: []types.DiffChangeType{types.DiffChangeAdded, types.DiffChangeModified},
+			expected: []change{
+				{types.DiffChangeAdded, 0},
+				{types.DiffChangeModified, 2},
+				{types.DiffChangeAdded, 4},
+				{types.DiffChangeModified, 5},
+				{types.DiffChangeAdded, 8},
+			},
+		},
+		{
+			name: "everything",
+			expected: []change{
+				{types.DiffChangeAdded, 0},
+				{types.DiffChangeRemoved, 1},
+				{types.DiffChangeModified, 2},
+				{types.DiffChangeAdded, 4},
+				{types.DiffChangeModified, 5},
+				{types.DiffChangeRemoved, 7},
+				{types.DiffChangeAdded, 8},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rd := NewRowDiffer(ctx, testKeyedSch, testKeyedSch, 4, WithChangeTypes(test.changeTypes...))
+			rd.Start(ctx, from, to)
+			diffs := drainDiffs(t, rd)
+
+			var actual []change
+			for _, d := range diffs {
+				pk, err := d.KeyValue.(types.Tuple).Get(1)
+				require.NoError(t, err)
+				actual = append(actual, change{d.ChangeType, int(pk.(types.Int))})
+			}
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+}
+
+func TestRowDifferWithFilterKeyless(t *testing.T) {
+	ctx := context.Background()
+	vrw := types.NewMemoryValueStore()
+
+	from := keylessTestMap(t, vrw, 1, 1, 2, 3)
+	to := keylessTestMap(t, vrw, 2, 1, 3, 2)
+
+	rd := NewRowDiffer(ctx, testKeylessSch, testKeylessSch, 4, WithChangeTypes(types.DiffChangeRemoved))
+	rd.Start(ctx, from, to)
+	diffs := drainDiffs(t, rd)
+
+	var changeTypes []types.DiffChangeType
+	for _, d := range diffs {
+		changeTypes = append(changeTypes, d.ChangeType)
+		assert.Nil(t, d.NewValue)
+	}
+	assert.Equal(t, []types.DiffChangeType{
+		types.DiffChangeRemoved, types.DiffChangeRemoved, types.DiffChangeRemoved,
+	}, changeTypes)
+}
@@ -0,0 +1,116 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// changesetBatchSize is the number of differences DiffToMap requests from a RowDiffer at a time
+const changesetBatchSize = 1024
+
+// DiffToMap reads every difference from |differ|, which must have been started, and returns a changeset map holding
+// each changed key, with a value encoding the change that can be decoded with DecodeChange. Differences are added
+// to the map in the order the differ produces them, which is key order. The copies of a keyless row that the
+// differ produces are all the same change, so each changed keyless row has a single entry. The caller is
+// responsible for closing |differ|.
+func DiffToMap(ctx context.Context, differ RowDiffer, vrw types.ValueReadWriter) (types.Map, error) {
+	m, err := types.NewMap(ctx, vrw)
+	if err != nil {
+		return types.EmptyMap, err
+	}
+
+	me := m.Edit()
+	for {
+		if err := ctx.Err(); err != nil {
+			return types.EmptyMap, err
+		}
+
+		diffs, more, err := differ.GetDiffs(changesetBatchSize, time.Second)
+		if err != nil {
+			return types.EmptyMap, err
+		}
+
+		for _, d := range diffs {
+			change, err := encodeChange(vrw.Format(), d.ChangeType, d.OldValue, d.NewValue)
+			if err != nil {
+				return types.EmptyMap, err
+			}
+
+			me.Set(d.KeyValue, change)
+		}
+
+		if !more {
+			break
+		}
+	}
+
+	return me.Map(ctx)
+}
+
+// encodeChange returns a tuple of the change type, the old value and the new value of a change, with NULL in place
+// of a missing value.
+func encodeChange(nbf *types.NomsBinFormat, changeType types.DiffChangeType, oldVal, newVal types.Value) (types.Tuple, error) {
+	if oldVal == nil {
+		oldVal = types.NullValue
+	}
+
+	if newVal == nil {
+		newVal = types.NullValue
+	}
+
+	return types.NewTuple(nbf, types.Uint(changeType), oldVal, newVal)
+}
+
+// DecodeChange returns the change type, the old value and the new value of a change encoded in a changeset map
+// built by DiffToMap. The old value of an added row and the new value of a removed row are nil.
+func DecodeChange(v types.Value) (changeType types.DiffChangeType, oldVal, newVal types.Value, err error) {
+	t, ok := v.(types.Tuple)
+	if !ok || t.Len() != 3 {
+		return 0, nil, nil, fmt.Errorf("invalid change: %s", v.Kind())
+	}
+
+	ct, err := t.Get(0)
+	if err != nil {
+		return 0, nil, nil, err
+	}
+
+	u, ok := ct.(types.Uint)
+	if !ok {
+		return 0, nil, nil, fmt.Errorf("invalid change type: %s", ct.Kind())
+	}
+
+	if oldVal, err = t.Get(1); err != nil {
+		return 0, nil, nil, err
+	}
+
+	if newVal, err = t.Get(2); err != nil {
+		return 0, nil, nil, err
+	}
+
+	if types.IsNull(oldVal) {
+		oldVal = nil
+	}
+
+	if types.IsNull(newVal) {
+		newVal = nil
+	}
+
+	return types.DiffChangeType(u), oldVal, newVal, nil
+}
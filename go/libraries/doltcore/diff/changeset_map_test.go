@@ -0,0 +1,112 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func TestDiffToMap(t *testing.T) {
+	ctx := context.Background()
+	vrw := types.NewMemoryValueStore()
+
+	from := keyedTestMap(t, vrw, 1, 1, 2, 2, 3, 3)
+	to := keyedTestMap(t, vrw, 1, 1, 2, 20, 4, 4)
+
+	rd := NewRowDiffer(ctx, testKeyedSch, testKeyedSch, 2)
+	rd.Start(ctx, from, to)
+	changes, err := DiffToMap(ctx, rd, vrw)
+	require.NoError(t, err)
+	require.NoError(t, rd.Close())
+
+	key := func(pk int) types.Value {
+		k, err := types.NewTuple(vrw.Format(), types.Uint(testPkTag), types.Int(pk))
+		require.NoError(t, err)
+		return k
+	}
+	val := func(v int) types.Value {
+		tup, err := types.NewTuple(vrw.Format(), types.Uint(testValTag), types.Int(v))
+		require.NoError(t, err)
+		return tup
+	}
+
+	expected := []struct {
+		key        types.Value
+		changeType types.DiffChangeType
+		old, new   types.Value
+	}{
+		{key(2), types.DiffChangeModified, val(2), val(20)},
+		{key(3), types.DiffChangeRemoved, val(3), nil},
+		{key(4), types.DiffChangeAdded, nil, val(4)},
+	}
+
+	require.Equal(t, uint64(len(expected)), changes.Len())
+
+	i := 0
+	err = changes.IterAll(ctx, func(k, v types.Value) error {
+		exp := expected[i]
+		i++
+
+		assert.True(t, exp.key.Equals(k))
+		changeType, oldVal, newVal, err := DecodeChange(v)
+		require.NoError(t, err)
+		assert.Equal(t, exp.changeType, changeType)
+		assertValuesEqual(t, exp.old, oldVal)
+		assertValuesEqual(t, exp.new, newVal)
+		return nil
+	})
+	require.NoError(t, err)
+
+	// no differences
+	rd = NewRowDiffer(ctx, testKeyedSch, testKeyedSch, 2)
+	rd.Start(ctx, from, from)
+	changes, err = DiffToMap(ctx, rd, vrw)
+	require.NoError(t, err)
+	require.NoError(t, rd.Close())
+	assert.Equal(t, uint64(0), changes.Len())
+
+	_, _, _, err = DecodeChange(types.Int(1))
+	assert.Error(t, err)
+}
+
+func TestDiffToMapKeyless(t *testing.T) {
+	ctx := context.Background()
+	vrw := types.NewMemoryValueStore()
+
+	// 2 of the 3 copies of 1 are removed
+	from := keylessTestMap(t, vrw, 1, 3, 2, 1)
+	to := keylessTestMap(t, vrw, 1, 1, 2, 1)
+
+	rd := NewRowDiffer(ctx, testKeylessSch, testKeylessSch, 2)
+	rd.Start(ctx, from, to)
+	changes, err := DiffToMap(ctx, rd, vrw)
+	require.NoError(t, err)
+	require.NoError(t, rd.Close())
+
+	require.Equal(t, uint64(1), changes.Len())
+	_, v, err := changes.First(ctx)
+	require.NoError(t, err)
+	changeType, oldVal, newVal, err := DecodeChange(v)
+	require.NoError(t, err)
+	assert.Equal(t, types.DiffChangeRemoved, changeType)
+	assert.NotNil(t, oldVal)
+	assert.Nil(t, newVal)
+}
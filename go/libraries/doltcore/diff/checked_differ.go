@@ -0,0 +1,138 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"context"
+	"errors"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/store/diff"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// ErrPrimaryKeyChanged is returned when a table's primary key changed between revisions, so rows cannot be matched.
+var ErrPrimaryKeyChanged = errors.New("primary key changed between revisions, rows cannot be diffed")
+
+type RowDiffMode int
+
+const (
+	// CellDiffMode reports rows that exist in both revisions with different values as modified.
+	CellDiffMode RowDiffMode = iota
+	// PresenceDiffMode reports rows that exist in both revisions with different values as removed and re-added,
+	// as their cells cannot be meaningfully compared.
+	PresenceDiffMode
+)
+
+// RowDiffModeForSchemas returns how rows of a table can be diffed across a change from |fromSch| to |toSch|. Row
+// values are tagged, so columns that are added or dropped don't prevent rows from being compared cell by cell.
+// Columns that change type do, so if any column does the rows can only be diffed by presence. If the primary key
+// changed, ErrPrimaryKeyChanged is returned.
+func RowDiffModeForSchemas(fromSch, toSch schema.Schema) (RowDiffMode, error) {
+	if fromSch.GetAllCols().Size() == 0 || toSch.GetAllCols().Size() == 0 {
+		// table added or dropped
+		return CellDiffMode, nil
+	}
+
+	if schema.IsKeyless(fromSch) != schema.IsKeyless(toSch) {
+		return CellDiffMode, ErrPrimaryKeyChanged
+	}
+
+	if !schema.IsKeyless(fromSch) && !pkColsMatch(fromSch, toSch) {
+		return CellDiffMode, ErrPrimaryKeyChanged
+	}
+
+	colDiffs, _ := DiffSchColumns(fromSch, toSch)
+	for _, cd := range colDiffs {
+		if cd.DiffType == SchDiffModified && !cd.Old.TypeInfo.Equals(cd.New.TypeInfo) {
+			return PresenceDiffMode, nil
+		}
+	}
+
+	return CellDiffMode, nil
+}
+
+func pkColsMatch(fromSch, toSch schema.Schema) bool {
+	fromPks, toPks := fromSch.GetPKCols(), toSch.GetPKCols()
+	if fromPks.Size() != toPks.Size() {
+		return false
+	}
+
+	for i, tag := range fromPks.Tags {
+		if toPks.Tags[i] != tag {
+			return false
+		}
+
+		fromCol, _ := fromPks.GetByTag(tag)
+		toCol, _ := toPks.GetByTag(tag)
+		if !fromCol.TypeInfo.Equals(toCol.TypeInfo) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// NewCheckedRowDiffer returns a RowDiffer for the rows of a table whose schema changed from |fromSch| to |toSch|, using
+// the RowDiffMode returned by RowDiffModeForSchemas. |opts| are applied after the mode's own handling of differences.
+func NewCheckedRowDiffer(ctx context.Context, fromSch, toSch schema.Schema, buf int, opts ...RowDifferOption) (RowDiffer, error) {
+	mode, err := RowDiffModeForSchemas(fromSch, toSch)
+	if err != nil {
+		return nil, err
+	}
+
+	if mode == PresenceDiffMode {
+		opts = append([]RowDifferOption{withPresenceOnly()}, opts...)
+	}
+
+	return NewRowDiffer(ctx, fromSch, toSch, buf, opts...), nil
+}
+
+// NewRowDifferForTableDelta returns a checked RowDiffer for the rows of |td|.
+func NewRowDifferForTableDelta(ctx context.Context, td TableDelta, buf int, opts ...RowDifferOption) (RowDiffer, error) {
+	fromSch, toSch, err := td.GetSchemas(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewCheckedRowDiffer(ctx, fromSch, toSch, buf, opts...)
+}
+
+// withPresenceOnly makes a RowDiffer split each modification into a removal and an addition.
+func withPresenceOnly() RowDifferOption {
+	return func(o *rowDifferOptions) {
+		o.wrap(presenceOnly)
+	}
+}
+
+// presenceOnly returns a mapDiffFunc that splits each modification from |diffFn| into a removal and an addition.
+func presenceOnly(diffFn mapDiffFunc) mapDiffFunc {
+	return func(ctx context.Context, from, to types.Map, out chan<- diff.Difference) error {
+		return pipeDiffs(ctx, from, to, out, diffFn, func(d diff.Difference, send func(diff.Difference) error) error {
+			if d.ChangeType != types.DiffChangeModified {
+				return send(d)
+			}
+
+			removed, added := d, d
+			removed.ChangeType, removed.NewValue = types.DiffChangeRemoved, nil
+			added.ChangeType, added.OldValue, added.NewKeyValue = types.DiffChangeAdded, nil, d.KeyValue
+
+			if err := send(removed); err != nil {
+				return err
+			}
+			return send(added)
+		})
+	}
+}
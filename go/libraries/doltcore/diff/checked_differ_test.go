@@ -0,0 +1,108 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func TestCheckedRowDiffer(t *testing.T) {
+	ctx := context.Background()
+	vrw := types.NewMemoryValueStore()
+
+	colAddedSch := schema.MustSchemaFromCols(mustColColl(
+		schema.NewColumn("pk", testPkTag, types.IntKind, true, schema.NotNullConstraint{}),
+		schema.NewColumn("val", testValTag, types.IntKind, false),
+		schema.NewColumn("added", 2, types.StringKind, false),
+	))
+	typeChangedSch := schema.MustSchemaFromCols(mustColColl(
+		schema.NewColumn("pk", testPkTag, types.IntKind, true, schema.NotNullConstraint{}),
+		schema.NewColumn("val", testValTag, types.StringKind, false),
+	))
+	pkChangedSch := schema.MustSchemaFromCols(mustColColl(
+		schema.NewColumn("pk", testPkTag, types.IntKind, false),
+		schema.NewColumn("val", testValTag, types.IntKind, true, schema.NotNullConstraint{}),
+	))
+
+	from := keyedTestMap(t, vrw, 1, 1, 2, 2)
+	to := keyedTestMap(t, vrw, 1, 1, 2, 5)
+
+	tests := []struct {
+		name     string
+		toSch    schema.Schema
+		mode     RowDiffMode
+		expErr   error
+		expTypes []types.DiffChangeType
+	}{
+		{
+			name:     "column added",
+			toSch:    colAddedSch,
+			mode:     CellDiffMode,
+			expTypes: []types.DiffChangeType{types.DiffChangeModified},
+		},
+		{
+			name:     "column type changed",
+			toSch:    typeChangedSch,
+			mode:     PresenceDiffMode,
+			expTypes: []types.DiffChangeType{types.DiffChangeRemoved, types.DiffChangeAdded},
+		},
+		{
+			name:   "primary key changed",
+			toSch:  pkChangedSch,
+			expErr: ErrPrimaryKeyChanged,
+		},
+		{
+			name:     "table added",
+			toSch:    testKeyedSch,
+			mode:     CellDiffMode,
+			expTypes: []types.DiffChangeType{types.DiffChangeModified},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mode, err := RowDiffModeForSchemas(testKeyedSch, test.toSch)
+			assert.Equal(t, test.expErr, err)
+			assert.Equal(t, test.mode, mode)
+
+			rd, err := NewCheckedRowDiffer(ctx, testKeyedSch, test.toSch, 8)
+			if test.expErr != nil {
+				assert.Equal(t, test.expErr, err)
+				return
+			}
+			require.NoError(t, err)
+
+			rd.Start(ctx, from, to)
+			diffs := drainDiffs(t, rd)
+
+			var changeTypes []types.DiffChangeType
+			for _, d := range diffs {
+				changeTypes = append(changeTypes, d.ChangeType)
+			}
+			assert.Equal(t, test.expTypes, changeTypes)
+		})
+	}
+
+	mode, err := RowDiffModeForSchemas(schema.EmptySchema, testKeyedSch)
+	assert.NoError(t, err)
+	assert.Equal(t, CellDiffMode, mode)
+}
@@ -0,0 +1,99 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"github.com/dolthub/dolt/go/store/diff"
+	"github.com/dolthub/dolt/go/store/hash"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// ChangeKind classifies a row difference.
+type ChangeKind int
+
+const (
+	// RowAdded is a row that was added.
+	RowAdded ChangeKind = iota
+	// RowRemoved is a row that was removed.
+	RowRemoved
+	// NonKeyChange is a row whose key is unchanged and whose non-key columns changed.
+	NonKeyChange
+	// KeyChange is a row whose key changed while its non-key columns didn't. It appears in a diff as a removal
+	// under the old key and an addition under the new key, and both differences are classified as KeyChange.
+	KeyChange
+)
+
+// ClassifiedDifference is a row difference along with its ChangeKind.
+type ClassifiedDifference struct {
+	*diff.Difference
+	Kind ChangeKind
+	// OtherKey is set for differences classified as KeyChange. For the removal it is the row's new key, and for
+	// the addition it is the row's old key.
+	OtherKey types.Value
+}
+
+// ClassifyDifferences classifies the row differences in |diffs|. Modifications are always NonKeyChanges, as rows
+// are matched by key. A removal and an addition whose non-key values are identical are taken to be a change of the
+// row's key. This is a heuristic, as two distinct rows with identical non-key values are indistinguishable from
+// one row whose key changed. Only differences within |diffs| are paired, so key changes are only found if both
+// halves are classified together.
+func ClassifyDifferences(diffs []*diff.Difference) ([]ClassifiedDifference, error) {
+	classified := make([]ClassifiedDifference, len(diffs))
+
+	// indexes of unpaired removals, by the hash of the removed value
+	removed := make(map[hash.Hash][]int)
+	for i, d := range diffs {
+		classified[i] = ClassifiedDifference{Difference: d}
+
+		switch d.ChangeType {
+		case types.DiffChangeModified:
+			classified[i].Kind = NonKeyChange
+		case types.DiffChangeAdded:
+			classified[i].Kind = RowAdded
+		case types.DiffChangeRemoved:
+			classified[i].Kind = RowRemoved
+
+			h, err := d.OldValue.Hash(types.Format_Default)
+			if err != nil {
+				return nil, err
+			}
+			removed[h] = append(removed[h], i)
+		}
+	}
+
+	for i, d := range diffs {
+		if d.ChangeType != types.DiffChangeAdded {
+			continue
+		}
+
+		h, err := d.NewValue.Hash(types.Format_Default)
+		if err != nil {
+			return nil, err
+		}
+
+		candidates := removed[h]
+		if len(candidates) == 0 {
+			continue
+		}
+
+		j := candidates[0]
+		removed[h] = candidates[1:]
+
+		classified[i].Kind, classified[i].OtherKey = KeyChange, diffs[j].KeyValue
+		classified[j].Kind, classified[j].OtherKey = KeyChange, d.KeyValue
+	}
+
+	return classified, nil
+}
@@ -0,0 +1,77 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/store/diff"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func TestClassifyDifferences(t *testing.T) {
+	ctx := context.Background()
+	vrw := types.NewMemoryValueStore()
+
+	// pk 2 has its value changed, pk 3 is re-keyed as pk 4 and pk 5 is added
+	from := keyedTestMap(t, vrw, 1, 1, 2, 2, 3, 3)
+	to := keyedTestMap(t, vrw, 1, 1, 2, 20, 4, 3, 5, 5)
+
+	rd := NewRowDiffer(ctx, testKeyedSch, testKeyedSch, 8)
+	rd.Start(ctx, from, to)
+	diffs := drainDiffs(t, rd)
+	require.Len(t, diffs, 4)
+
+	classified, err := ClassifyDifferences(diffs)
+	require.NoError(t, err)
+	require.Len(t, classified, 4)
+
+	pk := func(v types.Value) int64 {
+		if v == nil {
+			return -1
+		}
+		val, err := v.(types.Tuple).Get(1)
+		require.NoError(t, err)
+		return int64(val.(types.Int))
+	}
+
+	kinds := make(map[int64]ChangeKind)
+	otherKeys := make(map[int64]int64)
+	for _, cd := range classified {
+		kinds[pk(cd.KeyValue)] = cd.Kind
+		otherKeys[pk(cd.KeyValue)] = pk(cd.OtherKey)
+	}
+
+	assert.Equal(t, map[int64]ChangeKind{2: NonKeyChange, 3: KeyChange, 4: KeyChange, 5: RowAdded}, kinds)
+	assert.Equal(t, map[int64]int64{2: -1, 3: 4, 4: 3, 5: -1}, otherKeys)
+
+	// without the other half of the key change, the removal is just a removal
+	var removal []*diff.Difference
+	for _, d := range diffs {
+		if d.ChangeType == types.DiffChangeRemoved {
+			removal = append(removal, d)
+		}
+	}
+
+	classified, err = ClassifyDifferences(removal)
+	require.NoError(t, err)
+	require.Len(t, classified, 1)
+	assert.Equal(t, RowRemoved, classified[0].Kind)
+	assert.Nil(t, classified[0].OtherKey)
+}
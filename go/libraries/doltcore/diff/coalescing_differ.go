@@ -0,0 +1,131 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"context"
+
+	"github.com/dolthub/dolt/go/store/diff"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// WithCoalescing makes a RowDiffer merge a removal and an addition of rows with identical values into a single
+// modification, as they are taken to be one row whose key changed. The merged modification has the removed row's key as
+// its KeyValue and the added row's key as its NewKeyValue, and takes the place of whichever of the two came first. As
+// with ClassifyDifferences, this is a heuristic.
+//
+// A removal and an addition are only merged if they are no more than |window| differences apart, so with a |window| of
+// 1 only adjacent differences are merged. A larger window finds pairs that other differences come between, such as when
+// a change to one column of a composite key moves the row past other changed rows, but holds up to |window|
+// differences, along with their old and new rows, in memory, and compares each removal and addition with every
+// difference in the window. Differences are returned in the order the map diff produced them, which is key order,
+// whether or not they were merged. |window| is at least 1.
+func WithCoalescing(window int) RowDifferOption {
+	if window < 1 {
+		window = 1
+	}
+
+	return func(o *rowDifferOptions) {
+		o.extraBuffered += window
+		o.wrap(func(diffFn mapDiffFunc) mapDiffFunc {
+			return coalesceKeyChanges(diffFn, window)
+		})
+	}
+}
+
+// coalescingWindow holds the last differences produced by a map diff so that removals and additions can be merged
+// with later ones.
+type coalescingWindow struct {
+	size    int
+	entries []coalescingEntry
+}
+
+type coalescingEntry struct {
+	d diff.Difference
+	// merged is set once |d| has been merged with a later difference, so that it isn't merged again
+	merged bool
+}
+
+// add adds |d| to the window, or merges it with a difference already in the window. Once the window is full, the
+// oldest difference is passed to |send|.
+func (w *coalescingWindow) add(d diff.Difference, send func(diff.Difference) error) error {
+	for i := range w.entries {
+		e := &w.entries[i]
+		if !e.merged && mergeKeyChange(&e.d, d) {
+			e.merged = true
+			return nil
+		}
+	}
+
+	w.entries = append(w.entries, coalescingEntry{d: d})
+	if len(w.entries) <= w.size {
+		return nil
+	}
+
+	oldest := w.entries[0]
+	w.entries = w.entries[1:]
+	return send(oldest.d)
+}
+
+// mergeKeyChange merges |later| into |earlier| if one is a removal and the other an addition of identical values,
+// and returns whether it did.
+func mergeKeyChange(earlier *diff.Difference, later diff.Difference) bool {
+	removal, addition := *earlier, later
+	if removal.ChangeType == types.DiffChangeAdded {
+		removal, addition = addition, removal
+	}
+
+	if removal.ChangeType != types.DiffChangeRemoved || addition.ChangeType != types.DiffChangeAdded {
+		return false
+	}
+
+	if removal.OldValue == nil || addition.NewValue == nil || !removal.OldValue.Equals(addition.NewValue) {
+		return false
+	}
+
+	*earlier = diff.Difference{
+		Path:        removal.Path,
+		ChangeType:  types.DiffChangeModified,
+		OldValue:    removal.OldValue,
+		NewValue:    addition.NewValue,
+		KeyValue:    removal.KeyValue,
+		NewKeyValue: addition.KeyValue,
+	}
+	return true
+}
+
+// coalesceKeyChanges returns a mapDiffFunc that merges the removals and additions from |diffFn| that are within
+// |window| differences of each other and are of identical values.
+func coalesceKeyChanges(diffFn mapDiffFunc, window int) mapDiffFunc {
+	return func(ctx context.Context, from, to types.Map, out chan<- diff.Difference) error {
+		w := &coalescingWindow{size: window, entries: make([]coalescingEntry, 0, window+1)}
+		err := pipeDiffs(ctx, from, to, out, diffFn, w.add)
+
+		if err != nil {
+			return err
+		}
+
+		// the differences left in the window weren't merged with any later ones
+		for _, e := range w.entries {
+			select {
+			case out <- e.d:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,99 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func TestCoalescingRowDiffer(t *testing.T) {
+	ctx := context.Background()
+	vrw := types.NewMemoryValueStore()
+	nbf := vrw.Format()
+
+	// row 1 moves to key 4, with the modification of row 2 between its removal and addition
+	from := keyedTestMap(t, vrw, 0, 5, 1, 10, 2, 20, 3, 30)
+	to := keyedTestMap(t, vrw, 2, 21, 3, 30, 4, 10, 6, 60)
+
+	key := func(pk int) types.Value {
+		k, err := types.NewTuple(nbf, types.Uint(testPkTag), types.Int(pk))
+		require.NoError(t, err)
+		return k
+	}
+
+	type expectedDiff struct {
+		changeType types.DiffChangeType
+		key        int
+		newKey     int
+	}
+
+	assertDiffs := func(t *testing.T, window int, expected []expectedDiff) {
+		rd := NewRowDiffer(ctx, testKeyedSch, testKeyedSch, 8, WithCoalescing(window))
+		rd.Start(ctx, from, to)
+		diffs := drainDiffs(t, rd)
+
+		require.Len(t, diffs, len(expected))
+		for i, exp := range expected {
+			assert.Equal(t, exp.changeType, diffs[i].ChangeType)
+			assertValuesEqual(t, key(exp.key), diffs[i].KeyValue)
+			if exp.newKey != 0 {
+				assertValuesEqual(t, key(exp.newKey), diffs[i].NewKeyValue)
+				assertValuesEqual(t, diffs[i].OldValue, diffs[i].NewValue)
+			}
+		}
+	}
+
+	t.Run("adjacent only", func(t *testing.T) {
+		assertDiffs(t, 1, []expectedDiff{
+			{types.DiffChangeRemoved, 0, 0},
+			{types.DiffChangeRemoved, 1, 0},
+			{types.DiffChangeModified, 2, 0},
+			{types.DiffChangeAdded, 4, 0},
+			{types.DiffChangeAdded, 6, 0},
+		})
+	})
+
+	for _, window := range []int{2, 3, 10} {
+		t.Run(fmt.Sprintf("window of %d", window), func(t *testing.T) {
+			assertDiffs(t, window, []expectedDiff{
+				{types.DiffChangeRemoved, 0, 0},
+				{types.DiffChangeModified, 1, 4},
+				{types.DiffChangeModified, 2, 0},
+				{types.DiffChangeAdded, 6, 0},
+			})
+		})
+	}
+
+	t.Run("adjacent pair", func(t *testing.T) {
+		from := keyedTestMap(t, vrw, 1, 10, 5, 50)
+		to := keyedTestMap(t, vrw, 2, 10, 5, 50)
+
+		rd := NewRowDiffer(ctx, testKeyedSch, testKeyedSch, 8, WithCoalescing(1))
+		rd.Start(ctx, from, to)
+		diffs := drainDiffs(t, rd)
+
+		require.Len(t, diffs, 1)
+		assert.Equal(t, types.DiffChangeModified, diffs[0].ChangeType)
+		assertValuesEqual(t, key(1), diffs[0].KeyValue)
+		assertValuesEqual(t, key(2), diffs[0].NewKeyValue)
+	})
+}
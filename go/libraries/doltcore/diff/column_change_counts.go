@@ -0,0 +1,81 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"context"
+	"time"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/row"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+const (
+	// columnChangeBatchSize is the number of differences ColumnChangeCounts requests on each call to GetDiffs
+	columnChangeBatchSize = 1024
+	// columnChangePollTimeout is how long ColumnChangeCounts waits on each call to GetDiffs
+	columnChangePollTimeout = 100 * time.Millisecond
+)
+
+// ColumnChangeCounts reads every difference from |rd|, which must already be started, and counts how many
+// modifications changed each column of |sch|. The counts are keyed by the index of the column in |sch|'s columns,
+// and columns that never changed are omitted. Additions and removals are not counted. |rd| is not closed.
+func ColumnChangeCounts(ctx context.Context, rd RowDiffer, sch schema.Schema) (map[int]int, error) {
+	cols := sch.GetAllCols().GetColumns()
+	counts := make(map[int]int)
+
+	for {
+		diffs, more, err := rd.GetDiffs(columnChangeBatchSize, columnChangePollTimeout)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, d := range diffs {
+			if d.ChangeType != types.DiffChangeModified {
+				continue
+			}
+
+			oldVals, err := row.ParseTaggedValues(d.OldValue.(types.Tuple))
+			if err != nil {
+				return nil, err
+			}
+
+			newVals, err := row.ParseTaggedValues(d.NewValue.(types.Tuple))
+			if err != nil {
+				return nil, err
+			}
+
+			for i, col := range cols {
+				oldVal, _ := oldVals.Get(col.Tag)
+				newVal, _ := newVals.Get(col.Tag)
+
+				if !valuesEqual(nullToNil(oldVal), nullToNil(newVal)) {
+					counts[i]++
+				}
+			}
+		}
+
+		if !more {
+			break
+		}
+
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+	}
+
+	return counts, nil
+}
@@ -0,0 +1,84 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/row"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func TestColumnChangeCounts(t *testing.T) {
+	ctx := context.Background()
+	vrw := types.NewMemoryValueStore()
+
+	sch := schema.MustSchemaFromCols(mustColColl(
+		schema.NewColumn("pk", 0, types.IntKind, true, schema.NotNullConstraint{}),
+		schema.NewColumn("a", 1, types.IntKind, false),
+		schema.NewColumn("b", 2, types.IntKind, false),
+		schema.NewColumn("c", 3, types.IntKind, false),
+	))
+
+	// each row is pk, a, b, c, with 0 standing for a NULL b
+	buildMap := func(rows ...[4]int) types.Map {
+		m, err := types.NewMap(ctx, vrw)
+		require.NoError(t, err)
+		me := m.Edit()
+
+		for _, r := range rows {
+			vals := row.TaggedValues{0: types.Int(r[0]), 1: types.Int(r[1]), 3: types.Int(r[3])}
+			if r[2] != 0 {
+				vals[2] = types.Int(r[2])
+			}
+
+			rw, err := row.New(vrw.Format(), sch, vals)
+			require.NoError(t, err)
+			me.Set(rw.NomsMapKey(sch), rw.NomsMapValue(sch))
+		}
+
+		m, err = me.Map(ctx)
+		require.NoError(t, err)
+		return m
+	}
+
+	from := buildMap(
+		[4]int{1, 1, 1, 1},
+		[4]int{2, 1, 1, 1},
+		[4]int{3, 1, 1, 1},
+		[4]int{4, 1, 1, 1},
+		[4]int{5, 1, 1, 1},
+	)
+	to := buildMap(
+		[4]int{1, 2, 1, 1},
+		[4]int{2, 2, 0, 1},
+		[4]int{3, 2, 2, 1},
+		[4]int{4, 1, 1, 1},
+		[4]int{6, 9, 9, 9},
+	)
+
+	rd := NewRowDiffer(ctx, sch, sch, 4)
+	defer rd.Close()
+	rd.Start(ctx, from, to)
+
+	counts, err := ColumnChangeCounts(ctx, rd, sch)
+	require.NoError(t, err)
+	assert.Equal(t, map[int]int{1: 3, 2: 2}, counts)
+}
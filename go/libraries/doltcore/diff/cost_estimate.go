@@ -0,0 +1,120 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"context"
+
+	"github.com/dolthub/dolt/go/store/hash"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// DiffCostEstimate is a rough measure of how expensive it is to diff two maps.
+type DiffCostEstimate struct {
+	// DifferingChunks is the number of chunks referenced by either root that the other root does not reference.
+	DifferingChunks int
+	// Differences is an estimate of the number of differences between the maps.
+	Differences uint64
+	// BytesToRead is an estimate of the number of bytes of chunk data a full diff reads.
+	BytesToRead uint64
+}
+
+// EstimateDiffCost estimates the cost of diffing |from| and |to| by comparing the chunks referenced by their roots
+// rather than descending into them. Every entry beneath a differing chunk is assumed to differ, so the estimate
+// tends to overcount small edits spread across many chunks. Apart from the roots, at most one path to a leaf is
+// read, to measure the average encoded size of an entry.
+func EstimateDiffCost(ctx context.Context, from, to types.Map) (DiffCostEstimate, error) {
+	if from.Equals(to) {
+		return DiffCostEstimate{}, nil
+	}
+
+	fromChunks, err := from.RootChunks()
+	if err != nil {
+		return DiffCostEstimate{}, err
+	}
+
+	toChunks, err := to.RootChunks()
+	if err != nil {
+		return DiffCostEstimate{}, err
+	}
+
+	fromChunkCount, fromLeaves := countUnshared(fromChunks, toChunks)
+	toChunkCount, toLeaves := countUnshared(toChunks, fromChunks)
+
+	sample := from
+	if sample.Len() == 0 {
+		sample = to
+	}
+
+	bytesPerEntry, err := averageEntrySize(ctx, sample)
+	if err != nil {
+		return DiffCostEstimate{}, err
+	}
+
+	differences := fromLeaves
+	if toLeaves > differences {
+		differences = toLeaves
+	}
+
+	return DiffCostEstimate{
+		DifferingChunks: fromChunkCount + toChunkCount,
+		Differences:     differences,
+		BytesToRead:     (fromLeaves + toLeaves) * bytesPerEntry,
+	}, nil
+}
+
+// countUnshared returns the number of chunks in |chunks| that are not in |other|, and the number of entries
+// beneath them.
+func countUnshared(chunks, other []types.MapChunkSummary) (int, uint64) {
+	shared := make(hash.HashSet, len(other))
+	for _, c := range other {
+		shared.Insert(c.Hash)
+	}
+
+	count := 0
+	leaves := uint64(0)
+	for _, c := range chunks {
+		if !shared.Has(c.Hash) {
+			count++
+			leaves += c.NumLeaves
+		}
+	}
+
+	return count, leaves
+}
+
+// averageEntrySize returns the average encoded size of an entry in the first leaf of |m|.
+func averageEntrySize(ctx context.Context, m types.Map) (uint64, error) {
+	if m.Len() == 0 {
+		return 0, nil
+	}
+
+	leaves, _, err := types.LoadLeafNodes(ctx, []types.Collection{m}, 0, 1)
+	if err != nil {
+		return 0, err
+	}
+
+	leaf := leaves[0]
+	if leaf.Len() == 0 {
+		return 0, nil
+	}
+
+	c, err := types.EncodeValue(leaf, m.Format())
+	if err != nil {
+		return 0, err
+	}
+
+	return uint64(len(c.Data())) / leaf.Len(), nil
+}
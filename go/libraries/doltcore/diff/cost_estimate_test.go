@@ -0,0 +1,81 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func TestEstimateDiffCost(t *testing.T) {
+	ctx := context.Background()
+	vrw := types.NewMemoryValueStore()
+
+	const numRows = 20000
+	editedMap := func(numEdits int) types.Map {
+		pkVals := make([]int, 0, 2*numRows)
+		for i := 0; i < numRows; i++ {
+			val := i
+			if numEdits > 0 && i%(numRows/numEdits) == 0 {
+				val = -i - 1
+			}
+			pkVals = append(pkVals, i, val)
+		}
+		return keyedTestMap(t, vrw, pkVals...)
+	}
+
+	from := editedMap(0)
+
+	est, err := EstimateDiffCost(ctx, from, from)
+	require.NoError(t, err)
+	assert.Equal(t, DiffCostEstimate{}, est)
+
+	var prev DiffCostEstimate
+	for _, numEdits := range []int{1, 10, 100, 1000, numRows} {
+		to := editedMap(numEdits)
+
+		rd := NewAsyncDiffer(64)
+		rd.Start(ctx, from, to)
+		actual := uint64(len(drainDiffs(t, rd)))
+		require.Equal(t, uint64(numEdits), actual)
+
+		est, err := EstimateDiffCost(ctx, from, to)
+		require.NoError(t, err)
+
+		assert.True(t, est.DifferingChunks > 0)
+		assert.True(t, est.Differences >= actual, "estimated %d differences, actual %d", est.Differences, actual)
+		assert.True(t, est.Differences <= numRows)
+		assert.True(t, est.Differences >= prev.Differences)
+		assert.True(t, est.BytesToRead >= prev.BytesToRead)
+		assert.True(t, est.BytesToRead > 0)
+		prev = est
+	}
+
+	// A single edit touches far fewer chunks than rewriting every row.
+	single, err := EstimateDiffCost(ctx, from, editedMap(1))
+	require.NoError(t, err)
+	assert.True(t, single.Differences*10 < prev.Differences)
+	assert.True(t, single.BytesToRead*10 < prev.BytesToRead)
+
+	empty := keyedTestMap(t, vrw)
+	est, err = EstimateDiffCost(ctx, empty, from)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(numRows), est.Differences)
+}
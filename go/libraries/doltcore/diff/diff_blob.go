@@ -0,0 +1,194 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"github.com/dolthub/dolt/go/store/chunks"
+	"github.com/dolthub/dolt/go/store/diff"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// A diff blob holds a sequence of differences, each encoded as a Tuple of diffBlobFieldCount fields preceded by
+// the length of its encoding as a big endian uint32. Nil values are encoded as NULL.
+const (
+	diffBlobPathIdx = iota
+	diffBlobChangeTypeIdx
+	diffBlobKeyIdx
+	diffBlobOldIdx
+	diffBlobNewIdx
+	diffBlobNewKeyIdx
+	diffBlobRootKeyIdx
+	diffBlobFieldCount
+)
+
+// DiffToBlob reads every difference from |rd|, which must already be started, and writes them to a Blob in |vrw|,
+// so that a complete diff can be stored as a value. The differences can be read back with BlobToDiffIterator.
+// |rd| is not closed.
+func DiffToBlob(ctx context.Context, rd RowDiffer, vrw types.ValueReadWriter) (types.Blob, error) {
+	pr, pw := io.Pipe()
+
+	go func() {
+		wr := bufio.NewWriter(pw)
+		err := ForEach(ctx, rd, func(d *diff.Difference) error {
+			return writeBlobDiff(vrw.Format(), wr, d)
+		})
+
+		if err == nil {
+			err = wr.Flush()
+		}
+
+		_ = pw.CloseWithError(err)
+	}()
+
+	blob, err := types.NewBlob(ctx, vrw, pr)
+
+	if err != nil {
+		_ = pr.CloseWithError(err)
+		return types.Blob{}, err
+	}
+
+	return blob, nil
+}
+
+func writeBlobDiff(nbf *types.NomsBinFormat, wr io.Writer, d *diff.Difference) error {
+	fields := make([]types.Value, diffBlobFieldCount)
+	fields[diffBlobPathIdx] = types.String(d.Path.String())
+	fields[diffBlobChangeTypeIdx] = types.Uint(d.ChangeType)
+	fields[diffBlobKeyIdx] = nilToNull(d.KeyValue)
+	fields[diffBlobOldIdx] = nilToNull(d.OldValue)
+	fields[diffBlobNewIdx] = nilToNull(d.NewValue)
+	fields[diffBlobNewKeyIdx] = nilToNull(d.NewKeyValue)
+	fields[diffBlobRootKeyIdx] = nilToNull(d.RootKeyValue)
+
+	tup, err := types.NewTuple(nbf, fields...)
+	if err != nil {
+		return err
+	}
+
+	c, err := types.EncodeValue(tup, nbf)
+	if err != nil {
+		return err
+	}
+
+	var size [4]byte
+	binary.BigEndian.PutUint32(size[:], uint32(len(c.Data())))
+
+	if _, err := wr.Write(size[:]); err != nil {
+		return err
+	}
+
+	_, err = wr.Write(c.Data())
+	return err
+}
+
+// BlobDiffIterator reads the differences stored in a Blob by DiffToBlob.
+type BlobDiffIterator struct {
+	rd  *bufio.Reader
+	vrw types.ValueReadWriter
+}
+
+// BlobToDiffIterator returns a BlobDiffIterator over the differences stored in |blob| by DiffToBlob. Values are
+// read using |vrw|.
+func BlobToDiffIterator(ctx context.Context, blob types.Blob, vrw types.ValueReadWriter) *BlobDiffIterator {
+	return newBlobDiffIterator(blob.Reader(ctx), vrw)
+}
+
+func newBlobDiffIterator(rd io.Reader, vrw types.ValueReadWriter) *BlobDiffIterator {
+	return &BlobDiffIterator{rd: bufio.NewReader(rd), vrw: vrw}
+}
+
+// Next returns the next difference, in the order they were written, or io.EOF once every difference has been read.
+func (itr *BlobDiffIterator) Next() (*diff.Difference, error) {
+	tup, err := itr.nextRecord()
+	if err != nil {
+		return nil, err
+	}
+
+	fields, err := tup.AsSlice()
+	if err != nil {
+		return nil, err
+	}
+
+	path, err := parseBlobDiffPath(fields[diffBlobPathIdx])
+	if err != nil {
+		return nil, err
+	}
+
+	return &diff.Difference{
+		Path:         path,
+		ChangeType:   types.DiffChangeType(fields[diffBlobChangeTypeIdx].(types.Uint)),
+		KeyValue:     nullToNil(fields[diffBlobKeyIdx]),
+		OldValue:     nullToNil(fields[diffBlobOldIdx]),
+		NewValue:     nullToNil(fields[diffBlobNewIdx]),
+		NewKeyValue:  nullToNil(fields[diffBlobNewKeyIdx]),
+		RootKeyValue: nullToNil(fields[diffBlobRootKeyIdx]),
+	}, nil
+}
+
+// nextRecord returns the Tuple encoding the next difference without decoding its fields, or io.EOF once every
+// difference has been read.
+func (itr *BlobDiffIterator) nextRecord() (types.Tuple, error) {
+	var size [4]byte
+
+	if _, err := io.ReadFull(itr.rd, size[:]); err != nil {
+		return types.Tuple{}, err
+	}
+
+	data := make([]byte, binary.BigEndian.Uint32(size[:]))
+	if _, err := io.ReadFull(itr.rd, data); err == io.EOF {
+		return types.Tuple{}, io.ErrUnexpectedEOF
+	} else if err != nil {
+		return types.Tuple{}, err
+	}
+
+	v, err := types.DecodeValue(chunks.NewChunk(data), itr.vrw)
+	if err != nil {
+		return types.Tuple{}, err
+	}
+
+	tup, ok := v.(types.Tuple)
+	if !ok || tup.Len() != diffBlobFieldCount {
+		return types.Tuple{}, fmt.Errorf("corrupt diff blob: unexpected value of kind %s", v.Kind().String())
+	}
+
+	return tup, nil
+}
+
+func parseBlobDiffPath(field types.Value) (types.Path, error) {
+	if pathStr := string(field.(types.String)); pathStr != "" {
+		return types.ParsePath(pathStr)
+	}
+	return nil, nil
+}
+
+func nilToNull(v types.Value) types.Value {
+	if v == nil {
+		return types.NullValue
+	}
+	return v
+}
+
+func nullToNil(v types.Value) types.Value {
+	if types.IsNull(v) {
+		return nil
+	}
+	return v
+}
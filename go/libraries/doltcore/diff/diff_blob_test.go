@@ -0,0 +1,79 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/store/diff"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func TestDiffBlobRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	vrw := types.NewMemoryValueStore()
+
+	readAll := func(itr *BlobDiffIterator) []*diff.Difference {
+		var diffs []*diff.Difference
+		for {
+			d, err := itr.Next()
+			if err == io.EOF {
+				return diffs
+			}
+			require.NoError(t, err)
+			diffs = append(diffs, d)
+		}
+	}
+
+	from := keyedTestMap(t, vrw, 1, 1, 2, 2, 3, 3)
+	to := keyedTestMap(t, vrw, 1, 10, 3, 3, 4, 4)
+
+	rd := NewRowDiffer(ctx, testKeyedSch, testKeyedSch, 8)
+	rd.Start(ctx, from, to)
+	expected := drainDiffs(t, rd)
+	require.Len(t, expected, 3)
+
+	rd = NewRowDiffer(ctx, testKeyedSch, testKeyedSch, 8)
+	rd.Start(ctx, from, to)
+	blob, err := DiffToBlob(ctx, rd, vrw)
+	require.NoError(t, err)
+	require.NoError(t, rd.Close())
+
+	// store the blob and read it back, as an audit log would
+	ref, err := vrw.WriteValue(ctx, blob)
+	require.NoError(t, err)
+	stored, err := vrw.ReadValue(ctx, ref.TargetHash())
+	require.NoError(t, err)
+
+	replayed := readAll(BlobToDiffIterator(ctx, stored.(types.Blob), vrw))
+	assertDiffsEqual(t, expected, replayed)
+	for i := range expected {
+		assert.Equal(t, expected[i].Path.String(), replayed[i].Path.String())
+		assertValuesEqual(t, expected[i].RootKeyValue, replayed[i].RootKeyValue)
+	}
+
+	rd = NewRowDiffer(ctx, testKeyedSch, testKeyedSch, 8)
+	rd.Start(ctx, from, from)
+	blob, err = DiffToBlob(ctx, rd, vrw)
+	require.NoError(t, err)
+	require.NoError(t, rd.Close())
+	assert.Equal(t, uint64(0), blob.Len())
+	assert.Empty(t, readAll(BlobToDiffIterator(ctx, blob, vrw)))
+}
@@ -0,0 +1,114 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/dolthub/dolt/go/store/diff"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+const (
+	// diffBufferPollTimeout is how long NewDiffBuffer waits on each call to GetDiffs
+	diffBufferPollTimeout = 100 * time.Millisecond
+	// diffBufferBatchSize is the number of differences NewDiffBuffer requests on each call to GetDiffs
+	diffBufferBatchSize = 1024
+)
+
+// DiffBuffer holds every difference read from a RowDiffer, ordered by key, for point lookups into a materialized
+// diff.
+type DiffBuffer struct {
+	nbf   *types.NomsBinFormat
+	diffs []*diff.Difference
+}
+
+// NewDiffBuffer reads every difference from |rd|, which must already be started, and returns them in a DiffBuffer.
+// Differences with equal keys, such as the copies of a keyless row, are kept in the order |rd| returned them. |rd|
+// is not closed.
+func NewDiffBuffer(ctx context.Context, nbf *types.NomsBinFormat, rd RowDiffer) (*DiffBuffer, error) {
+	var diffs []*diff.Difference
+	for {
+		batch, more, err := rd.GetDiffs(diffBufferBatchSize, diffBufferPollTimeout)
+		if err != nil {
+			return nil, err
+		}
+
+		diffs = append(diffs, batch...)
+
+		if !more {
+			break
+		}
+
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+	}
+
+	var sortErr error
+	sort.SliceStable(diffs, func(i, j int) bool {
+		if sortErr != nil {
+			return false
+		}
+
+		isLess, err := diffs[i].KeyValue.Less(nbf, diffs[j].KeyValue)
+		if err != nil {
+			sortErr = err
+		}
+		return isLess
+	})
+
+	if sortErr != nil {
+		return nil, sortErr
+	}
+
+	return &DiffBuffer{nbf: nbf, diffs: diffs}, nil
+}
+
+// Len returns the number of differences in the buffer.
+func (db *DiffBuffer) Len() int {
+	return len(db.diffs)
+}
+
+// Diffs returns the buffered differences, ordered by key. The slice must not be modified.
+func (db *DiffBuffer) Diffs() []*diff.Difference {
+	return db.diffs
+}
+
+// Find returns the first difference whose key is |key|, and whether there is one. It does a binary search, so it
+// takes O(log n) key comparisons. A key that can't be compared to the buffer's keys is not found.
+func (db *DiffBuffer) Find(key types.Value) (*diff.Difference, bool) {
+	var cmpErr error
+	i := sort.Search(len(db.diffs), func(i int) bool {
+		if cmpErr != nil {
+			return true
+		}
+
+		isLess, err := db.diffs[i].KeyValue.Less(db.nbf, key)
+		if err != nil {
+			cmpErr = err
+			return true
+		}
+		return !isLess
+	})
+
+	if cmpErr != nil || i == len(db.diffs) || !db.diffs[i].KeyValue.Equals(key) {
+		return nil, false
+	}
+
+	return db.diffs[i], true
+}
@@ -0,0 +1,119 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/store/diff"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func TestDiffBufferFind(t *testing.T) {
+	ctx := context.Background()
+	vrw := types.NewMemoryValueStore()
+	nbf := vrw.Format()
+
+	var fromVals, toVals []int
+	for i := 0; i < 300; i++ {
+		switch i % 5 {
+		case 0:
+			fromVals = append(fromVals, i, i)
+		case 1:
+			toVals = append(toVals, i, i)
+		case 2:
+			fromVals = append(fromVals, i, i)
+			toVals = append(toVals, i, -i)
+		default:
+			fromVals = append(fromVals, i, i)
+			toVals = append(toVals, i, i)
+		}
+	}
+	from, to := keyedTestMap(t, vrw, fromVals...), keyedTestMap(t, vrw, toVals...)
+
+	rd := NewRowDiffer(ctx, testKeyedSch, testKeyedSch, 16)
+	rd.Start(ctx, from, to)
+	db, err := NewDiffBuffer(ctx, nbf, rd)
+	require.NoError(t, err)
+	require.NoError(t, rd.Close())
+	require.Equal(t, 180, db.Len())
+
+	linearFind := func(key types.Value) (*diff.Difference, bool) {
+		for _, d := range db.Diffs() {
+			if d.KeyValue.Equals(key) {
+				return d, true
+			}
+		}
+		return nil, false
+	}
+
+	for i := -1; i <= 300; i++ {
+		key, err := types.NewTuple(nbf, types.Uint(testPkTag), types.Int(i))
+		require.NoError(t, err)
+
+		expected, expectedOk := linearFind(key)
+		actual, ok := db.Find(key)
+		require.Equal(t, expectedOk, ok, "key %d", i)
+		assert.Equal(t, expected, actual, "key %d", i)
+
+		switch {
+		case i < 0 || i == 300 || i%5 > 2:
+			assert.False(t, ok, "key %d didn't change", i)
+		case i%5 == 0:
+			assert.Equal(t, types.DiffChangeRemoved, actual.ChangeType)
+		case i%5 == 1:
+			assert.Equal(t, types.DiffChangeAdded, actual.ChangeType)
+		case i%5 == 2:
+			assert.Equal(t, types.DiffChangeModified, actual.ChangeType)
+		}
+	}
+
+	_, ok := db.Find(types.String("not a key"))
+	assert.False(t, ok)
+}
+
+func TestDiffBufferFindKeyless(t *testing.T) {
+	ctx := context.Background()
+	vrw := types.NewMemoryValueStore()
+	nbf := vrw.Format()
+
+	from := keylessTestMap(t, vrw, 1, 1, 2, 5)
+	to := keylessTestMap(t, vrw, 2, 2, 3, 4)
+
+	rd := NewRowDiffer(ctx, testKeylessSch, testKeylessSch, 16)
+	rd.Start(ctx, from, to)
+	db, err := NewDiffBuffer(ctx, nbf, rd)
+	require.NoError(t, err)
+	require.NoError(t, rd.Close())
+	require.Equal(t, 1+3+4, db.Len())
+
+	// the first copy of a row is found
+	key, err := types.NewTuple(nbf, types.Uint(schema.KeylessRowIdTag), types.Int(2))
+	require.NoError(t, err)
+	d, ok := db.Find(key)
+	require.True(t, ok)
+	assert.Same(t, db.Diffs()[1], d)
+	assert.Equal(t, types.DiffChangeRemoved, d.ChangeType)
+
+	key, err = types.NewTuple(nbf, types.Uint(schema.KeylessRowIdTag), types.Int(4))
+	require.NoError(t, err)
+	_, ok = db.Find(key)
+	assert.False(t, ok)
+}
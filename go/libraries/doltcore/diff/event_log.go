@@ -0,0 +1,132 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/dolthub/dolt/go/store/diff"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// DiffEventKind is the kind of a DiffEvent.
+type DiffEventKind int
+
+const (
+	// DiffStarted is logged when the diff starts
+	DiffStarted DiffEventKind = iota
+	// DiffFirstDifference is logged when the first difference is found
+	DiffFirstDifference
+	// DiffProgressed is logged every reporting interval while the diff runs
+	DiffProgressed
+	// DiffCompleted is logged when every difference has been found
+	DiffCompleted
+	// DiffFailed is logged when the diff stops early, including when it is cancelled or closed
+	DiffFailed
+)
+
+func (k DiffEventKind) String() string {
+	switch k {
+	case DiffStarted:
+		return "started"
+	case DiffFirstDifference:
+		return "first difference"
+	case DiffProgressed:
+		return "progressed"
+	case DiffCompleted:
+		return "completed"
+	case DiffFailed:
+		return "failed"
+	default:
+		return fmt.Sprintf("unknown(%d)", int(k))
+	}
+}
+
+// DiffEvent is an event in the life of a diff.
+type DiffEvent struct {
+	Kind DiffEventKind
+	// Elapsed is the time since the diff started
+	Elapsed time.Duration
+	// Differences is the number of differences found so far. For keyless tables a changed row is counted once
+	// regardless of its cardinality
+	Differences uint64
+	// Err is the error that stopped the diff, for DiffFailed events
+	Err error
+}
+
+// DiffEventLogger receives the events logged by a RowDiffer created with WithEventLog. Events are logged one at a time,
+// in order, from the goroutine running the diff, so a slow logger slows the diff.
+type DiffEventLogger interface {
+	LogDiffEvent(e DiffEvent)
+}
+
+// WithEventLog makes a RowDiffer log the start of the diff, its first difference, progress every |interval| while it
+// runs, and its completion or failure, to |logger|.
+func WithEventLog(logger DiffEventLogger, interval time.Duration) RowDifferOption {
+	return func(o *rowDifferOptions) {
+		el := &eventLog{logger: logger, interval: interval}
+		o.wrap(el.wrap)
+	}
+}
+
+type eventLog struct {
+	logger   DiffEventLogger
+	interval time.Duration
+	start    time.Time
+	count    uint64
+}
+
+// wrap returns a mapDiffFunc that runs |diffFn|, logging events as it does.
+func (el *eventLog) wrap(diffFn mapDiffFunc) mapDiffFunc {
+	return func(ctx context.Context, from, to types.Map, out chan<- diff.Difference) error {
+		el.start = time.Now()
+		el.log(DiffStarted, nil)
+
+		err := el.diff(ctx, from, to, out, diffFn)
+
+		if err != nil {
+			el.log(DiffFailed, err)
+		} else {
+			el.log(DiffCompleted, nil)
+		}
+
+		return err
+	}
+}
+
+func (el *eventLog) diff(ctx context.Context, from, to types.Map, out chan<- diff.Difference, diffFn mapDiffFunc) error {
+	progressed := func() {
+		el.log(DiffProgressed, nil)
+	}
+
+	return pipeDiffsWithTicks(ctx, from, to, out, diffFn, el.interval, progressed, func(d diff.Difference, send func(diff.Difference) error) error {
+		el.count++
+		if el.count == 1 {
+			el.log(DiffFirstDifference, nil)
+		}
+		return send(d)
+	})
+}
+
+func (el *eventLog) log(kind DiffEventKind, err error) {
+	el.logger.LogDiffEvent(DiffEvent{
+		Kind:        kind,
+		Elapsed:     time.Since(el.start),
+		Differences: el.count,
+		Err:         err,
+	})
+}
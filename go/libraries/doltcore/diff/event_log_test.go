@@ -0,0 +1,122 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+type recordingEventLogger struct {
+	mu     sync.Mutex
+	events []DiffEvent
+}
+
+func (l *recordingEventLogger) LogDiffEvent(e DiffEvent) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.events = append(l.events, e)
+}
+
+// kinds returns the kinds of the events logged, leaving out progress events, whose number depends on timing.
+func (l *recordingEventLogger) kinds() []DiffEventKind {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var kinds []DiffEventKind
+	for _, e := range l.events {
+		if e.Kind != DiffProgressed {
+			kinds = append(kinds, e.Kind)
+		}
+	}
+	return kinds
+}
+
+func (l *recordingEventLogger) last() DiffEvent {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.events[len(l.events)-1]
+}
+
+func TestRowDifferWithEventLog(t *testing.T) {
+	ctx := context.Background()
+	vrw := types.NewMemoryValueStore()
+
+	from := keyedTestMap(t, vrw, 1, 1, 2, 2, 3, 3)
+	to := keyedTestMap(t, vrw, 1, 1, 2, 20, 4, 4)
+
+	logger := &recordingEventLogger{}
+	rd := NewRowDiffer(ctx, testKeyedSch, testKeyedSch, 8, WithEventLog(logger, time.Millisecond))
+	rd.Start(ctx, from, to)
+	assert.Len(t, drainDiffs(t, rd), 3)
+
+	assert.Equal(t, []DiffEventKind{DiffStarted, DiffFirstDifference, DiffCompleted}, logger.kinds())
+
+	logger.mu.Lock()
+	defer logger.mu.Unlock()
+	var elapsed time.Duration
+	for _, e := range logger.events {
+		assert.True(t, e.Elapsed >= elapsed, "events should be logged in order")
+		elapsed = e.Elapsed
+		assert.NoError(t, e.Err)
+
+		switch e.Kind {
+		case DiffStarted:
+			assert.Equal(t, uint64(0), e.Differences)
+		case DiffFirstDifference:
+			assert.Equal(t, uint64(1), e.Differences)
+		case DiffCompleted:
+			assert.Equal(t, uint64(3), e.Differences)
+		}
+	}
+}
+
+func TestRowDifferWithEventLogCancelled(t *testing.T) {
+	ctx := context.Background()
+	vrw := types.NewMemoryValueStore()
+
+	var kvs []int
+	for i := 0; i < 1000; i++ {
+		kvs = append(kvs, i, i)
+	}
+	from := keyedTestMap(t, vrw)
+	to := keyedTestMap(t, vrw, kvs...)
+
+	logger := &recordingEventLogger{}
+	rd := NewRowDiffer(ctx, testKeyedSch, testKeyedSch, 1, WithEventLog(logger, time.Hour))
+	rd.Start(ctx, from, to)
+
+	diffs, more, err := rd.GetDiffs(1, time.Second)
+	require.NoError(t, err)
+	require.Len(t, diffs, 1)
+	require.True(t, more)
+
+	// closing the differ before the diff is done cancels it
+	_ = rd.Close()
+
+	assert.Equal(t, []DiffEventKind{DiffStarted, DiffFirstDifference, DiffFailed}, logger.kinds())
+	last := logger.last()
+	assert.True(t, errors.Is(last.Err, context.Canceled))
+	assert.True(t, last.Differences >= 1)
+	assert.True(t, last.Differences < 1000)
+}
@@ -0,0 +1,133 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"time"
+
+	"github.com/dolthub/dolt/go/store/diff"
+)
+
+// BufferFillingRowDiffer is a RowDiffer that can return differences in a buffer provided by the caller.
+type BufferFillingRowDiffer interface {
+	RowDiffer
+
+	// FillDiffs is GetDiffs for up to len(|buf|) differences, which are written to the start of |buf| rather than
+	// to a newly allocated slice. It returns the number of differences written. Non-nil entries of |buf| are
+	// overwritten in place, so differences from a previous call are no longer valid once |buf| is reused.
+	FillDiffs(buf []*diff.Difference, timeout time.Duration) (n int, more bool, err error)
+}
+
+var _ BufferFillingRowDiffer = &AsyncDiffer{}
+var _ BufferFillingRowDiffer = &keylessDiffer{}
+
+// FillDiffs implements BufferFillingRowDiffer.
+func (ad *AsyncDiffer) FillDiffs(buf []*diff.Difference, timeout time.Duration) (int, bool, error) {
+	if len(buf) == 0 {
+		return 0, true, nil
+	}
+
+	n := 0
+	timeoutChan := time.After(timeout)
+	for {
+		select {
+		case d, more := <-ad.diffChan:
+			if !more {
+				return n, false, ad.incompleteErr(ad.eg.Wait())
+			}
+
+			fillDiff(buf, n, d)
+			n++
+			if n == len(buf) {
+				return n, true, nil
+			}
+		case <-timeoutChan:
+			return n, true, nil
+		case <-ad.egCtx.Done():
+			err := ad.eg.Wait()
+			if ad.keepPartial {
+				return n, false, ad.incompleteErr(err)
+			}
+			return 0, false, err
+		}
+	}
+}
+
+// FillDiffs implements BufferFillingRowDiffer.
+func (kd *keylessDiffer) FillDiffs(buf []*diff.Difference, timeout time.Duration) (int, bool, error) {
+	if len(buf) == 0 {
+		return 0, true, nil
+	}
+
+	n := 0
+	timeoutChan := time.After(timeout)
+	for {
+		// first fill |buf| with copies of |kd.df|
+		for n < len(buf) && kd.copiesLeft > 0 {
+			fillDiff(buf, n, kd.df)
+			n++
+			kd.copiesLeft--
+		}
+		if n == len(buf) {
+			return n, true, nil
+		}
+
+		// then get another Difference
+		select {
+		case <-timeoutChan:
+			return n, true, nil
+
+		case <-kd.egCtx.Done():
+			err := kd.eg.Wait()
+			if kd.keepPartial {
+				return n, false, kd.incompleteErr(err)
+			}
+			return 0, false, err
+
+		case d, more := <-kd.rawDiffs():
+			if !more {
+				if kd.keepPartial {
+					return n, false, kd.incompleteErr(kd.eg.Wait())
+				}
+				return n, false, nil
+			}
+
+			var card uint64
+			var err error
+			kd.df, card, err = convertDiff(d, kd.formatKey)
+			if err != nil {
+				return 0, false, err
+			}
+			kd.setCopies(card)
+
+		case c, ok := <-kd.converted:
+			if !ok {
+				return n, false, kd.incompleteErr(kd.eg.Wait())
+			}
+
+			kd.df = c.df
+			kd.setCopies(c.card)
+		}
+	}
+}
+
+// fillDiff writes |d| to |buf|[|i|], reusing the Difference already there if there is one.
+func fillDiff(buf []*diff.Difference, i int, d diff.Difference) {
+	if buf[i] == nil {
+		buf[i] = &d
+	} else {
+		*buf[i] = d
+	}
+}
@@ -0,0 +1,95 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/store/diff"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func TestFillDiffs(t *testing.T) {
+	ctx := context.Background()
+	vrw := types.NewMemoryValueStore()
+
+	var fromVals, toVals []int
+	for i := 0; i < 50; i++ {
+		fromVals = append(fromVals, i, i)
+		toVals = append(toVals, i, -i-1)
+	}
+
+	tests := []struct {
+		name     string
+		sch      schema.Schema
+		from, to types.Map
+	}{
+		{
+			name: "keyed",
+			sch:  testKeyedSch,
+			from: keyedTestMap(t, vrw, fromVals...),
+			to:   keyedTestMap(t, vrw, toVals...),
+		},
+		{
+			name: "keyless",
+			sch:  testKeylessSch,
+			from: keylessTestMap(t, vrw, 1, 1, 2, 8, 3, 2),
+			to:   keylessTestMap(t, vrw, 1, 9, 3, 2, 4, 5),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rd := NewRowDiffer(ctx, test.sch, test.sch, 4)
+			rd.Start(ctx, test.from, test.to)
+			expected := drainDiffs(t, rd)
+			require.NotEmpty(t, expected)
+
+			rd = NewRowDiffer(ctx, test.sch, test.sch, 4)
+			rd.Start(ctx, test.from, test.to)
+			frd := rd.(BufferFillingRowDiffer)
+
+			buf := make([]*diff.Difference, 7)
+			var actual []diff.Difference
+			for {
+				n, more, err := frd.FillDiffs(buf, time.Second)
+				require.NoError(t, err)
+
+				for _, d := range buf[:n] {
+					actual = append(actual, *d)
+				}
+
+				if !more {
+					break
+				}
+			}
+			require.NoError(t, rd.Close())
+
+			require.Equal(t, len(expected), len(actual))
+			for i := range expected {
+				assert.Equal(t, expected[i].ChangeType, actual[i].ChangeType)
+				assertValuesEqual(t, expected[i].KeyValue, actual[i].KeyValue)
+				assertValuesEqual(t, expected[i].OldValue, actual[i].OldValue)
+				assertValuesEqual(t, expected[i].NewValue, actual[i].NewValue)
+			}
+		})
+	}
+}
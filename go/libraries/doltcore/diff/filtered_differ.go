@@ -0,0 +1,79 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"context"
+	"time"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/row"
+	"github.com/dolthub/dolt/go/store/diff"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// ValuePredicate reports whether a difference should be kept, given its new value. |newVal| is nil for removed rows.
+type ValuePredicate func(newVal types.Value) (bool, error)
+
+// WithValueFilter makes a RowDiffer emit only the differences whose new value satisfies |pred|.
+func WithValueFilter(pred ValuePredicate) RowDifferOption {
+	return func(o *rowDifferOptions) {
+		o.wrap(func(diffFn mapDiffFunc) mapDiffFunc {
+			return filterDiffs(diffFn, pred)
+		})
+	}
+}
+
+// WithSince makes a RowDiffer emit only the differences whose new value has a timestamp after |cutoff| in the column
+// with tag |tag|. Removed rows, and rows where the column is missing or null, are dropped.
+func WithSince(tag uint64, cutoff time.Time) RowDifferOption {
+	return WithValueFilter(SincePredicate(tag, cutoff))
+}
+
+// SincePredicate returns a ValuePredicate that keeps values whose timestamp column with tag |tag| is after |cutoff|.
+func SincePredicate(tag uint64, cutoff time.Time) ValuePredicate {
+	return func(newVal types.Value) (bool, error) {
+		if types.IsNull(newVal) {
+			return false, nil
+		}
+
+		taggedVals, err := row.ParseTaggedValues(newVal.(types.Tuple))
+		if err != nil {
+			return false, err
+		}
+
+		ts, ok := taggedVals.Get(tag)
+		if !ok || ts.Kind() != types.TimestampKind {
+			return false, nil
+		}
+
+		return time.Time(ts.(types.Timestamp)).After(cutoff), nil
+	}
+}
+
+func filterDiffs(diffFn mapDiffFunc, pred ValuePredicate) mapDiffFunc {
+	return func(ctx context.Context, from, to types.Map, out chan<- diff.Difference) error {
+		return pipeDiffs(ctx, from, to, out, diffFn, func(d diff.Difference, send func(diff.Difference) error) error {
+			keep, err := pred(d.NewValue)
+			if err != nil {
+				return err
+			}
+
+			if !keep {
+				return nil
+			}
+			return send(d)
+		})
+	}
+}
@@ -0,0 +1,98 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+const testUpdatedAtTag = 2
+
+var testUpdatedAtSch = schema.MustSchemaFromCols(mustColColl(
+	schema.NewColumn("pk", testPkTag, types.IntKind, true, schema.NotNullConstraint{}),
+	schema.NewColumn("val", testValTag, types.IntKind, false),
+	schema.NewColumn("updated_at", testUpdatedAtTag, types.TimestampKind, false),
+))
+
+// updatedAtTestMap builds a row map for |testUpdatedAtSch| from |vals| and |updatedAt|, both keyed by pk.
+func updatedAtTestMap(t *testing.T, vrw types.ValueReadWriter, vals map[int]int, updatedAt map[int]time.Time) types.Map {
+	kvs := make([]types.Value, 0, 2*len(vals))
+	for pk, val := range vals {
+		k, err := types.NewTuple(vrw.Format(), types.Uint(testPkTag), types.Int(pk))
+		require.NoError(t, err)
+		v, err := types.NewTuple(vrw.Format(),
+			types.Uint(testValTag), types.Int(val),
+			types.Uint(testUpdatedAtTag), types.Timestamp(updatedAt[pk]))
+		require.NoError(t, err)
+		kvs = append(kvs, k, v)
+	}
+
+	m, err := types.NewMap(context.Background(), vrw, kvs...)
+	require.NoError(t, err)
+	return m
+}
+
+func TestSinceRowDiffer(t *testing.T) {
+	ctx := context.Background()
+	vrw := types.NewMemoryValueStore()
+
+	cutoff := time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+	before := cutoff.Add(-time.Hour)
+	after := cutoff.Add(time.Hour)
+
+	from := updatedAtTestMap(t, vrw,
+		map[int]int{1: 1, 2: 2, 3: 3, 4: 4},
+		map[int]time.Time{1: before, 2: before, 3: before, 4: before})
+	to := updatedAtTestMap(t, vrw,
+		map[int]int{1: 10, 2: 20, 3: 3, 5: 5, 6: 6},
+		map[int]time.Time{1: after, 2: before, 3: cutoff, 5: after, 6: before})
+
+	rd := NewRowDiffer(ctx, testUpdatedAtSch, testUpdatedAtSch, 8, WithSince(testUpdatedAtTag, cutoff))
+	rd.Start(ctx, from, to)
+	diffs := drainDiffs(t, rd)
+
+	require.Len(t, diffs, 2)
+	assert.Equal(t, types.DiffChangeModified, diffs[0].ChangeType)
+	assertValuesEqual(t, types.Int(1), mustTupleGet(t, diffs[0].KeyValue.(types.Tuple), 1))
+	assert.Equal(t, types.DiffChangeAdded, diffs[1].ChangeType)
+	assertValuesEqual(t, types.Int(5), mustTupleGet(t, diffs[1].KeyValue.(types.Tuple), 1))
+}
+
+func TestFilteredRowDiffer(t *testing.T) {
+	ctx := context.Background()
+	vrw := types.NewMemoryValueStore()
+
+	from := keyedTestMap(t, vrw, 1, 1, 2, 2, 3, 3)
+	to := keyedTestMap(t, vrw, 1, 10, 3, 3, 4, 4)
+
+	removedOnly := func(newVal types.Value) (bool, error) {
+		return newVal == nil, nil
+	}
+
+	rd := NewRowDiffer(ctx, testKeyedSch, testKeyedSch, 8, WithValueFilter(removedOnly))
+	rd.Start(ctx, from, to)
+	diffs := drainDiffs(t, rd)
+
+	require.Len(t, diffs, 1)
+	assert.Equal(t, types.DiffChangeRemoved, diffs[0].ChangeType)
+}
@@ -0,0 +1,105 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"context"
+	"crypto/sha512"
+	"io"
+	"time"
+
+	"github.com/dolthub/dolt/go/store/diff"
+	"github.com/dolthub/dolt/go/store/hash"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// fingerprintBatchSize is the number of differences DiffFingerprint requests from a RowDiffer at a time
+const fingerprintBatchSize = 1024
+
+// DiffFingerprint reads every difference from |differ|, which must have been started, and returns a hash of them.
+// Each difference contributes the hash of its key, its change type and the hashes of its old and new values, in
+// the order they are produced, so two diffs have the same fingerprint only if they contain the same differences.
+// The caller is responsible for closing |differ|.
+func DiffFingerprint(ctx context.Context, differ RowDiffer) (hash.Hash, error) {
+	h := sha512.New()
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return hash.Hash{}, err
+		}
+
+		diffs, more, err := differ.GetDiffs(fingerprintBatchSize, time.Second)
+		if err != nil {
+			return hash.Hash{}, err
+		}
+
+		for _, d := range diffs {
+			if err = writeDifference(h, d); err != nil {
+				return hash.Hash{}, err
+			}
+		}
+
+		if !more {
+			break
+		}
+	}
+
+	return hash.New(h.Sum(nil)[:hash.ByteLen]), nil
+}
+
+// DifferenceHash returns a hash of the key, change type and the hashes of the old and new values of |d|. It depends
+// only on the content of |d|, so the same difference has the same hash however many times, and by whichever differ,
+// it is produced, and consumers that may see a difference more than once can use it to ignore repeats. Copies of a
+// keyless row are identical differences, and so have the same hash.
+func DifferenceHash(d *diff.Difference) (hash.Hash, error) {
+	h := sha512.New()
+	if err := writeDifference(h, d); err != nil {
+		return hash.Hash{}, err
+	}
+
+	return hash.New(h.Sum(nil)[:hash.ByteLen]), nil
+}
+
+// writeDifference writes the hash of the key of |d|, its change type and the hashes of its old and new values to
+// |w|. A nil value is written as the empty hash.
+func writeDifference(w io.Writer, d *diff.Difference) error {
+	writeValueHash := func(v types.Value) error {
+		var vh hash.Hash
+		if v != nil {
+			var err error
+			vh, err = v.Hash(types.Format_Default)
+			if err != nil {
+				return err
+			}
+		}
+
+		_, err := w.Write(vh[:])
+		return err
+	}
+
+	if err := writeValueHash(d.KeyValue); err != nil {
+		return err
+	}
+
+	if _, err := w.Write([]byte{byte(d.ChangeType)}); err != nil {
+		return err
+	}
+
+	if err := writeValueHash(d.OldValue); err != nil {
+		return err
+	}
+
+	return writeValueHash(d.NewValue)
+}
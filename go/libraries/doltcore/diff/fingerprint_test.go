@@ -0,0 +1,102 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/store/hash"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func TestDiffFingerprint(t *testing.T) {
+	ctx := context.Background()
+	vrw := types.NewMemoryValueStore()
+
+	fingerprint := func(from, to types.Map) hash.Hash {
+		rd := NewRowDiffer(ctx, testKeyedSch, testKeyedSch, 8)
+		rd.Start(ctx, from, to)
+		h, err := DiffFingerprint(ctx, rd)
+		require.NoError(t, err)
+		require.NoError(t, rd.Close())
+		return h
+	}
+
+	from := keyedTestMap(t, vrw, 1, 1, 2, 2, 3, 3)
+	to := keyedTestMap(t, vrw, 1, 1, 2, 20, 4, 4)
+
+	fp := fingerprint(from, to)
+	assert.False(t, fp.IsEmpty())
+
+	// the same differences between different maps
+	from2 := keyedTestMap(t, vrw, 1, 10, 2, 2, 3, 3)
+	to2 := keyedTestMap(t, vrw, 1, 10, 2, 20, 4, 4)
+	assert.Equal(t, fp, fingerprint(from2, to2))
+
+	// a single changed cell
+	to3 := keyedTestMap(t, vrw, 1, 1, 2, 21, 4, 4)
+	assert.NotEqual(t, fp, fingerprint(from, to3))
+
+	// no differences
+	assert.NotEqual(t, fp, fingerprint(from, from))
+	assert.Equal(t, fingerprint(to, to), fingerprint(from, from))
+}
+
+func TestDifferenceHash(t *testing.T) {
+	ctx := context.Background()
+	vrw := types.NewMemoryValueStore()
+
+	hashes := func(from, to types.Map) []hash.Hash {
+		rd := NewRowDiffer(ctx, testKeyedSch, testKeyedSch, 8)
+		rd.Start(ctx, from, to)
+
+		var hs []hash.Hash
+		for _, d := range drainDiffs(t, rd) {
+			h, err := DifferenceHash(d)
+			require.NoError(t, err)
+			hs = append(hs, h)
+		}
+		return hs
+	}
+
+	// a modification, a removal and an addition
+	from := keyedTestMap(t, vrw, 1, 1, 2, 2, 3, 3)
+	to := keyedTestMap(t, vrw, 1, 1, 2, 20, 4, 4)
+
+	hs := hashes(from, to)
+	require.Len(t, hs, 3)
+	assert.NotEqual(t, hs[0], hs[1])
+	assert.NotEqual(t, hs[0], hs[2])
+	assert.NotEqual(t, hs[1], hs[2])
+
+	// replaying the same diff produces the same hashes
+	assert.Equal(t, hs, hashes(from, to))
+
+	// the same differences between different maps
+	from2 := keyedTestMap(t, vrw, 1, 10, 2, 2, 3, 3)
+	to2 := keyedTestMap(t, vrw, 1, 10, 2, 20, 4, 4)
+	assert.Equal(t, hs, hashes(from2, to2))
+
+	// the same keys with a different new value
+	to3 := keyedTestMap(t, vrw, 1, 1, 2, 21, 4, 4)
+	hs3 := hashes(from, to3)
+	require.Len(t, hs3, 3)
+	assert.NotEqual(t, hs[0], hs3[0])
+	assert.Equal(t, hs[1:], hs3[1:])
+}
@@ -0,0 +1,101 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/dolthub/dolt/go/store/diff"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// forEachPollTimeout is how long ForEach waits on each call to GetDiffs
+const forEachPollTimeout = 100 * time.Millisecond
+
+// forEachBatchSize is the number of differences ForEach requests on each call to GetDiffs
+const forEachBatchSize = 64
+
+// ErrInvalidCheckpointInterval is returned by ForEachWithCheckpoint when the checkpoint interval is not positive.
+var ErrInvalidCheckpointInterval = errors.New("checkpoint interval must be positive")
+
+// DiffCallback is called with each difference visited by ForEach.
+type DiffCallback func(d *diff.Difference) error
+
+// CheckpointCallback is called by ForEachWithCheckpoint with the key of the last difference processed.
+type CheckpointCallback func(lastKey types.Value) error
+
+// ForEach calls |cb| with every difference from |rd|, which must already be started, stopping at the first error.
+// |rd| is not closed.
+func ForEach(ctx context.Context, rd RowDiffer, cb DiffCallback) error {
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		diffs, more, err := rd.GetDiffs(forEachBatchSize, forEachPollTimeout)
+		if err != nil {
+			return err
+		}
+
+		for _, d := range diffs {
+			if err := cb(d); err != nil {
+				return err
+			}
+		}
+
+		if !more {
+			return nil
+		}
+	}
+}
+
+// ForEachWithCheckpoint is ForEach, but also calls |checkpoint| after every |n| differences with the key of the
+// last difference passed to |cb|, so that progress can be recorded. |checkpoint| is called a final time once the
+// diff completes, unless it was just called for the last difference. For an empty diff it is called once with a
+// nil key.
+func ForEachWithCheckpoint(ctx context.Context, rd RowDiffer, n int, cb DiffCallback, checkpoint CheckpointCallback) error {
+	if n <= 0 {
+		return ErrInvalidCheckpointInterval
+	}
+
+	var lastKey types.Value
+	sinceCheckpoint := 0
+	err := ForEach(ctx, rd, func(d *diff.Difference) error {
+		if err := cb(d); err != nil {
+			return err
+		}
+
+		lastKey = d.KeyValue
+		sinceCheckpoint++
+		if sinceCheckpoint < n {
+			return nil
+		}
+
+		sinceCheckpoint = 0
+		return checkpoint(lastKey)
+	})
+
+	if err != nil {
+		return err
+	}
+
+	if sinceCheckpoint > 0 || lastKey == nil {
+		return checkpoint(lastKey)
+	}
+
+	return nil
+}
@@ -0,0 +1,99 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/store/diff"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func TestForEachWithCheckpoint(t *testing.T) {
+	ctx := context.Background()
+	vrw := types.NewMemoryValueStore()
+
+	empty := keyedTestMap(t, vrw)
+	var pkVals []int
+	for i := 0; i < 10; i++ {
+		pkVals = append(pkVals, i, i)
+	}
+	to := keyedTestMap(t, vrw, pkVals...)
+
+	tests := []struct {
+		name           string
+		to             types.Map
+		n              int
+		expVisited     int
+		expCheckpoints []types.Value
+	}{
+		{"uneven interval", to, 3, 10, []types.Value{types.Int(2), types.Int(5), types.Int(8), types.Int(9)}},
+		{"even interval", to, 5, 10, []types.Value{types.Int(4), types.Int(9)}},
+		{"interval larger than diff", to, 20, 10, []types.Value{types.Int(9)}},
+		{"empty diff", empty, 3, 0, []types.Value{nil}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rd := NewRowDiffer(ctx, testKeyedSch, testKeyedSch, 4)
+			rd.Start(ctx, empty, test.to)
+			defer rd.Close()
+
+			visited := 0
+			var checkpoints []types.Value
+			err := ForEachWithCheckpoint(ctx, rd, test.n, func(d *diff.Difference) error {
+				visited++
+				return nil
+			}, func(lastKey types.Value) error {
+				if lastKey == nil {
+					checkpoints = append(checkpoints, nil)
+				} else {
+					checkpoints = append(checkpoints, mustTupleGet(t, lastKey.(types.Tuple), 1))
+				}
+				return nil
+			})
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expVisited, visited)
+			assert.Equal(t, test.expCheckpoints, checkpoints)
+		})
+	}
+
+	t.Run("callback error", func(t *testing.T) {
+		rd := NewRowDiffer(ctx, testKeyedSch, testKeyedSch, 4)
+		rd.Start(ctx, empty, to)
+		defer rd.Close()
+
+		errStop := errors.New("stop")
+		checkpoints := 0
+		err := ForEachWithCheckpoint(ctx, rd, 2, func(d *diff.Difference) error {
+			return errStop
+		}, func(lastKey types.Value) error {
+			checkpoints++
+			return nil
+		})
+		assert.Equal(t, errStop, err)
+		assert.Equal(t, 0, checkpoints)
+	})
+
+	rd := NewRowDiffer(ctx, testKeyedSch, testKeyedSch, 4)
+	err := ForEachWithCheckpoint(ctx, rd, 0, nil, nil)
+	assert.Equal(t, ErrInvalidCheckpointInterval, err)
+}
@@ -0,0 +1,73 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// KeyFormatter renders a row key for display.
+type KeyFormatter func(key types.Value) (string, error)
+
+// DefaultKeyFormatter renders a key using its noms representation.
+func DefaultKeyFormatter(key types.Value) (string, error) {
+	return key.HumanReadableString(), nil
+}
+
+// ColumnKeyFormatter returns a KeyFormatter that renders key tuples as the names and values of |sch|'s columns,
+// e.g. (id: 1, name: "a"). Tags that aren't columns of |sch| are rendered by number.
+func ColumnKeyFormatter(sch schema.Schema) KeyFormatter {
+	allCols := sch.GetAllCols()
+	return func(key types.Value) (string, error) {
+		tup, ok := key.(types.Tuple)
+		if !ok {
+			return key.HumanReadableString(), nil
+		}
+
+		var fields []string
+		var tag uint64
+		err := tup.IterFields(func(i uint64, v types.Value) (stop bool, err error) {
+			if i%2 == 0 {
+				tag = uint64(v.(types.Uint))
+				return false, nil
+			}
+
+			name := fmt.Sprintf("%d", tag)
+			if col, ok := allCols.GetByTag(tag); ok {
+				name = col.Name
+			}
+
+			fields = append(fields, fmt.Sprintf("%s: %s", name, v.HumanReadableString()))
+			return false, nil
+		})
+
+		if err != nil {
+			return "", err
+		}
+
+		return "(" + strings.Join(fields, ", ") + ")", nil
+	}
+}
+
+// WithKeyFormatter makes a RowDiffer render keys with |kf| in the errors it returns.
+func WithKeyFormatter(kf KeyFormatter) RowDifferOption {
+	return func(o *rowDifferOptions) {
+		o.formatKey = kf
+	}
+}
@@ -0,0 +1,78 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func TestColumnKeyFormatter(t *testing.T) {
+	ctx := context.Background()
+	vrw := types.NewMemoryValueStore()
+
+	from := keyedTestMap(t, vrw, 1, 1, 2, 2)
+	to := keyedTestMap(t, vrw, 1, 1, 2, 3)
+
+	rd := NewRowDiffer(ctx, testKeyedSch, testKeyedSch, 8)
+	rd.Start(ctx, from, to)
+	diffs := drainDiffs(t, rd)
+
+	require.Len(t, diffs, 1)
+	key, err := ColumnKeyFormatter(testKeyedSch)(diffs[0].KeyValue)
+	require.NoError(t, err)
+	assert.Equal(t, "(pk: 2)", key)
+}
+
+func TestKeyFormatterInErrors(t *testing.T) {
+	ctx := context.Background()
+	vrw := types.NewMemoryValueStore()
+	nbf := vrw.Format()
+
+	formatted := 0
+	kf := func(key types.Value) (string, error) {
+		formatted++
+		return "custom key", nil
+	}
+
+	// a keyless row whose value changes without its cardinality changing can't be expanded
+	key, err := types.NewTuple(nbf, types.Uint(schema.KeylessRowIdTag), types.Int(1))
+	require.NoError(t, err)
+	rowMap := func(val int) types.Map {
+		v, err := types.NewTuple(nbf,
+			types.Uint(schema.KeylessRowCardinalityTag), types.Uint(1),
+			types.Uint(testValTag), types.Int(val))
+		require.NoError(t, err)
+		m, err := types.NewMap(ctx, vrw, key, v)
+		require.NoError(t, err)
+		return m
+	}
+
+	rd := NewRowDiffer(ctx, testKeylessSch, testKeylessSch, 8, WithKeyFormatter(kf))
+	rd.Start(ctx, rowMap(1), rowMap(2))
+
+	_, _, err = rd.GetDiffs(8, time.Second)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "custom key")
+	assert.Equal(t, 1, formatted)
+	_ = rd.Close()
+}
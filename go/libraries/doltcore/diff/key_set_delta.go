@@ -0,0 +1,120 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/row"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/store/diff"
+	"github.com/dolthub/dolt/go/store/hash"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// KeySetDelta is the change a diff makes to the set of keys in a row map.
+type KeySetDelta struct {
+	// Added are the keys that are in the to map but not the from map, in the order the diff reported them
+	Added []types.Value
+	// Removed are the keys that are in the from map but not the to map, in the order the diff reported them
+	Removed []types.Value
+}
+
+// ExpectedLen returns the number of keys in the to map, given the |from| map the delta was computed against.
+func (ksd KeySetDelta) ExpectedLen(from types.Map) uint64 {
+	return from.Len() + uint64(len(ksd.Added)) - uint64(len(ksd.Removed))
+}
+
+// NewKeySetDelta returns the keys added to and removed from |from| by |diffs|, which are every difference returned
+// by a RowDiffer for a table with schema |sch|, without building the to map. For keyless tables a key is only
+// removed once every copy of its row is, so |from| is read once for each key that |diffs| change.
+func NewKeySetDelta(ctx context.Context, sch schema.Schema, from types.Map, diffs []*diff.Difference) (KeySetDelta, error) {
+	if schema.IsKeyless(sch) {
+		return keylessKeySetDelta(ctx, from, diffs)
+	}
+
+	var ksd KeySetDelta
+	for _, d := range diffs {
+		switch d.ChangeType {
+		case types.DiffChangeAdded:
+			ksd.Added = append(ksd.Added, d.KeyValue)
+		case types.DiffChangeRemoved:
+			ksd.Removed = append(ksd.Removed, d.KeyValue)
+		case types.DiffChangeModified:
+		default:
+			return KeySetDelta{}, fmt.Errorf("unexpected DiffChange type %d", d.ChangeType)
+		}
+	}
+
+	return ksd, nil
+}
+
+// keylessKeySetDelta tallies the copies of each row that |diffs| add and remove, and compares the totals with the
+// rows' cardinalities in |from|.
+func keylessKeySetDelta(ctx context.Context, from types.Map, diffs []*diff.Difference) (KeySetDelta, error) {
+	var keys []types.Value
+	copies := make(map[hash.Hash]int64)
+	for _, d := range diffs {
+		h, err := d.KeyValue.Hash(from.Format())
+		if err != nil {
+			return KeySetDelta{}, err
+		}
+
+		if _, ok := copies[h]; !ok {
+			keys = append(keys, d.KeyValue)
+		}
+
+		switch d.ChangeType {
+		case types.DiffChangeAdded:
+			copies[h]++
+		case types.DiffChangeRemoved:
+			copies[h]--
+		default:
+			return KeySetDelta{}, fmt.Errorf("unexpected DiffChange type %d for keyless row", d.ChangeType)
+		}
+	}
+
+	var ksd KeySetDelta
+	for _, key := range keys {
+		h, err := key.Hash(from.Format())
+		if err != nil {
+			return KeySetDelta{}, err
+		}
+
+		val, ok, err := from.MaybeGet(ctx, key)
+		if err != nil {
+			return KeySetDelta{}, err
+		}
+
+		var fromCard int64
+		if ok {
+			c, err := val.(types.Tuple).Get(row.KeylessCardinalityValIdx)
+			if err != nil {
+				return KeySetDelta{}, err
+			}
+			fromCard = int64(c.(types.Uint))
+		}
+
+		toCard := fromCard + copies[h]
+		if fromCard == 0 && toCard > 0 {
+			ksd.Added = append(ksd.Added, key)
+		} else if fromCard > 0 && toCard <= 0 {
+			ksd.Removed = append(ksd.Removed, key)
+		}
+	}
+
+	return ksd, nil
+}
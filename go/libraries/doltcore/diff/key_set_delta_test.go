@@ -0,0 +1,82 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func TestNewKeySetDelta(t *testing.T) {
+	ctx := context.Background()
+	vrw := types.NewMemoryValueStore()
+
+	tests := []struct {
+		name       string
+		sch        schema.Schema
+		from, to   types.Map
+		expAdded   int
+		expRemoved int
+	}{
+		{
+			name:       "keyed",
+			sch:        testKeyedSch,
+			from:       keyedTestMap(t, vrw, 1, 1, 2, 2, 3, 3, 4, 4),
+			to:         keyedTestMap(t, vrw, 1, 1, 2, 5, 5, 5, 6, 6, 7, 7),
+			expAdded:   3,
+			expRemoved: 2,
+		},
+		{
+			// 1 loses a copy, 2 loses all of its copies, 3 gains a copy and 4 is new
+			name:       "keyless",
+			sch:        testKeylessSch,
+			from:       keylessTestMap(t, vrw, 1, 2, 2, 2, 3, 1),
+			to:         keylessTestMap(t, vrw, 1, 1, 3, 2, 4, 3),
+			expAdded:   1,
+			expRemoved: 1,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rd := NewRowDiffer(ctx, test.sch, test.sch, 8)
+			rd.Start(ctx, test.from, test.to)
+			diffs := drainDiffs(t, rd)
+
+			ksd, err := NewKeySetDelta(ctx, test.sch, test.from, diffs)
+			require.NoError(t, err)
+			assert.Len(t, ksd.Added, test.expAdded)
+			assert.Len(t, ksd.Removed, test.expRemoved)
+			assert.Equal(t, test.to.Len(), ksd.ExpectedLen(test.from))
+
+			for _, k := range ksd.Added {
+				has, err := test.to.Has(ctx, k)
+				require.NoError(t, err)
+				assert.True(t, has)
+			}
+			for _, k := range ksd.Removed {
+				has, err := test.to.Has(ctx, k)
+				require.NoError(t, err)
+				assert.False(t, has)
+			}
+		})
+	}
+}
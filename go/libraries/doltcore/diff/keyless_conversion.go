@@ -0,0 +1,175 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/dolthub/dolt/go/libraries/utils/async"
+	"github.com/dolthub/dolt/go/store/diff"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// conversionWindowPerWorker is the number of differences per conversion worker that may be dispatched but not yet
+// returned in order. It bounds the memory used to reorder converted differences.
+const conversionWindowPerWorker = 16
+
+// keylessConversion is a difference between keyless rows after conversion by convertDiff, along with the number of
+// copies of it to return.
+type keylessConversion struct {
+	seq  uint64
+	df   diff.Difference
+	card uint64
+}
+
+// WithConversionWorkers makes a RowDiffer for keyless tables convert differences into additions and removals with a
+// pool of |workers| goroutines rather than on the goroutine reading them, so that conversion runs in parallel with
+// consumption. Differences are still returned in key order. It has no effect for keyed tables, or if |workers| is not
+// positive.
+func WithConversionWorkers(workers int) RowDifferOption {
+	return func(o *rowDifferOptions) {
+		o.workers = workers
+	}
+}
+
+// Start implements RowDiffer.
+func (kd *keylessDiffer) Start(ctx context.Context, from, to types.Map) {
+	if kd.workers <= 0 {
+		kd.AsyncDiffer.Start(ctx, from, to)
+		return
+	}
+
+	kd.eg, kd.egCtx = errgroup.WithContext(ctx)
+	size, release, resErr := kd.bufferReservation(kd.egCtx)
+	kd.converted = make(chan keylessConversion, size)
+	kd.egCancel = async.GoWithCancel(kd.egCtx, kd.eg, func(ctx context.Context) error {
+		defer close(kd.converted)
+		if resErr != nil {
+			return resErr
+		}
+		defer release()
+		return convertInParallel(ctx, from, to, kd.diffFn, kd.converted, kd.workers, kd.formatKey)
+	})
+}
+
+// rawDiffs returns the channel of unconverted differences that |kd| converts itself, or nil if conversion workers
+// convert them instead.
+func (kd *keylessDiffer) rawDiffs() <-chan diff.Difference {
+	if kd.converted != nil {
+		return nil
+	}
+	return kd.diffChan
+}
+
+// convertInParallel runs |diffFn|, converts each difference it produces with a pool of |workers| goroutines, and
+// sends the conversions to |out| in the order |diffFn| produced them.
+func convertInParallel(ctx context.Context, from, to types.Map, diffFn mapDiffFunc, out chan<- keylessConversion, workers int, formatKey KeyFormatter) error {
+	eg, ctx := errgroup.WithContext(ctx)
+	raw := make(chan diff.Difference, cap(out))
+	jobs := make(chan keylessConversion, workers)
+	results := make(chan keylessConversion, workers)
+
+	// a token is taken for each difference dispatched and returned once it is sent to |out|
+	window := make(chan struct{}, workers*conversionWindowPerWorker)
+
+	eg.Go(func() (err error) {
+		defer close(raw)
+		defer func() {
+			if r := recover(); r != nil {
+				err = fmt.Errorf("panic in diff.Diff: %v", r)
+			}
+		}()
+		return diffFn(ctx, from, to, raw)
+	})
+
+	eg.Go(func() error {
+		defer close(jobs)
+		seq := uint64(0)
+		for d := range raw {
+			select {
+			case window <- struct{}{}:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+
+			select {
+			case jobs <- keylessConversion{seq: seq, df: d}:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+			seq++
+		}
+		return nil
+	})
+
+	wg := &sync.WaitGroup{}
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		eg.Go(func() error {
+			defer wg.Done()
+			for c := range jobs {
+				var err error
+				c.df, c.card, err = convertDiff(c.df, formatKey)
+				if err != nil {
+					return err
+				}
+
+				select {
+				case results <- c:
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			}
+			return nil
+		})
+	}
+
+	eg.Go(func() error {
+		wg.Wait()
+		close(results)
+		return nil
+	})
+
+	eg.Go(func() error {
+		pending := make(map[uint64]keylessConversion)
+		next := uint64(0)
+		for c := range results {
+			pending[c.seq] = c
+
+			for {
+				c, ok := pending[next]
+				if !ok {
+					break
+				}
+				delete(pending, next)
+				next++
+
+				select {
+				case out <- c:
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+				<-window
+			}
+		}
+		return nil
+	})
+
+	return eg.Wait()
+}
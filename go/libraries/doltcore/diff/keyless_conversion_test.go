@@ -0,0 +1,137 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/store/diff"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// keylessChurnMaps returns keyless maps of |n| rows where every row is added, removed or has its cardinality changed.
+func keylessChurnMaps(t testing.TB, vrw types.ValueReadWriter, n int) (types.Map, types.Map) {
+	var fromVals, toVals []int
+	for i := 0; i < n; i++ {
+		switch i % 4 {
+		case 0:
+			fromVals = append(fromVals, i, 1)
+		case 1:
+			toVals = append(toVals, i, 2)
+		case 2:
+			fromVals = append(fromVals, i, 1)
+			toVals = append(toVals, i, 3)
+		case 3:
+			fromVals = append(fromVals, i, 4)
+			toVals = append(toVals, i, 1)
+		}
+	}
+
+	return keylessTestMap(t, vrw, fromVals...), keylessTestMap(t, vrw, toVals...)
+}
+
+func TestRowDifferWithConversionWorkers(t *testing.T) {
+	ctx := context.Background()
+	vrw := types.NewMemoryValueStore()
+	from, to := keylessChurnMaps(t, vrw, 2000)
+
+	serial := NewRowDiffer(ctx, testKeylessSch, testKeylessSch, 16)
+	serial.Start(ctx, from, to)
+	expected := drainDiffs(t, serial)
+	require.Len(t, expected, 500*1+500*2+500*2+500*3)
+
+	for _, workers := range []int{1, 2, 8} {
+		rd := NewRowDiffer(ctx, testKeylessSch, testKeylessSch, 16, WithConversionWorkers(workers))
+		rd.Start(ctx, from, to)
+		assertDiffsEqual(t, expected, drainDiffs(t, rd))
+
+		rd = NewRowDiffer(ctx, testKeylessSch, testKeylessSch, 16, WithConversionWorkers(workers))
+		rd.Start(ctx, from, to)
+		var filled []*diff.Difference
+		buf := make([]*diff.Difference, 7)
+		for {
+			n, more, err := rd.(BufferFillingRowDiffer).FillDiffs(buf, time.Second)
+			require.NoError(t, err)
+			for _, d := range buf[:n] {
+				d := *d
+				filled = append(filled, &d)
+			}
+			if !more {
+				break
+			}
+		}
+		require.NoError(t, rd.Close())
+		assertDiffsEqual(t, expected, filled)
+	}
+
+	// closing before the diff is consumed stops the workers
+	rd := NewRowDiffer(ctx, testKeylessSch, testKeylessSch, 1, WithConversionWorkers(4))
+	rd.Start(ctx, from, to)
+	_, _, err := rd.GetDiffs(1, time.Second)
+	require.NoError(t, err)
+	assert.NoError(t, rd.Close())
+}
+
+func assertDiffsEqual(t *testing.T, expected, actual []*diff.Difference) {
+	require.Equal(t, len(expected), len(actual))
+	for i := range expected {
+		assert.Equal(t, expected[i].ChangeType, actual[i].ChangeType)
+		assertValuesEqual(t, expected[i].KeyValue, actual[i].KeyValue)
+		assertValuesEqual(t, expected[i].OldValue, actual[i].OldValue)
+		assertValuesEqual(t, expected[i].NewValue, actual[i].NewValue)
+	}
+}
+
+func BenchmarkKeylessConversion(b *testing.B) {
+	ctx := context.Background()
+	vrw := types.NewMemoryValueStore()
+	from, to := keylessChurnMaps(b, vrw, 50000)
+
+	benchmarks := []struct {
+		name      string
+		newDiffer func() RowDiffer
+	}{
+		{"serial", func() RowDiffer { return NewRowDiffer(ctx, testKeylessSch, testKeylessSch, 1024) }},
+		{"workers=4", func() RowDiffer {
+			return NewRowDiffer(ctx, testKeylessSch, testKeylessSch, 1024, WithConversionWorkers(4))
+		}},
+	}
+
+	for _, bm := range benchmarks {
+		b.Run(bm.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				rd := bm.newDiffer()
+				rd.Start(ctx, from, to)
+				for {
+					_, more, err := rd.GetDiffs(1024, time.Second)
+					if err != nil {
+						b.Fatal(err)
+					}
+					if !more {
+						break
+					}
+				}
+				if err := rd.Close(); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+// KeylessDiffCounts is implemented by the RowDiffers returned for keyless tables. A keyless row that is added or
+// removed several times is a single change to the row's cardinality, but is returned by GetDiffs as one difference
+// per copy, so the two counts can differ. The counts cover the differences read so far, and are complete once the
+// RowDiffer has been drained.
+type KeylessDiffCounts interface {
+	// DistinctChanges returns the number of distinct rows whose cardinality changed.
+	DistinctChanges() uint64
+	// TotalCardinalityDelta returns the number of copies of rows added or removed, which is the number of
+	// differences GetDiffs returns for them.
+	TotalCardinalityDelta() uint64
+}
+
+var _ KeylessDiffCounts = &keylessDiffer{}
+
+// setCopies sets the number of copies of kd.df left to return to |card|, and counts the change.
+func (kd *keylessDiffer) setCopies(card uint64) {
+	kd.copiesLeft = card
+	if card > 0 {
+		kd.distinctChanges++
+		kd.cardinalityDelta += card
+	}
+}
+
+// DistinctChanges implements KeylessDiffCounts.
+func (kd *keylessDiffer) DistinctChanges() uint64 {
+	return kd.distinctChanges
+}
+
+// TotalCardinalityDelta implements KeylessDiffCounts.
+func (kd *keylessDiffer) TotalCardinalityDelta() uint64 {
+	return kd.cardinalityDelta
+}
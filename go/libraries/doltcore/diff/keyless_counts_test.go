@@ -0,0 +1,90 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/store/diff"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func TestKeylessDiffCounts(t *testing.T) {
+	ctx := context.Background()
+	vrw := types.NewMemoryValueStore()
+
+	// 2 gains two copies, 3 loses four, 4 is added with three and 5 is removed with one
+	from := keylessTestMap(t, vrw, 1, 1, 2, 2, 3, 5, 5, 1)
+	to := keylessTestMap(t, vrw, 1, 1, 2, 4, 3, 1, 4, 3)
+
+	const expDistinct = 4
+	const expDelta = 2 + 4 + 3 + 1
+
+	differs := []struct {
+		name string
+		rd   func() RowDiffer
+	}{
+		{"serial", func() RowDiffer { return NewRowDiffer(ctx, testKeylessSch, testKeylessSch, 2) }},
+		{"workers", func() RowDiffer {
+			return NewRowDiffer(ctx, testKeylessSch, testKeylessSch, 2, WithConversionWorkers(3))
+		}},
+	}
+
+	for _, test := range differs {
+		t.Run(test.name, func(t *testing.T) {
+			rd := test.rd()
+			counts, ok := rd.(KeylessDiffCounts)
+			require.True(t, ok)
+
+			rd.Start(ctx, from, to)
+			diffs := drainDiffs(t, rd)
+			assert.Len(t, diffs, expDelta)
+			assert.Equal(t, uint64(expDistinct), counts.DistinctChanges())
+			assert.Equal(t, uint64(expDelta), counts.TotalCardinalityDelta())
+		})
+
+		t.Run(test.name+" fill diffs", func(t *testing.T) {
+			rd := test.rd()
+			rd.Start(ctx, from, to)
+
+			buf := make([]*diff.Difference, 3)
+			total := 0
+			for {
+				n, more, err := rd.(BufferFillingRowDiffer).FillDiffs(buf, time.Second)
+				require.NoError(t, err)
+				total += n
+				if !more {
+					break
+				}
+			}
+			require.NoError(t, rd.Close())
+
+			counts := rd.(KeylessDiffCounts)
+			assert.Equal(t, expDelta, total)
+			assert.Equal(t, uint64(expDistinct), counts.DistinctChanges())
+			assert.Equal(t, uint64(expDelta), counts.TotalCardinalityDelta())
+		})
+	}
+
+	t.Run("keyed differs don't count", func(t *testing.T) {
+		_, ok := NewRowDiffer(ctx, testKeyedSch, testKeyedSch, 2).(KeylessDiffCounts)
+		assert.False(t, ok)
+	})
+}
@@ -0,0 +1,142 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"context"
+
+	"github.com/dolthub/dolt/go/store/diff"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// LazyDifference is a difference read from a diff blob whose old and new values are decoded from the retained
+// encoding of the difference only when they're first accessed. Consumers that only need a difference's key and
+// change type never pay to decode its values. A LazyDifference is not safe for concurrent use.
+type LazyDifference struct {
+	// Path to the value that has changed
+	Path types.Path
+	// ChangeType indicates the type of diff: modified, added, deleted
+	ChangeType types.DiffChangeType
+	// KeyValue holds the key associated with the changed value
+	KeyValue types.Value
+
+	record types.Tuple
+
+	oldVal, newVal         types.Value
+	oldDecoded, newDecoded bool
+}
+
+// OldValue returns the value before the change, or nil if the value was added.
+func (ld *LazyDifference) OldValue() (types.Value, error) {
+	return ld.decode(diffBlobOldIdx, &ld.oldVal, &ld.oldDecoded)
+}
+
+// NewValue returns the value after the change, or nil if the value was removed.
+func (ld *LazyDifference) NewValue() (types.Value, error) {
+	return ld.decode(diffBlobNewIdx, &ld.newVal, &ld.newDecoded)
+}
+
+func (ld *LazyDifference) decode(idx uint64, val *types.Value, decoded *bool) (types.Value, error) {
+	if !*decoded {
+		v, err := ld.record.Get(idx)
+		if err != nil {
+			return nil, err
+		}
+
+		*val, *decoded = nullToNil(v), true
+	}
+
+	return *val, nil
+}
+
+// Difference decodes every field of the difference.
+func (ld *LazyDifference) Difference() (*diff.Difference, error) {
+	oldVal, err := ld.OldValue()
+	if err != nil {
+		return nil, err
+	}
+
+	newVal, err := ld.NewValue()
+	if err != nil {
+		return nil, err
+	}
+
+	newKey, err := ld.record.Get(diffBlobNewKeyIdx)
+	if err != nil {
+		return nil, err
+	}
+
+	rootKey, err := ld.record.Get(diffBlobRootKeyIdx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &diff.Difference{
+		Path:         ld.Path,
+		ChangeType:   ld.ChangeType,
+		KeyValue:     ld.KeyValue,
+		OldValue:     oldVal,
+		NewValue:     newVal,
+		NewKeyValue:  nullToNil(newKey),
+		RootKeyValue: nullToNil(rootKey),
+	}, nil
+}
+
+// LazyBlobDiffIterator reads the differences stored in a Blob by DiffToBlob as LazyDifferences.
+type LazyBlobDiffIterator struct {
+	itr *BlobDiffIterator
+}
+
+// BlobToLazyDiffIterator returns a LazyBlobDiffIterator over the differences stored in |blob| by DiffToBlob. Values
+// are read using |vrw|.
+func BlobToLazyDiffIterator(ctx context.Context, blob types.Blob, vrw types.ValueReadWriter) *LazyBlobDiffIterator {
+	return &LazyBlobDiffIterator{BlobToDiffIterator(ctx, blob, vrw)}
+}
+
+// Next returns the next difference, in the order they were written, or io.EOF once every difference has been read.
+// Only the difference's path, change type and key are decoded.
+func (itr *LazyBlobDiffIterator) Next() (*LazyDifference, error) {
+	tup, err := itr.itr.nextRecord()
+	if err != nil {
+		return nil, err
+	}
+
+	pathField, err := tup.Get(diffBlobPathIdx)
+	if err != nil {
+		return nil, err
+	}
+
+	path, err := parseBlobDiffPath(pathField)
+	if err != nil {
+		return nil, err
+	}
+
+	changeType, err := tup.Get(diffBlobChangeTypeIdx)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := tup.Get(diffBlobKeyIdx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &LazyDifference{
+		Path:       path,
+		ChangeType: types.DiffChangeType(changeType.(types.Uint)),
+		KeyValue:   nullToNil(key),
+		record:     tup,
+	}, nil
+}
@@ -0,0 +1,89 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/store/diff"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func TestLazyBlobDiffIterator(t *testing.T) {
+	ctx := context.Background()
+	vrw := types.NewMemoryValueStore()
+
+	from := keyedTestMap(t, vrw, 1, 1, 2, 2, 3, 3)
+	to := keyedTestMap(t, vrw, 1, 10, 3, 3, 4, 4)
+
+	rd := NewRowDiffer(ctx, testKeyedSch, testKeyedSch, 8)
+	rd.Start(ctx, from, to)
+	blob, err := DiffToBlob(ctx, rd, vrw)
+	require.NoError(t, err)
+	require.NoError(t, rd.Close())
+
+	eager := BlobToDiffIterator(ctx, blob, vrw)
+	lazy := BlobToLazyDiffIterator(ctx, blob, vrw)
+
+	var expected, decoded []*diff.Difference
+	for {
+		exp, err := eager.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		expected = append(expected, exp)
+
+		ld, err := lazy.Next()
+		require.NoError(t, err)
+		assert.Equal(t, exp.ChangeType, ld.ChangeType)
+		assert.Equal(t, exp.Path.String(), ld.Path.String())
+		assertValuesEqual(t, exp.KeyValue, ld.KeyValue)
+
+		// values aren't decoded until they're accessed
+		assert.False(t, ld.oldDecoded)
+		assert.False(t, ld.newDecoded)
+
+		newVal, err := ld.NewValue()
+		require.NoError(t, err)
+		assertValuesEqual(t, exp.NewValue, newVal)
+		assert.True(t, ld.newDecoded)
+		assert.False(t, ld.oldDecoded)
+
+		oldVal, err := ld.OldValue()
+		require.NoError(t, err)
+		assertValuesEqual(t, exp.OldValue, oldVal)
+		assert.True(t, ld.oldDecoded)
+
+		d, err := ld.Difference()
+		require.NoError(t, err)
+		decoded = append(decoded, d)
+	}
+
+	require.Len(t, expected, 3)
+	assertDiffsEqual(t, expected, decoded)
+	for i := range expected {
+		assertValuesEqual(t, expected[i].RootKeyValue, decoded[i].RootKeyValue)
+		assertValuesEqual(t, expected[i].NewKeyValue, decoded[i].NewKeyValue)
+	}
+
+	_, err = lazy.Next()
+	assert.Equal(t, io.EOF, err)
+}
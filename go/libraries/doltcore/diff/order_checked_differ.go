@@ -0,0 +1,60 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/dolthub/dolt/go/store/diff"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// ErrMapKeysNotOrdered is returned by a RowDiffer created with WithKeyOrderCheck when the keys of either map being
+// diffed are not in order, which can only happen if the map is corrupt. Diffing such a map would produce misleading
+// differences.
+var ErrMapKeysNotOrdered = errors.New("map keys are not in order")
+
+// WithKeyOrderCheck makes a RowDiffer check that the keys of both maps are in order, using types.Map.IsInOrder, before
+// diffing them, and fails with ErrMapKeysNotOrdered if they are not. The check reads every key of both maps before the
+// first difference is produced, so it is meant for validating maps that are suspected of being corrupt rather than for
+// routine diffs.
+func WithKeyOrderCheck() RowDifferOption {
+	return func(o *rowDifferOptions) {
+		o.wrapUnbuffered(checkKeyOrder)
+	}
+}
+
+// checkKeyOrder returns a mapDiffFunc that checks that the keys of both maps are in order before running |diffFn|.
+func checkKeyOrder(diffFn mapDiffFunc) mapDiffFunc {
+	return func(ctx context.Context, from, to types.Map, out chan<- diff.Difference) error {
+		for _, side := range []struct {
+			name string
+			m    types.Map
+		}{{"from", from}, {"to", to}} {
+			inOrder, err := side.m.IsInOrder(ctx)
+			if err != nil {
+				return err
+			}
+
+			if !inOrder {
+				return fmt.Errorf("%w in %s map", ErrMapKeysNotOrdered, side.name)
+			}
+		}
+
+		return diffFn(ctx, from, to, out)
+	}
+}
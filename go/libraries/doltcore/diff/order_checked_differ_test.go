@@ -0,0 +1,79 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/store/chunks"
+	"github.com/dolthub/dolt/go/store/diff"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// unorderedTestMap returns a corrupt map for |testKeyedSch| holding the pk 2 before the pk 1. Maps can't be built
+// out of order, so it is made by swapping the entries of an encoded map.
+func unorderedTestMap(t *testing.T, vrw types.ValueReadWriter) types.Map {
+	c, err := types.EncodeValue(keyedTestMap(t, vrw, 1, 1, 2, 2), vrw.Format())
+	require.NoError(t, err)
+
+	// a map leaf is encoded as its kind, level and count followed by its entries, which here are the same size
+	data := c.Data()
+	entries := data[3:]
+	require.True(t, len(entries)%2 == 0)
+	half := len(entries) / 2
+
+	swapped := append(append(append([]byte{}, data[:3]...), entries[half:]...), entries[:half]...)
+	v, err := types.DecodeValue(chunks.NewChunk(swapped), vrw)
+	require.NoError(t, err)
+
+	m := v.(types.Map)
+	inOrder, err := m.IsInOrder(context.Background())
+	require.NoError(t, err)
+	require.False(t, inOrder)
+	return m
+}
+
+func TestOrderCheckedRowDiffer(t *testing.T) {
+	ctx := context.Background()
+	vrw := types.NewMemoryValueStore()
+
+	from := keyedTestMap(t, vrw, 1, 1, 2, 2)
+	to := keyedTestMap(t, vrw, 1, 1, 2, 20)
+
+	rd := NewRowDiffer(ctx, testKeyedSch, testKeyedSch, 8, WithKeyOrderCheck())
+	rd.Start(ctx, from, to)
+	assert.Len(t, drainDiffs(t, rd), 1)
+
+	unordered := unorderedTestMap(t, vrw)
+	for _, maps := range [][2]types.Map{{unordered, to}, {from, unordered}} {
+		rd = NewRowDiffer(ctx, testKeyedSch, testKeyedSch, 8, WithKeyOrderCheck())
+		rd.Start(ctx, maps[0], maps[1])
+
+		var err error
+		for more := true; more && err == nil; {
+			var diffs []*diff.Difference
+			diffs, more, err = rd.GetDiffs(8, time.Second)
+			assert.Empty(t, diffs)
+		}
+		assert.True(t, errors.Is(err, ErrMapKeysNotOrdered), "%v", err)
+		assert.True(t, errors.Is(rd.Close(), ErrMapKeysNotOrdered))
+	}
+}
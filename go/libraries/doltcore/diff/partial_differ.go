@@ -0,0 +1,76 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"context"
+	"errors"
+
+	"github.com/dolthub/dolt/go/store/diff"
+)
+
+// ErrDiffIncomplete is matched by the error returned by a RowDiffer created with WithPartialResults when its diff is
+// cancelled before it completes. The error also wraps the cancellation cause, such as context.Canceled.
+var ErrDiffIncomplete = errors.New("diff incomplete")
+
+// WithPartialResults makes a RowDiffer keep the differences it has collected when its diff is cancelled. Rather than
+// returning nil, GetDiffs returns the differences read in that call, along with any already buffered, and an error
+// matching ErrDiffIncomplete, so that callers can show the partial diff with a notice that it was cancelled.
+func WithPartialResults() RowDifferOption {
+	return func(o *rowDifferOptions) {
+		o.keepPartial = true
+	}
+}
+
+// incompleteDiffError is returned by a partial RowDiffer when its diff was cancelled with |cause|.
+type incompleteDiffError struct {
+	cause error
+}
+
+func (e incompleteDiffError) Error() string {
+	return ErrDiffIncomplete.Error() + ": " + e.cause.Error()
+}
+
+func (e incompleteDiffError) Is(target error) bool {
+	return target == ErrDiffIncomplete
+}
+
+func (e incompleteDiffError) Unwrap() error {
+	return e.cause
+}
+
+// incompleteErr returns the error to return from GetDiffs when the diff ended with |err|. Cancellations of a
+// partial RowDiffer are reported as incomplete diffs, and all other errors are returned as is.
+func (ad *AsyncDiffer) incompleteErr(err error) error {
+	if ad.keepPartial && (errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)) {
+		return incompleteDiffError{cause: err}
+	}
+
+	return err
+}
+
+// drainPartial appends differences that were buffered before the diff was cancelled to |diffs|, up to a total of
+// |numDiffs| unless |numDiffs| is zero. The map diff must have finished, so that the buffer is closed.
+func (ad *AsyncDiffer) drainPartial(diffs []*diff.Difference, numDiffs int) []*diff.Difference {
+	for numDiffs == 0 || len(diffs) < numDiffs {
+		d, ok := <-ad.diffChan
+		if !ok {
+			break
+		}
+		diffs = append(diffs, &d)
+	}
+
+	return diffs
+}
@@ -0,0 +1,120 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func TestPartialRowDiffer(t *testing.T) {
+	ctx := context.Background()
+	vrw := types.NewMemoryValueStore()
+
+	const numRows = 1000
+	const buf = 8
+
+	keyed := make([]int, 0, 2*numRows)
+	keyless := make([]int, 0, 2*numRows)
+	for i := 0; i < numRows; i++ {
+		keyed = append(keyed, i, i)
+		keyless = append(keyless, i, 1)
+	}
+
+	t.Run("keyed", func(t *testing.T) {
+		rd := NewRowDiffer(ctx, testKeyedSch, testKeyedSch, buf, WithPartialResults())
+		diffCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		rd.Start(diffCtx, keyedTestMap(t, vrw), keyedTestMap(t, vrw, keyed...))
+
+		diffs, more, err := rd.GetDiffs(4, time.Second)
+		require.NoError(t, err)
+		require.True(t, more)
+		require.Len(t, diffs, 4)
+
+		// wait for the buffer to refill, then cancel mid-diff
+		ad := rd.(*AsyncDiffer)
+		require.Eventually(t, func() bool { return len(ad.diffChan) == buf }, time.Second, time.Millisecond)
+		cancel()
+
+		diffs, more, err = rd.GetDiffs(numRows, time.Second)
+		assert.True(t, errors.Is(err, ErrDiffIncomplete))
+		assert.True(t, errors.Is(err, context.Canceled))
+		assert.False(t, more)
+		assert.GreaterOrEqual(t, len(diffs), buf)
+		assert.Less(t, len(diffs), numRows-4)
+		for _, d := range diffs {
+			assert.NotNil(t, d.KeyValue)
+		}
+
+		assert.Equal(t, context.Canceled, rd.Close())
+	})
+
+	t.Run("keyless", func(t *testing.T) {
+		rd := NewRowDiffer(ctx, testKeylessSch, testKeylessSch, buf, WithPartialResults())
+		diffCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		rd.Start(diffCtx, keylessTestMap(t, vrw), keylessTestMap(t, vrw, keyless...))
+
+		diffs, more, err := rd.GetDiffs(4, time.Second)
+		require.NoError(t, err)
+		require.True(t, more)
+		require.Len(t, diffs, 4)
+
+		cancel()
+
+		diffs, more, err = rd.GetDiffs(numRows, time.Second)
+		assert.True(t, errors.Is(err, ErrDiffIncomplete))
+		assert.False(t, more)
+		assert.NotNil(t, diffs)
+		assert.Less(t, len(diffs), numRows-4)
+	})
+
+	t.Run("completed diffs are not incomplete", func(t *testing.T) {
+		rd := NewRowDiffer(ctx, testKeyedSch, testKeyedSch, buf, WithPartialResults())
+		rd.Start(ctx, keyedTestMap(t, vrw), keyedTestMap(t, vrw, 1, 1, 2, 2))
+		assert.Len(t, drainDiffs(t, rd), 2)
+	})
+}
+
+func TestRowDifferCancelDiscardsDiffs(t *testing.T) {
+	vrw := types.NewMemoryValueStore()
+
+	rows := make([]int, 0, 200)
+	for i := 0; i < 100; i++ {
+		rows = append(rows, i, i)
+	}
+
+	rd := NewRowDiffer(context.Background(), testKeyedSch, testKeyedSch, 8)
+	ctx, cancel := context.WithCancel(context.Background())
+	rd.Start(ctx, keyedTestMap(t, vrw), keyedTestMap(t, vrw, rows...))
+	cancel()
+
+	// without the partial mode, cancellation is an error and isn't reported as an incomplete diff
+	var err error
+	for err == nil {
+		_, _, err = rd.GetDiffs(16, time.Second)
+	}
+	assert.False(t, errors.Is(err, ErrDiffIncomplete))
+	assert.True(t, errors.Is(err, context.Canceled))
+	_ = rd.Close()
+}
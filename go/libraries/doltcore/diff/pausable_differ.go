@@ -0,0 +1,115 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"context"
+	"sync"
+
+	"github.com/dolthub/dolt/go/store/diff"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// PauseControl pauses and resumes the diffs of the RowDiffers created with WithPauseControl.
+type PauseControl struct {
+	gate *pauseGate
+}
+
+// NewPauseControl returns a PauseControl that is not paused.
+func NewPauseControl() *PauseControl {
+	return &PauseControl{gate: newPauseGate()}
+}
+
+// Pause stops the RowDiffers' map diffs before they produce their next difference, so that they stop reading the
+// maps until Resume is called. Differences that are already buffered can still be read with GetDiffs.
+func (pc *PauseControl) Pause() {
+	pc.gate.pause()
+}
+
+// Resume continues paused diffs.
+func (pc *PauseControl) Resume() {
+	pc.gate.resume()
+}
+
+// WithPauseControl makes a RowDiffer's diff pause and resume with |pc|.
+func WithPauseControl(pc *PauseControl) RowDifferOption {
+	return func(o *rowDifferOptions) {
+		o.pauses = append(o.pauses, pc.gate)
+	}
+}
+
+// pauseGate blocks callers of wait while paused.
+type pauseGate struct {
+	mu sync.Mutex
+	// resumed is closed while the gate is open
+	resumed chan struct{}
+}
+
+func newPauseGate() *pauseGate {
+	resumed := make(chan struct{})
+	close(resumed)
+	return &pauseGate{resumed: resumed}
+}
+
+func (pg *pauseGate) pause() {
+	pg.mu.Lock()
+	defer pg.mu.Unlock()
+
+	select {
+	case <-pg.resumed:
+		pg.resumed = make(chan struct{})
+	default:
+		// already paused
+	}
+}
+
+func (pg *pauseGate) resume() {
+	pg.mu.Lock()
+	defer pg.mu.Unlock()
+
+	select {
+	case <-pg.resumed:
+		// not paused
+	default:
+		close(pg.resumed)
+	}
+}
+
+// wait blocks until the gate is open or |ctx| is done.
+func (pg *pauseGate) wait(ctx context.Context) error {
+	pg.mu.Lock()
+	resumed := pg.resumed
+	pg.mu.Unlock()
+
+	select {
+	case <-resumed:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// wrap returns a mapDiffFunc that waits for the gate to open before forwarding each difference from |diffFn|.
+// Nothing is buffered in between, so |diffFn| blocks on the difference it produces next while the gate is closed.
+func (pg *pauseGate) wrap(diffFn mapDiffFunc) mapDiffFunc {
+	return func(ctx context.Context, from, to types.Map, out chan<- diff.Difference) error {
+		return pipeDiffsUnbuffered(ctx, from, to, out, diffFn, func(d diff.Difference, send func(diff.Difference) error) error {
+			if err := pg.wait(ctx); err != nil {
+				return err
+			}
+			return send(d)
+		})
+	}
+}
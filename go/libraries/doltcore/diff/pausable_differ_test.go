@@ -0,0 +1,123 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/store/chunks"
+	"github.com/dolthub/dolt/go/store/diff"
+	"github.com/dolthub/dolt/go/store/hash"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// countingChunkStore is a ChunkStore that counts its reads.
+type countingChunkStore struct {
+	chunks.ChunkStore
+	reads int64
+}
+
+func (cs *countingChunkStore) Get(ctx context.Context, h hash.Hash) (chunks.Chunk, error) {
+	atomic.AddInt64(&cs.reads, 1)
+	return cs.ChunkStore.Get(ctx, h)
+}
+
+func (cs *countingChunkStore) GetMany(ctx context.Context, hashes hash.HashSet, found func(*chunks.Chunk)) error {
+	atomic.AddInt64(&cs.reads, 1)
+	return cs.ChunkStore.GetMany(ctx, hashes, found)
+}
+
+func TestPausableRowDiffer(t *testing.T) {
+	ctx := context.Background()
+
+	// enough rows that the maps span many chunks, which are read through a countingChunkStore
+	const numRows = 5000
+	var fromVals, toVals []int
+	for i := 0; i < numRows; i++ {
+		fromVals = append(fromVals, i, i)
+		toVals = append(toVals, i, i+1)
+	}
+
+	storage := &chunks.MemoryStorage{}
+	vrw := types.NewValueStore(storage.NewView())
+	tup, err := types.NewTuple(vrw.Format(), keyedTestMap(t, vrw, fromVals...), keyedTestMap(t, vrw, toVals...))
+	require.NoError(t, err)
+	ref, err := vrw.WriteValue(ctx, tup)
+	require.NoError(t, err)
+	_, err = vrw.Commit(ctx, ref.TargetHash(), hash.Hash{})
+	require.NoError(t, err)
+
+	cs := &countingChunkStore{ChunkStore: storage.NewView()}
+	val, err := types.NewValueStore(cs).ReadValue(ctx, ref.TargetHash())
+	require.NoError(t, err)
+	from, err := val.(types.Tuple).Get(0)
+	require.NoError(t, err)
+	to, err := val.(types.Tuple).Get(1)
+	require.NoError(t, err)
+
+	pc := NewPauseControl()
+	rd := NewRowDiffer(ctx, testKeyedSch, testKeyedSch, 4, WithPauseControl(pc))
+	rd.Start(ctx, from.(types.Map), to.(types.Map))
+
+	var all []*diff.Difference
+	getDiffs := func(timeout time.Duration) []*diff.Difference {
+		diffs, _, err := rd.GetDiffs(numRows, timeout)
+		require.NoError(t, err)
+		all = append(all, diffs...)
+		return diffs
+	}
+
+	diffs, _, err := rd.GetDiffs(5, time.Second)
+	require.NoError(t, err)
+	require.Len(t, diffs, 5)
+	all = append(all, diffs...)
+
+	pc.Pause()
+
+	// differences buffered before the pause are still readable
+	for len(getDiffs(50*time.Millisecond)) > 0 {
+	}
+	paused := len(all)
+	assert.Less(t, paused, numRows)
+
+	// no new differences arrive, and the maps aren't read, while paused
+	reads := atomic.LoadInt64(&cs.reads)
+	assert.Empty(t, getDiffs(100*time.Millisecond))
+	assert.Equal(t, reads, atomic.LoadInt64(&cs.reads))
+
+	pc.Resume()
+	for {
+		diffs, more, err := rd.GetDiffs(numRows, time.Second)
+		require.NoError(t, err)
+		all = append(all, diffs...)
+		if !more {
+			break
+		}
+	}
+	require.NoError(t, rd.Close())
+
+	require.Len(t, all, numRows)
+	for i, d := range all {
+		pk, err := d.KeyValue.(types.Tuple).Get(1)
+		require.NoError(t, err)
+		assert.Equal(t, types.Int(i), pk)
+	}
+}
@@ -0,0 +1,144 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/store/diff"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// ErrUnsupportedPrefixKey is returned by NewPrefixRowDiffer for tables whose keys can't be matched by a byte prefix.
+var ErrUnsupportedPrefixKey = errors.New("table keys cannot be matched by prefix")
+
+// NewPrefixRowDiffer returns a RowDiffer that reports only the differences in rows whose first primary key column
+// starts with |prefix|. Only string and inline blob key columns are supported, and keyless tables are not. The rows
+// with a prefix are contiguous in a row map, so rather than diffing the whole of each map, the RowDiffer diffs the
+// range of keys from |prefix| up to its successor with types.Map.DiffLeftRightInRange, which skips identical
+// subtrees within the range. The RowDiffer must be started with the maps returned by |td|.GetMaps. |opts| are
+// applied to the differences in the prefix.
+func NewPrefixRowDiffer(ctx context.Context, td TableDelta, prefix []byte, buf int, opts ...RowDifferOption) (RowDiffer, error) {
+	fromSch, toSch, err := td.GetSchemas(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if schema.IsKeyless(fromSch) || schema.IsKeyless(toSch) {
+		return nil, fmt.Errorf("%w: keyless table", ErrUnsupportedPrefixKey)
+	}
+
+	sch := toSch
+	if td.ToTable == nil {
+		sch = fromSch
+	}
+
+	kr, err := newPrefixKeyRange(sch, prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	prefixOpt := func(o *rowDifferOptions) {
+		o.diffFn = func(ctx context.Context, from, to types.Map, out chan<- diff.Difference) error {
+			return prefixDiff(ctx, from, to, out, kr)
+		}
+		// for the buffer of changes between the map diff and the conversion to differences
+		o.buffers++
+	}
+
+	return NewRowDiffer(ctx, fromSch, toSch, buf, append([]RowDifferOption{prefixOpt}, opts...)...), nil
+}
+
+// prefixKeyRange is the range of row keys whose first column starts with a prefix, from |start| up to but not
+// including |end|. |end| is nil if the range is unbounded above.
+type prefixKeyRange struct {
+	tag        uint64
+	start, end types.Value
+}
+
+// newPrefixKeyRange returns the range of keys of rows of |sch| whose first key column starts with |prefix|.
+func newPrefixKeyRange(sch schema.Schema, prefix []byte) (prefixKeyRange, error) {
+	if sch.GetPKCols().Size() == 0 {
+		return prefixKeyRange{}, fmt.Errorf("%w: no primary key columns", ErrUnsupportedPrefixKey)
+	}
+
+	col := sch.GetPKCols().GetAtIndex(0)
+	succ := prefixSuccessor(prefix)
+
+	kr := prefixKeyRange{tag: col.Tag}
+	switch col.Kind {
+	case types.StringKind:
+		kr.start = types.String(prefix)
+		if succ != nil {
+			kr.end = types.String(succ)
+		}
+	case types.InlineBlobKind:
+		kr.start = types.InlineBlob(prefix)
+		if succ != nil {
+			kr.end = types.InlineBlob(succ)
+		}
+	default:
+		return prefixKeyRange{}, fmt.Errorf("%w: first key column is of kind %s", ErrUnsupportedPrefixKey, col.Kind.String())
+	}
+
+	return kr, nil
+}
+
+// prefixSuccessor returns the smallest byte string greater than every byte string starting with |prefix|, or nil if
+// there is none because |prefix| is empty or made up only of 0xff bytes.
+func prefixSuccessor(prefix []byte) []byte {
+	for i := len(prefix) - 1; i >= 0; i-- {
+		if prefix[i] != 0xff {
+			succ := make([]byte, i+1)
+			copy(succ, prefix)
+			succ[i]++
+			return succ
+		}
+	}
+
+	return nil
+}
+
+// keys returns the row keys bounding |kr| in maps of format |nbf|. |end| is nil if the range is unbounded above.
+func (kr prefixKeyRange) keys(nbf *types.NomsBinFormat) (start, end types.Value, err error) {
+	start, err = types.NewTuple(nbf, types.Uint(kr.tag), kr.start)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if kr.end != nil {
+		end, err = types.NewTuple(nbf, types.Uint(kr.tag), kr.end)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return start, end, nil
+}
+
+// prefixDiff sends the differences between the rows of |from| and |to| in |kr| on |out|, in key order.
+func prefixDiff(ctx context.Context, from, to types.Map, out chan<- diff.Difference, kr prefixKeyRange) error {
+	start, end, err := kr.keys(from.Format())
+	if err != nil {
+		return err
+	}
+
+	return diffLeftRight(ctx, from.Format(), out, func(ctx context.Context, changes chan<- types.ValueChanged) error {
+		return to.DiffLeftRightInRange(ctx, from, start, end, changes)
+	})
+}
@@ -0,0 +1,145 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema/encoding"
+	"github.com/dolthub/dolt/go/store/diff"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+var testStringKeySch = schema.MustSchemaFromCols(mustColColl(
+	schema.NewColumn("pk", testPkTag, types.StringKind, true, schema.NotNullConstraint{}),
+	schema.NewColumn("val", testValTag, types.IntKind, false),
+))
+
+func stringKeyedTestTable(t *testing.T, vrw types.ValueReadWriter, sch schema.Schema, rows map[string]int) *doltdb.Table {
+	ctx := context.Background()
+
+	kvs := make([]types.Value, 0, 2*len(rows))
+	for pk, val := range rows {
+		k, err := types.NewTuple(vrw.Format(), types.Uint(testPkTag), types.String(pk))
+		require.NoError(t, err)
+		v, err := types.NewTuple(vrw.Format(), types.Uint(testValTag), types.Int(val))
+		require.NoError(t, err)
+		kvs = append(kvs, k, v)
+	}
+
+	m, err := types.NewMap(ctx, vrw, kvs...)
+	require.NoError(t, err)
+	schVal, err := encoding.MarshalSchemaAsNomsValue(ctx, vrw, sch)
+	require.NoError(t, err)
+	empty, err := types.NewMap(ctx, vrw)
+	require.NoError(t, err)
+	tbl, err := doltdb.NewTable(ctx, vrw, schVal, m, empty)
+	require.NoError(t, err)
+	return tbl
+}
+
+func TestPrefixRowDiffer(t *testing.T) {
+	ctx := context.Background()
+	vrw := types.NewMemoryValueStore()
+
+	fromRows := map[string]int{"a": 1, "aa": 2, "b": 3, "ba": 4, "bb": 5, "bc": 6, "c": 7, "ca": 8}
+	toRows := map[string]int{"a": 1, "ab": 2, "b": 30, "bb": 5, "bc": 60, "bd": 7, "c": 70, "cb": 8}
+	td := TableDelta{
+		FromName:  "t",
+		ToName:    "t",
+		FromTable: stringKeyedTestTable(t, vrw, testStringKeySch, fromRows),
+		ToTable:   stringKeyedTestTable(t, vrw, testStringKeySch, toRows),
+	}
+	from, to, err := td.GetMaps(ctx)
+	require.NoError(t, err)
+
+	rd := NewRowDiffer(ctx, testStringKeySch, testStringKeySch, 16)
+	rd.Start(ctx, from, to)
+	all := drainDiffs(t, rd)
+
+	keyString := func(d *diff.Difference) string {
+		return string(mustTupleGet(t, d.KeyValue.(types.Tuple), 1).(types.String))
+	}
+
+	for _, prefix := range []string{"", "a", "b", "bc", "c", "d"} {
+		t.Run(prefix, func(t *testing.T) {
+			var expected []*diff.Difference
+			for _, d := range all {
+				if strings.HasPrefix(keyString(d), prefix) {
+					expected = append(expected, d)
+				}
+			}
+
+			rd, err := NewPrefixRowDiffer(ctx, td, []byte(prefix), 4)
+			require.NoError(t, err)
+			rd.Start(ctx, from, to)
+			actual := drainDiffs(t, rd)
+
+			assertDiffsEqual(t, expected, actual)
+			for i := range actual {
+				assert.Equal(t, expected[i].NewKeyValue != nil, actual[i].NewKeyValue != nil)
+			}
+		})
+	}
+
+	rd, err = NewPrefixRowDiffer(ctx, td, []byte("b"), 4)
+	require.NoError(t, err)
+	rd.Start(ctx, from, to)
+	var keys []string
+	for _, d := range drainDiffs(t, rd) {
+		keys = append(keys, keyString(d))
+	}
+	assert.Equal(t, []string{"b", "ba", "bc", "bd"}, keys)
+}
+
+func TestPrefixRowDifferUnsupportedKey(t *testing.T) {
+	ctx := context.Background()
+	vrw := types.NewMemoryValueStore()
+
+	schVal, err := encoding.MarshalSchemaAsNomsValue(ctx, vrw, testKeyedSch)
+	require.NoError(t, err)
+	rows := keyedTestMap(t, vrw, 1, 1)
+	empty, err := types.NewMap(ctx, vrw)
+	require.NoError(t, err)
+	tbl, err := doltdb.NewTable(ctx, vrw, schVal, rows, empty)
+	require.NoError(t, err)
+
+	_, err = NewPrefixRowDiffer(ctx, TableDelta{FromName: "t", ToName: "t", FromTable: tbl, ToTable: tbl}, []byte("a"), 4)
+	assert.True(t, errors.Is(err, ErrUnsupportedPrefixKey))
+
+	keylessSchVal, err := encoding.MarshalSchemaAsNomsValue(ctx, vrw, testKeylessSch)
+	require.NoError(t, err)
+	keylessTbl, err := doltdb.NewTable(ctx, vrw, keylessSchVal, keylessTestMap(t, vrw, 1, 1), empty)
+	require.NoError(t, err)
+
+	_, err = NewPrefixRowDiffer(ctx, TableDelta{FromName: "t", ToName: "t", FromTable: keylessTbl, ToTable: keylessTbl}, []byte("a"), 4)
+	assert.True(t, errors.Is(err, ErrUnsupportedPrefixKey))
+}
+
+func TestPrefixSuccessor(t *testing.T) {
+	assert.Nil(t, prefixSuccessor(nil))
+	assert.Nil(t, prefixSuccessor([]byte{0xff, 0xff}))
+	assert.Equal(t, []byte("b"), prefixSuccessor([]byte("a")))
+	assert.Equal(t, []byte("ac"), prefixSuccessor([]byte("ab")))
+	assert.Equal(t, []byte{'b'}, prefixSuccessor([]byte{'a', 0xff, 0xff}))
+}
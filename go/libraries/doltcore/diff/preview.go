@@ -0,0 +1,54 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"context"
+	"time"
+
+	"github.com/dolthub/dolt/go/store/diff"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// previewPollTimeout is how long Preview waits on each call to GetDiffs
+const previewPollTimeout = 100 * time.Millisecond
+
+// Preview starts |rd| diffing |from| and |to|, reads at most |n| differences, and then closes |rd|, stopping the
+// diff rather than walking the rest of the maps. The returned bool is false if the diff finished within the first
+// |n| differences, and true if more differences may exist.
+func Preview(ctx context.Context, rd RowDiffer, from, to types.Map, n int) ([]*diff.Difference, bool, error) {
+	rd.Start(ctx, from, to)
+
+	var diffs []*diff.Difference
+	more := true
+	for more && len(diffs) < n {
+		var batch []*diff.Difference
+		var err error
+		batch, more, err = rd.GetDiffs(n-len(diffs), previewPollTimeout)
+
+		if err != nil {
+			_ = rd.Close()
+			return nil, false, err
+		}
+
+		diffs = append(diffs, batch...)
+	}
+
+	if err := rd.Close(); err != nil {
+		return nil, false, err
+	}
+
+	return diffs, more, nil
+}
@@ -0,0 +1,79 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/store/diff"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func TestPreview(t *testing.T) {
+	ctx := context.Background()
+	vrw := types.NewMemoryValueStore()
+
+	const numRows = 10000
+	var fromVals, toVals []int
+	for i := 0; i < numRows; i++ {
+		fromVals = append(fromVals, i, i)
+		toVals = append(toVals, i, -i)
+	}
+	from := keyedTestMap(t, vrw, fromVals...)
+	to := keyedTestMap(t, vrw, toVals...)
+
+	// countingDiffer returns a RowDiffer that counts the differences produced by its map diff
+	countingDiffer := func(produced *int64) RowDiffer {
+		ad := NewAsyncDiffer(8)
+		inner := ad.diffFn
+		ad.diffFn = func(ctx context.Context, from, to types.Map, out chan<- diff.Difference) error {
+			return pipeDiffs(ctx, from, to, out, inner, func(d diff.Difference, send func(diff.Difference) error) error {
+				atomic.AddInt64(produced, 1)
+				return send(d)
+			})
+		}
+		return ad
+	}
+
+	t.Run("stops after n", func(t *testing.T) {
+		var produced int64
+		diffs, more, err := Preview(ctx, countingDiffer(&produced), from, to, 10)
+		require.NoError(t, err)
+		assert.Len(t, diffs, 10)
+		assert.True(t, more)
+		assert.Less(t, atomic.LoadInt64(&produced), int64(numRows/10))
+
+		for i, d := range diffs {
+			pk, err := d.KeyValue.(types.Tuple).Get(1)
+			require.NoError(t, err)
+			assert.Equal(t, types.Int(i+1), pk) // row 0 is unchanged
+		}
+	})
+
+	t.Run("fewer than n", func(t *testing.T) {
+		small := keyedTestMap(t, vrw, 0, 0, 1, 1, 2, 2)
+		smallTo := keyedTestMap(t, vrw, 0, 0, 1, 10, 2, 20)
+
+		diffs, more, err := Preview(ctx, NewRowDiffer(ctx, testKeyedSch, testKeyedSch, 8), small, smallTo, 10)
+		require.NoError(t, err)
+		assert.Len(t, diffs, 2)
+		assert.False(t, more)
+	})
+}
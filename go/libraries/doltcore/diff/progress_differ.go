@@ -0,0 +1,130 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/dolthub/dolt/go/store/diff"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// DiffProgress holds the cumulative number of differences produced by a RowDiffer.
+type DiffProgress struct {
+	Added, Removed, Modified uint64
+}
+
+// ProgressReporter reports the running totals of the diff of a RowDiffer created with WithProgress. Totals count the
+// differences found by the underlying map diff, so for keyless tables a changed row is counted once regardless of its
+// cardinality. A ProgressReporter reports on a single diff: its channel is closed once the first diff it reports on
+// completes, and it reports nothing more, so each RowDiffer needs its own ProgressReporter.
+type ProgressReporter struct {
+	pr *progressReporter
+}
+
+// NewProgressReporter returns a ProgressReporter that reports running totals every |interval|.
+func NewProgressReporter(interval time.Duration) *ProgressReporter {
+	return &ProgressReporter{pr: newProgressReporter(interval)}
+}
+
+// Progress returns a channel of cumulative DiffProgress totals. A total is sent every reporting interval while the
+// diff runs, and a final total is sent when the diff completes, after which the channel is closed. Totals are
+// dropped rather than blocking the diff if the receiver falls behind.
+func (r *ProgressReporter) Progress() <-chan DiffProgress {
+	return r.pr.ch
+}
+
+// WithProgress makes a RowDiffer report the running totals of its diff to |r|.
+func WithProgress(r *ProgressReporter) RowDifferOption {
+	return func(o *rowDifferOptions) {
+		o.wrap(r.pr.wrap)
+	}
+}
+
+type progressReporter struct {
+	interval time.Duration
+	ch       chan DiffProgress
+
+	mu     sync.Mutex
+	totals DiffProgress
+	// finished is set once the final totals have been sent, after which nothing more is reported
+	finished bool
+}
+
+func newProgressReporter(interval time.Duration) *progressReporter {
+	// a single slot is enough as each report supersedes the previous one
+	return &progressReporter{interval: interval, ch: make(chan DiffProgress, 1)}
+}
+
+// wrap returns a mapDiffFunc that runs |diffFn|, counting each difference before forwarding it.
+func (pr *progressReporter) wrap(diffFn mapDiffFunc) mapDiffFunc {
+	return func(ctx context.Context, from, to types.Map, out chan<- diff.Difference) error {
+		defer pr.finish()
+
+		return pipeDiffsWithTicks(ctx, from, to, out, diffFn, pr.interval, pr.report, func(d diff.Difference, send func(diff.Difference) error) error {
+			pr.count(d)
+			return send(d)
+		})
+	}
+}
+
+func (pr *progressReporter) count(d diff.Difference) {
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+
+	switch d.ChangeType {
+	case types.DiffChangeAdded:
+		pr.totals.Added++
+	case types.DiffChangeRemoved:
+		pr.totals.Removed++
+	case types.DiffChangeModified:
+		pr.totals.Modified++
+	}
+}
+
+func (pr *progressReporter) report() {
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+
+	if pr.finished {
+		return
+	}
+
+	select {
+	case pr.ch <- pr.totals:
+	default:
+	}
+}
+
+// finish replaces any unread report with the final totals and closes the progress channel. Only the first call has
+// any effect, so a reporter reports on the first diff it wraps that completes.
+func (pr *progressReporter) finish() {
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+
+	if pr.finished {
+		return
+	}
+	pr.finished = true
+
+	select {
+	case <-pr.ch:
+	default:
+	}
+	pr.ch <- pr.totals
+	close(pr.ch)
+}
@@ -0,0 +1,113 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func TestRowDifferWithProgress(t *testing.T) {
+	ctx := context.Background()
+	vrw := types.NewMemoryValueStore()
+
+	var fromVals, toVals []int
+	for i := 0; i < 3000; i++ {
+		switch i % 3 {
+		case 0: // removed
+			fromVals = append(fromVals, i, i)
+		case 1: // added
+			toVals = append(toVals, i, i)
+		case 2: // modified
+			fromVals = append(fromVals, i, i)
+			toVals = append(toVals, i, -i)
+		}
+	}
+	from := keyedTestMap(t, vrw, fromVals...)
+	to := keyedTestMap(t, vrw, toVals...)
+
+	pr := NewProgressReporter(time.Microsecond)
+	rd := NewRowDiffer(ctx, testKeyedSch, testKeyedSch, 8, WithProgress(pr))
+	rd.Start(ctx, from, to)
+
+	progDone := make(chan []DiffProgress)
+	go func() {
+		var events []DiffProgress
+		for p := range pr.Progress() {
+			events = append(events, p)
+		}
+		progDone <- events
+	}()
+
+	expected := DiffProgress{}
+	for {
+		diffs, more, err := rd.GetDiffs(100, time.Second)
+		require.NoError(t, err)
+		for _, d := range diffs {
+			switch d.ChangeType {
+			case types.DiffChangeAdded:
+				expected.Added++
+			case types.DiffChangeRemoved:
+				expected.Removed++
+			case types.DiffChangeModified:
+				expected.Modified++
+			}
+		}
+		if !more {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	require.NoError(t, rd.Close())
+
+	events := <-progDone
+	require.NotEmpty(t, events)
+	for i := 1; i < len(events); i++ {
+		assert.True(t, events[i].Added >= events[i-1].Added)
+		assert.True(t, events[i].Removed >= events[i-1].Removed)
+		assert.True(t, events[i].Modified >= events[i-1].Modified)
+	}
+
+	assert.Equal(t, DiffProgress{Added: 1000, Removed: 1000, Modified: 1000}, expected)
+	assert.Equal(t, expected, events[len(events)-1])
+}
+
+func TestRowDifferWithProgressReusedReporter(t *testing.T) {
+	ctx := context.Background()
+	vrw := types.NewMemoryValueStore()
+
+	from := keyedTestMap(t, vrw, 1, 1, 2, 2)
+	to := keyedTestMap(t, vrw, 1, 1, 2, 20, 3, 3)
+
+	pr := NewProgressReporter(time.Microsecond)
+	for i := 0; i < 2; i++ {
+		rd := NewRowDiffer(ctx, testKeyedSch, testKeyedSch, 8, WithProgress(pr))
+		rd.Start(ctx, from, to)
+		assert.Len(t, drainDiffs(t, rd), 2)
+	}
+
+	// only the first diff is reported
+	var events []DiffProgress
+	for p := range pr.Progress() {
+		events = append(events, p)
+	}
+	assert.Equal(t, []DiffProgress{{Added: 1, Modified: 1}}, events)
+}
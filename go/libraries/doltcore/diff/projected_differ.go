@@ -0,0 +1,135 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/row"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/store/diff"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// ErrKeylessProjection is returned when creating a projected RowDiffer for a keyless table, whose rows are
+// identified by their values and so can't be matched across a projection.
+var ErrKeylessProjection = errors.New("keyless tables can't be diffed through a projection")
+
+// NewProjectedRowDiffer returns a RowDiffer that diffs rows as if the from rows had been written with |toSch|. Each
+// non primary key column of |fromSch| is projected onto the column of |toSch| with the same name, or onto the
+// column named by |renames| if it has an entry for the column, and columns with no such column in |toSch| are left
+// out. Rows whose projected from values equal their to values are not reported, so renamed or re-tagged columns
+// with unchanged values produce no differences, and the old values of the differences reported are projected.
+// Primary key columns must keep their tags, and rows whose stored values are the same are not compared. |opts| are
+// applied to the projected differences.
+func NewProjectedRowDiffer(ctx context.Context, fromSch, toSch schema.Schema, buf int, renames map[string]string, opts ...RowDifferOption) (RowDiffer, error) {
+	if schema.IsKeyless(fromSch) || schema.IsKeyless(toSch) {
+		return nil, ErrKeylessProjection
+	}
+
+	p, err := newProjection(fromSch, toSch, renames)
+	if err != nil {
+		return nil, err
+	}
+
+	opts = append([]RowDifferOption{func(o *rowDifferOptions) { o.wrap(p.wrap) }}, opts...)
+	return NewRowDiffer(ctx, fromSch, toSch, buf, opts...), nil
+}
+
+// projection maps the tags of the non primary key columns of one schema to those of another.
+type projection struct {
+	tags   map[uint64]uint64
+	toCols *schema.ColCollection
+}
+
+func newProjection(fromSch, toSch schema.Schema, renames map[string]string) (*projection, error) {
+	toCols := toSch.GetNonPKCols()
+	for fromName, toName := range renames {
+		if _, ok := fromSch.GetNonPKCols().GetByName(fromName); !ok {
+			return nil, fmt.Errorf("projection renames unknown column %s", fromName)
+		}
+
+		if _, ok := toCols.GetByName(toName); !ok {
+			return nil, fmt.Errorf("projection renames column %s to unknown column %s", fromName, toName)
+		}
+	}
+
+	p := &projection{tags: make(map[uint64]uint64), toCols: toCols}
+	err := fromSch.GetNonPKCols().Iter(func(tag uint64, col schema.Column) (stop bool, err error) {
+		name := col.Name
+		if renamed, ok := renames[name]; ok {
+			name = renamed
+		}
+
+		if toCol, ok := toCols.GetByName(name); ok {
+			p.tags[tag] = toCol.Tag
+		}
+		return false, nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// wrap returns a mapDiffFunc that runs |diffFn|, projecting the old values of its differences and dropping
+// modifications that the projection makes equal.
+func (p *projection) wrap(diffFn mapDiffFunc) mapDiffFunc {
+	return func(ctx context.Context, from, to types.Map, out chan<- diff.Difference) error {
+		return pipeDiffs(ctx, from, to, out, diffFn, func(d diff.Difference, send func(diff.Difference) error) error {
+			if d.OldValue == nil {
+				return send(d)
+			}
+
+			old, err := p.project(ctx, d.OldValue)
+			if err != nil {
+				return err
+			}
+
+			if d.ChangeType == types.DiffChangeModified && old.Equals(d.NewValue) {
+				return nil
+			}
+
+			d.OldValue = old
+			return send(d)
+		})
+	}
+}
+
+// project returns the value tuple |val| with its values moved to the tags they are projected onto.
+func (p *projection) project(ctx context.Context, val types.Value) (types.Value, error) {
+	tpl, ok := val.(types.Tuple)
+	if !ok {
+		return nil, fmt.Errorf("can't project a value of kind %s", val.Kind())
+	}
+
+	tvs, err := row.ParseTaggedValues(tpl)
+	if err != nil {
+		return nil, err
+	}
+
+	projected := make(row.TaggedValues, len(tvs))
+	for tag, v := range tvs {
+		if toTag, ok := p.tags[tag]; ok {
+			projected[toTag] = v
+		}
+	}
+
+	return projected.NomsTupleForNonPKCols(tpl.Format(), p.toCols).Value(ctx)
+}
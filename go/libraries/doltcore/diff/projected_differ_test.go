@@ -0,0 +1,117 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func TestProjectedRowDiffer(t *testing.T) {
+	ctx := context.Background()
+	vrw := types.NewMemoryValueStore()
+
+	const renamedTag = 2
+	renamedSch := schema.MustSchemaFromCols(mustColColl(
+		schema.NewColumn("pk", testPkTag, types.IntKind, true),
+		schema.NewColumn("value", renamedTag, types.IntKind, false),
+	))
+	retaggedSch := schema.MustSchemaFromCols(mustColColl(
+		schema.NewColumn("pk", testPkTag, types.IntKind, true),
+		schema.NewColumn("val", renamedTag, types.IntKind, false),
+	))
+
+	key := func(pk int) types.Tuple {
+		k, err := types.NewTuple(vrw.Format(), types.Uint(testPkTag), types.Int(pk))
+		require.NoError(t, err)
+		return k
+	}
+	val := func(tag uint64, v int) types.Tuple {
+		tup, err := types.NewTuple(vrw.Format(), types.Uint(tag), types.Int(v))
+		require.NoError(t, err)
+		return tup
+	}
+	rowMap := func(tag uint64, pkVals ...int) types.Map {
+		var kvs []types.Value
+		for i := 0; i < len(pkVals); i += 2 {
+			kvs = append(kvs, key(pkVals[i]), val(tag, pkVals[i+1]))
+		}
+		m, err := types.NewMap(ctx, vrw, kvs...)
+		require.NoError(t, err)
+		return m
+	}
+
+	// the value column is moved to a new tag, 1 is unchanged, 2 is modified, 3 is removed and 4 is added
+	from := rowMap(testValTag, 1, 1, 2, 2, 3, 3)
+	to := rowMap(renamedTag, 1, 1, 2, 20, 4, 4)
+
+	// diffed directly, every row in both maps is modified
+	rd := NewRowDiffer(ctx, testKeyedSch, renamedSch, 8)
+	rd.Start(ctx, from, to)
+	assert.Len(t, drainDiffs(t, rd), 4)
+
+	expected := []struct {
+		key        types.Value
+		changeType types.DiffChangeType
+		old, new   types.Value
+	}{
+		{key(2), types.DiffChangeModified, val(renamedTag, 2), val(renamedTag, 20)},
+		{key(3), types.DiffChangeRemoved, val(renamedTag, 3), nil},
+		{key(4), types.DiffChangeAdded, nil, val(renamedTag, 4)},
+	}
+
+	tests := []struct {
+		name    string
+		toSch   schema.Schema
+		renames map[string]string
+	}{
+		{"renamed", renamedSch, map[string]string{"val": "value"}},
+		{"retagged", retaggedSch, nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rd, err := NewProjectedRowDiffer(ctx, testKeyedSch, test.toSch, 8, test.renames)
+			require.NoError(t, err)
+			rd.Start(ctx, from, to)
+
+			diffs := drainDiffs(t, rd)
+			require.Len(t, diffs, len(expected))
+			for i, d := range diffs {
+				assert.True(t, expected[i].key.Equals(d.KeyValue))
+				assert.Equal(t, expected[i].changeType, d.ChangeType)
+				assertValuesEqual(t, expected[i].old, d.OldValue)
+				assertValuesEqual(t, expected[i].new, d.NewValue)
+			}
+		})
+	}
+
+	// a renamed column that isn't projected is left out, so its values look removed
+	rd, err := NewProjectedRowDiffer(ctx, testKeyedSch, renamedSch, 8, nil)
+	require.NoError(t, err)
+	rd.Start(ctx, from, to)
+	assert.Len(t, drainDiffs(t, rd), 4)
+
+	_, err = NewProjectedRowDiffer(ctx, testKeyedSch, renamedSch, 8, map[string]string{"val": "missing"})
+	assert.Error(t, err)
+	_, err = NewProjectedRowDiffer(ctx, testKeylessSch, testKeylessSch, 8, nil)
+	assert.Equal(t, ErrKeylessProjection, err)
+}
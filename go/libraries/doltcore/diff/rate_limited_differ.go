@@ -0,0 +1,114 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"time"
+
+	"github.com/dolthub/dolt/go/store/diff"
+)
+
+// NewRateLimitedRowDiffer returns a RowDiffer that returns differences from |rd| at no more than |diffsPerSec| per
+// second on average, allowing bursts of up to |burst| differences. |diffsPerSec| must be positive. Differences are only read from |rd| as the rate
+// allows, so while the consumer is throttled |rd|'s buffer fills and its producer blocks.
+func NewRateLimitedRowDiffer(rd RowDiffer, diffsPerSec float64, burst int) RowDiffer {
+	if burst < 1 {
+		burst = 1
+	}
+
+	return &rateLimitedDiffer{RowDiffer: rd, bucket: newTokenBucket(diffsPerSec, burst)}
+}
+
+type rateLimitedDiffer struct {
+	RowDiffer
+	bucket *tokenBucket
+}
+
+var _ RowDiffer = &rateLimitedDiffer{}
+
+// GetDiffs implements RowDiffer. It waits, until |timeout| at most, for the rate to allow at least one
+// difference, and then returns no more differences than the rate allows.
+func (rl *rateLimitedDiffer) GetDiffs(numDiffs int, timeout time.Duration) ([]*diff.Difference, bool, error) {
+	deadline := time.Now().Add(timeout)
+
+	allowed := rl.bucket.available()
+	if allowed == 0 {
+		wait := rl.bucket.timeUntilAvailable()
+		if time.Now().Add(wait).After(deadline) {
+			time.Sleep(time.Until(deadline))
+			return nil, true, nil
+		}
+
+		time.Sleep(wait)
+		allowed = rl.bucket.available()
+		if allowed == 0 {
+			return nil, true, nil
+		}
+	}
+
+	if numDiffs == 0 || numDiffs > allowed {
+		numDiffs = allowed
+	}
+
+	remaining := time.Until(deadline)
+	if remaining < 0 {
+		remaining = 0
+	}
+
+	diffs, more, err := rl.RowDiffer.GetDiffs(numDiffs, remaining)
+	rl.bucket.take(len(diffs))
+
+	return diffs, more, err
+}
+
+// tokenBucket is a token bucket rate limiter holding up to |burst| tokens, refilled at |rate| tokens per second.
+type tokenBucket struct {
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(rate float64, burst int) *tokenBucket {
+	return &tokenBucket{rate: rate, burst: float64(burst), tokens: float64(burst), last: time.Now()}
+}
+
+func (tb *tokenBucket) refill() {
+	now := time.Now()
+	tb.tokens += now.Sub(tb.last).Seconds() * tb.rate
+	if tb.tokens > tb.burst {
+		tb.tokens = tb.burst
+	}
+	tb.last = now
+}
+
+// available returns the number of whole tokens in the bucket.
+func (tb *tokenBucket) available() int {
+	tb.refill()
+	return int(tb.tokens)
+}
+
+// timeUntilAvailable returns how long until the bucket holds a whole token.
+func (tb *tokenBucket) timeUntilAvailable() time.Duration {
+	tb.refill()
+	if tb.tokens >= 1 {
+		return 0
+	}
+	return time.Duration((1 - tb.tokens) / tb.rate * float64(time.Second))
+}
+
+func (tb *tokenBucket) take(n int) {
+	tb.tokens -= float64(n)
+}
@@ -0,0 +1,57 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func TestRateLimitedRowDiffer(t *testing.T) {
+	ctx := context.Background()
+	vrw := types.NewMemoryValueStore()
+
+	const numRows = 61
+	var fromVals, toVals []int
+	for i := 0; i < numRows; i++ {
+		fromVals = append(fromVals, i, i)
+		toVals = append(toVals, i, i+1)
+	}
+	from := keyedTestMap(t, vrw, fromVals...)
+	to := keyedTestMap(t, vrw, toVals...)
+
+	const rate = 100.0
+	const burst = 10
+
+	rd := NewRateLimitedRowDiffer(NewRowDiffer(ctx, testKeyedSch, testKeyedSch, 64), rate, burst)
+	rd.Start(ctx, from, to)
+
+	start := time.Now()
+	diffs := drainDiffs(t, rd)
+	elapsed := time.Since(start)
+
+	require.Len(t, diffs, numRows)
+
+	// the first |burst| differences are returned immediately, and the rest at |rate|
+	expected := time.Duration(float64(numRows-burst) / rate * float64(time.Second))
+	assert.True(t, elapsed >= expected*8/10, "elapsed %v, expected about %v", elapsed, expected)
+	assert.True(t, elapsed <= expected*2, "elapsed %v, expected about %v", elapsed, expected)
+}
@@ -0,0 +1,109 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"context"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/row"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/store/diff"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// TagMapping maps the tags of a from schema's columns to the tags of the same columns in a to schema.
+type TagMapping map[uint64]uint64
+
+// TagMappingByName returns the TagMapping of the columns of |fromSch| to the columns of |toSch| with the same names.
+func TagMappingByName(fromSch, toSch schema.Schema) TagMapping {
+	mapping := make(TagMapping)
+	_ = fromSch.GetAllCols().Iter(func(tag uint64, col schema.Column) (stop bool, err error) {
+		if toCol, ok := toSch.GetAllCols().GetByName(col.Name); ok {
+			mapping[tag] = toCol.Tag
+		}
+		return false, nil
+	})
+	return mapping
+}
+
+// WithReorderedColumns makes a RowDiffer drop modifications in which the old and new rows have the same values, once
+// the tags of the old row are mapped to those of the new row by |mapping|, regardless of the order of the fields in
+// their tuples. This hides rows that were rewritten with their fields in a different order, as after a schema
+// migration. Tags of the old row missing from |mapping| are unchanged, so a nil mapping compares rows by their own
+// tags. For keyless tables, whose rows are otherwise reported as modified with no change in cardinality, reordered rows
+// with the same cardinality are dropped as well.
+func WithReorderedColumns(mapping TagMapping) RowDifferOption {
+	return func(o *rowDifferOptions) {
+		o.wrap(func(diffFn mapDiffFunc) mapDiffFunc {
+			return dropReorderedEqual(diffFn, mapping)
+		})
+	}
+}
+
+func dropReorderedEqual(diffFn mapDiffFunc, mapping TagMapping) mapDiffFunc {
+	return func(ctx context.Context, from, to types.Map, out chan<- diff.Difference) error {
+		return pipeDiffs(ctx, from, to, out, diffFn, func(d diff.Difference, send func(diff.Difference) error) error {
+			if d.ChangeType != types.DiffChangeModified {
+				return send(d)
+			}
+
+			equal, err := equalIgnoringOrder(d.OldValue, d.NewValue, mapping)
+			if err != nil {
+				return err
+			}
+
+			if equal {
+				return nil
+			}
+			return send(d)
+		})
+	}
+}
+
+// equalIgnoringOrder returns whether the row values |oldVal| and |newVal| have the same value for every tag, once
+// the tags of |oldVal| are mapped by |mapping|. Missing values are equal to NULLs.
+func equalIgnoringOrder(oldVal, newVal types.Value, mapping TagMapping) (bool, error) {
+	oldTVs, err := row.ParseTaggedValues(oldVal.(types.Tuple))
+	if err != nil {
+		return false, err
+	}
+
+	newTVs, err := row.ParseTaggedValues(newVal.(types.Tuple))
+	if err != nil {
+		return false, err
+	}
+
+	mappedTVs := make(row.TaggedValues, len(oldTVs))
+	for tag, val := range oldTVs {
+		if toTag, ok := mapping[tag]; ok {
+			tag = toTag
+		}
+		mappedTVs[tag] = val
+	}
+
+	for tag, val := range mappedTVs {
+		if !newTVs.GetWithDefault(tag, types.NullValue).Equals(val) {
+			return false, nil
+		}
+	}
+
+	for tag, val := range newTVs {
+		if !mappedTVs.GetWithDefault(tag, types.NullValue).Equals(val) {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
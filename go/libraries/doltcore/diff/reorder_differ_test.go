@@ -0,0 +1,126 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func TestReorderTolerantRowDiffer(t *testing.T) {
+	ctx := context.Background()
+	vrw := types.NewMemoryValueStore()
+	nbf := vrw.Format()
+
+	tuple := func(vals ...types.Value) types.Tuple {
+		tup, err := types.NewTuple(nbf, vals...)
+		require.NoError(t, err)
+		return tup
+	}
+	key := func(pk int) types.Tuple {
+		return tuple(types.Uint(testPkTag), types.Int(pk))
+	}
+	rowMap := func(kvs ...types.Value) types.Map {
+		m, err := types.NewMap(ctx, vrw, kvs...)
+		require.NoError(t, err)
+		return m
+	}
+
+	from := rowMap(
+		key(1), tuple(types.Uint(1), types.Int(10), types.Uint(2), types.String("a")),
+		key(2), tuple(types.Uint(1), types.Int(20), types.Uint(2), types.String("b")),
+	)
+	to := rowMap(
+		// reordered but equal
+		key(1), tuple(types.Uint(2), types.String("a"), types.Uint(1), types.Int(10)),
+		// reordered and changed
+		key(2), tuple(types.Uint(2), types.String("c"), types.Uint(1), types.Int(20)),
+	)
+
+	rd := NewRowDiffer(ctx, testKeyedSch, testKeyedSch, 8)
+	rd.Start(ctx, from, to)
+	assert.Len(t, drainDiffs(t, rd), 2)
+
+	rd = NewRowDiffer(ctx, testKeyedSch, testKeyedSch, 8, WithReorderedColumns(nil))
+	rd.Start(ctx, from, to)
+	diffs := drainDiffs(t, rd)
+	require.Len(t, diffs, 1)
+	assert.Equal(t, types.DiffChangeModified, diffs[0].ChangeType)
+	assert.True(t, key(2).Equals(diffs[0].KeyValue))
+
+	// the columns were retagged as well as reordered
+	retagged := rowMap(
+		key(1), tuple(types.Uint(4), types.String("a"), types.Uint(3), types.Int(10)),
+		key(2), tuple(types.Uint(4), types.String("b"), types.Uint(3), types.Int(20)),
+	)
+
+	rd = NewRowDiffer(ctx, testKeyedSch, testKeyedSch, 8, WithReorderedColumns(TagMapping{1: 3, 2: 4}))
+	rd.Start(ctx, from, retagged)
+	assert.Empty(t, drainDiffs(t, rd))
+
+	rd = NewRowDiffer(ctx, testKeyedSch, testKeyedSch, 8, WithReorderedColumns(nil))
+	rd.Start(ctx, from, retagged)
+	assert.Len(t, drainDiffs(t, rd), 2)
+}
+
+func TestReorderTolerantRowDifferKeyless(t *testing.T) {
+	ctx := context.Background()
+	vrw := types.NewMemoryValueStore()
+	nbf := vrw.Format()
+
+	key, err := types.NewTuple(nbf, types.Uint(schema.KeylessRowIdTag), types.Int(1))
+	require.NoError(t, err)
+	fromVal, err := types.NewTuple(nbf,
+		types.Uint(schema.KeylessRowCardinalityTag), types.Uint(2),
+		types.Uint(testValTag), types.Int(1), types.Uint(2), types.Int(2))
+	require.NoError(t, err)
+	toVal, err := types.NewTuple(nbf,
+		types.Uint(schema.KeylessRowCardinalityTag), types.Uint(2),
+		types.Uint(2), types.Int(2), types.Uint(testValTag), types.Int(1))
+	require.NoError(t, err)
+
+	from, err := types.NewMap(ctx, vrw, key, fromVal)
+	require.NoError(t, err)
+	to, err := types.NewMap(ctx, vrw, key, toVal)
+	require.NoError(t, err)
+
+	// with the cardinality unchanged, the reordered row can't be reported as an addition or removal
+	rd := NewRowDiffer(ctx, testKeylessSch, testKeylessSch, 8)
+	rd.Start(ctx, from, to)
+	_, _, err = rd.GetDiffs(10, time.Second)
+	assert.Error(t, err)
+	_ = rd.Close()
+
+	rd = NewRowDiffer(ctx, testKeylessSch, testKeylessSch, 8, WithReorderedColumns(nil))
+	rd.Start(ctx, from, to)
+	assert.Empty(t, drainDiffs(t, rd))
+}
+
+func TestTagMappingByName(t *testing.T) {
+	toSch := schema.MustSchemaFromCols(mustColColl(
+		schema.NewColumn("pk", 10, types.IntKind, true, schema.NotNullConstraint{}),
+		schema.NewColumn("new", 11, types.IntKind, false),
+		schema.NewColumn("val", 12, types.IntKind, false),
+	))
+
+	assert.Equal(t, TagMapping{testPkTag: 10, testValTag: 12}, TagMappingByName(testKeyedSch, toSch))
+}
@@ -0,0 +1,234 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"context"
+	"encoding/binary"
+
+	"github.com/dolthub/dolt/go/store/diff"
+	"github.com/dolthub/dolt/go/store/hash"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// RowChecksums holds a checksum of the value of each row in a row map, keyed by the hash of the row's key. The
+// checksums are computed once with a full scan by ComputeRowChecksums, and can be kept alongside a revision so that
+// later diffs made with WithChecksums can skip comparing the values of rows whose checksums match.
+//
+// Checksums are the first 8 bytes of the hash of a row's value, so rows with different values have matching
+// checksums with negligible probability.
+type RowChecksums struct {
+	sums map[hash.Hash]uint64
+}
+
+// ComputeRowChecksums scans every row in |m| and returns their checksums.
+func ComputeRowChecksums(ctx context.Context, m types.Map) (*RowChecksums, error) {
+	nbf := m.Format()
+	sums := make(map[hash.Hash]uint64, m.Len())
+	err := m.IterAll(ctx, func(k, v types.Value) error {
+		kh, err := k.Hash(nbf)
+		if err != nil {
+			return err
+		}
+
+		sums[kh], err = rowChecksum(nbf, v)
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &RowChecksums{sums: sums}, nil
+}
+
+// Len returns the number of rows with checksums.
+func (rc *RowChecksums) Len() int {
+	if rc == nil {
+		return 0
+	}
+	return len(rc.sums)
+}
+
+// checksum returns the checksum of the row whose key hashes to |kh|, and whether there is one.
+func (rc *RowChecksums) checksum(kh hash.Hash) (uint64, bool) {
+	if rc == nil {
+		return 0, false
+	}
+
+	sum, ok := rc.sums[kh]
+	return sum, ok
+}
+
+func rowChecksum(nbf *types.NomsBinFormat, v types.Value) (uint64, error) {
+	h, err := v.Hash(nbf)
+	if err != nil {
+		return 0, err
+	}
+
+	return binary.BigEndian.Uint64(h[:8]), nil
+}
+
+// WithChecksums makes a RowDiffer diff row maps whose checksums were computed with ComputeRowChecksums, |fromSums| for
+// the from map and |toSums| for the to map. Rows whose key is in both maps are reported as unchanged without comparing
+// their values if their checksums match, and their values are compared only when a checksum is missing. Either set of
+// checksums may be nil, in which case values are always compared.
+func WithChecksums(fromSums, toSums *RowChecksums) RowDifferOption {
+	return func(o *rowDifferOptions) {
+		o.diffFn = checksumDiffFunc(fromSums, toSums)
+	}
+}
+
+// checksumDiffFunc returns a mapDiffFunc that walks both maps in key order, using |fromSums| and |toSums| to skip
+// rows that are unchanged.
+func checksumDiffFunc(fromSums, toSums *RowChecksums) mapDiffFunc {
+	return func(ctx context.Context, from, to types.Map, out chan<- diff.Difference) error {
+		nbf := from.Format()
+
+		fromItr, err := from.Iterator(ctx)
+		if err != nil {
+			return err
+		}
+
+		toItr, err := to.Iterator(ctx)
+		if err != nil {
+			return err
+		}
+
+		fromKey, fromVal, err := fromItr.Next(ctx)
+		if err != nil {
+			return err
+		}
+
+		toKey, toVal, err := toItr.Next(ctx)
+		if err != nil {
+			return err
+		}
+
+		send := func(key types.Value, changeType types.DiffChangeType, oldVal, newVal types.Value) error {
+			var pp types.PathPart
+			if types.ValueCanBePathIndex(key) {
+				pp = types.NewIndexPath(key)
+			} else {
+				kh, err := key.Hash(nbf)
+				if err != nil {
+					return err
+				}
+				pp = types.NewHashIndexPath(kh)
+			}
+
+			d := diff.Difference{Path: types.Path{pp}, ChangeType: changeType, OldValue: oldVal, NewValue: newVal, KeyValue: key}
+			if changeType == types.DiffChangeAdded {
+				d.NewKeyValue = key
+			}
+
+			select {
+			case out <- d:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+
+		for fromKey != nil || toKey != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+
+			c, err := compareRowKeys(nbf, fromKey, toKey)
+			if err != nil {
+				return err
+			}
+
+			if c == 0 {
+				unchanged, err := rowUnchanged(nbf, fromKey, fromVal, toVal, fromSums, toSums)
+				if err != nil {
+					return err
+				}
+
+				if !unchanged {
+					if err = send(fromKey, types.DiffChangeModified, fromVal, toVal); err != nil {
+						return err
+					}
+				}
+			} else if c < 0 {
+				if err = send(fromKey, types.DiffChangeRemoved, fromVal, nil); err != nil {
+					return err
+				}
+			} else {
+				if err = send(toKey, types.DiffChangeAdded, nil, toVal); err != nil {
+					return err
+				}
+			}
+
+			if c <= 0 {
+				if fromKey, fromVal, err = fromItr.Next(ctx); err != nil {
+					return err
+				}
+			}
+
+			if c >= 0 {
+				if toKey, toVal, err = toItr.Next(ctx); err != nil {
+					return err
+				}
+			}
+		}
+
+		return nil
+	}
+}
+
+// compareRowKeys orders the keys of a lockstep walk of two maps, where a nil key is past the end of its map. It
+// returns a negative number if only |fromKey|'s row is next, a positive number if only |toKey|'s row is next, and
+// zero if they are the same row.
+func compareRowKeys(nbf *types.NomsBinFormat, fromKey, toKey types.Value) (int, error) {
+	switch {
+	case toKey == nil:
+		return -1, nil
+	case fromKey == nil:
+		return 1, nil
+	case fromKey.Equals(toKey):
+		return 0, nil
+	}
+
+	isLess, err := fromKey.Less(nbf, toKey)
+	if err != nil {
+		return 0, err
+	}
+
+	if isLess {
+		return -1, nil
+	}
+	return 1, nil
+}
+
+// rowUnchanged returns whether the row with key |key| is unchanged from |fromVal| to |toVal|. The values are only
+// compared if the row's checksums are missing or don't match.
+func rowUnchanged(nbf *types.NomsBinFormat, key, fromVal, toVal types.Value, fromSums, toSums *RowChecksums) (bool, error) {
+	if fromSums != nil && toSums != nil {
+		kh, err := key.Hash(nbf)
+		if err != nil {
+			return false, err
+		}
+
+		fromSum, fromOk := fromSums.checksum(kh)
+		toSum, toOk := toSums.checksum(kh)
+		if fromOk && toOk {
+			return fromSum == toSum, nil
+		}
+	}
+
+	return fromVal.Equals(toVal), nil
+}
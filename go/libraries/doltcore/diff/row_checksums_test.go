@@ -0,0 +1,113 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/store/diff"
+	"github.com/dolthub/dolt/go/store/hash"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func TestRowDifferWithChecksums(t *testing.T) {
+	ctx := context.Background()
+	vrw := types.NewMemoryValueStore()
+
+	fromRows := make([]int, 0, 400)
+	for i := 0; i < 200; i++ {
+		fromRows = append(fromRows, i, i)
+	}
+	toRows := append([]int(nil), fromRows...)
+	toRows[2*10+1] = -10
+	toRows[2*50+1] = -50
+	toRows = append(toRows[:2*100], toRows[2*101:]...)
+	toRows = append(toRows, 500, 500)
+
+	from := keyedTestMap(t, vrw, fromRows...)
+	to := keyedTestMap(t, vrw, toRows...)
+
+	fromSums, err := ComputeRowChecksums(ctx, from)
+	require.NoError(t, err)
+	toSums, err := ComputeRowChecksums(ctx, to)
+	require.NoError(t, err)
+	assert.Equal(t, 200, fromSums.Len())
+	assert.Equal(t, 200, toSums.Len())
+
+	full := NewRowDiffer(ctx, testKeyedSch, testKeyedSch, 8)
+	full.Start(ctx, from, to)
+	expected := drainDiffs(t, full)
+	require.Len(t, expected, 4)
+
+	t.Run("matches a full diff", func(t *testing.T) {
+		rd := NewRowDiffer(ctx, testKeyedSch, testKeyedSch, 8, WithChecksums(fromSums, toSums))
+		rd.Start(ctx, from, to)
+		assertDiffsEqual(t, expected, drainDiffs(t, rd))
+	})
+
+	t.Run("without checksums", func(t *testing.T) {
+		rd := NewRowDiffer(ctx, testKeyedSch, testKeyedSch, 8, WithChecksums(nil, nil))
+		rd.Start(ctx, from, to)
+		assertDiffsEqual(t, expected, drainDiffs(t, rd))
+	})
+
+	t.Run("rows with matching checksums are skipped", func(t *testing.T) {
+		// make the checksum of modified row 10 match, so its values are never compared and it's left out
+		k, err := types.NewTuple(vrw.Format(), types.Uint(testPkTag), types.Int(10))
+		require.NoError(t, err)
+		kh, err := k.Hash(vrw.Format())
+		require.NoError(t, err)
+
+		stale := &RowChecksums{sums: make(map[hash.Hash]uint64)}
+		for h, sum := range toSums.sums {
+			stale.sums[h] = sum
+		}
+		stale.sums[kh] = fromSums.sums[kh]
+
+		rd := NewRowDiffer(ctx, testKeyedSch, testKeyedSch, 8, WithChecksums(fromSums, stale))
+		rd.Start(ctx, from, to)
+		actual := drainDiffs(t, rd)
+
+		var skipped []*diff.Difference
+		for _, d := range expected {
+			if !d.KeyValue.Equals(k) {
+				skipped = append(skipped, d)
+			}
+		}
+		assertDiffsEqual(t, skipped, actual)
+	})
+
+	t.Run("keyless", func(t *testing.T) {
+		from := keylessTestMap(t, vrw, 1, 1, 2, 2, 3, 1)
+		to := keylessTestMap(t, vrw, 1, 1, 2, 1, 4, 2)
+
+		fromSums, err := ComputeRowChecksums(ctx, from)
+		require.NoError(t, err)
+		toSums, err := ComputeRowChecksums(ctx, to)
+		require.NoError(t, err)
+
+		full := NewRowDiffer(ctx, testKeylessSch, testKeylessSch, 8)
+		full.Start(ctx, from, to)
+		expected := drainDiffs(t, full)
+
+		rd := NewRowDiffer(ctx, testKeylessSch, testKeylessSch, 8, WithChecksums(fromSums, toSums))
+		rd.Start(ctx, from, to)
+		assertDiffsEqual(t, expected, drainDiffs(t, rd))
+	})
+}
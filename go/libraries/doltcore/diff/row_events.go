@@ -0,0 +1,97 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// rowEventPollTimeout is how long StreamRowEvents waits on each call to GetDiffs
+const rowEventPollTimeout = 100 * time.Millisecond
+
+// RowEvent is a single change streamed by StreamRowEvents. It is one of AddedRow, RemovedRow or ModifiedRow.
+type RowEvent interface {
+	isRowEvent()
+}
+
+// AddedRow is a row that exists only in the to map.
+type AddedRow struct {
+	Key types.Value
+	New types.Value
+}
+
+// RemovedRow is a row that exists only in the from map.
+type RemovedRow struct {
+	Key types.Value
+	Old types.Value
+}
+
+// ModifiedRow is a row whose value differs between the from and to maps.
+type ModifiedRow struct {
+	Key types.Value
+	Old types.Value
+	New types.Value
+}
+
+func (AddedRow) isRowEvent()    {}
+func (RemovedRow) isRowEvent()  {}
+func (ModifiedRow) isRowEvent() {}
+
+// StreamRowEvents reads every difference from |rd|, which must already be started, and sends it on |events| as a
+// RowEvent. For keyless tables each added or removed copy of a row is sent as its own event. |events| is closed
+// when StreamRowEvents returns, but |rd| is not.
+func StreamRowEvents(ctx context.Context, rd RowDiffer, events chan<- RowEvent) error {
+	defer close(events)
+
+	batchSize := cap(events)
+	if batchSize < 1 {
+		batchSize = 1
+	}
+
+	for {
+		diffs, more, err := rd.GetDiffs(batchSize, rowEventPollTimeout)
+		if err != nil {
+			return err
+		}
+
+		for _, d := range diffs {
+			var ev RowEvent
+			switch d.ChangeType {
+			case types.DiffChangeAdded:
+				ev = AddedRow{Key: d.KeyValue, New: d.NewValue}
+			case types.DiffChangeRemoved:
+				ev = RemovedRow{Key: d.KeyValue, Old: d.OldValue}
+			case types.DiffChangeModified:
+				ev = ModifiedRow{Key: d.KeyValue, Old: d.OldValue, New: d.NewValue}
+			default:
+				return fmt.Errorf("unexpected DiffChange type %d", d.ChangeType)
+			}
+
+			select {
+			case events <- ev:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+
+		if !more {
+			return nil
+		}
+	}
+}
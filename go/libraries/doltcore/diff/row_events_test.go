@@ -0,0 +1,98 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func collectRowEvents(t *testing.T, rd RowDiffer) []RowEvent {
+	events := make(chan RowEvent, 4)
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- StreamRowEvents(context.Background(), rd, events)
+	}()
+
+	var all []RowEvent
+	for ev := range events {
+		all = append(all, ev)
+	}
+	require.NoError(t, <-errCh)
+	require.NoError(t, rd.Close())
+	return all
+}
+
+func TestStreamRowEvents(t *testing.T) {
+	ctx := context.Background()
+	vrw := types.NewMemoryValueStore()
+
+	t.Run("keyed", func(t *testing.T) {
+		from := keyedTestMap(t, vrw, 1, 1, 2, 2, 3, 3)
+		to := keyedTestMap(t, vrw, 1, 10, 3, 3, 4, 4)
+
+		rd := NewRowDiffer(ctx, testKeyedSch, testKeyedSch, 8)
+		rd.Start(ctx, from, to)
+		events := collectRowEvents(t, rd)
+		require.Len(t, events, 3)
+
+		modified, ok := events[0].(ModifiedRow)
+		require.True(t, ok)
+		assertValuesEqual(t, types.Int(1), mustTupleGet(t, modified.Key.(types.Tuple), 1))
+		assertValuesEqual(t, types.Int(1), mustTupleGet(t, modified.Old.(types.Tuple), 1))
+		assertValuesEqual(t, types.Int(10), mustTupleGet(t, modified.New.(types.Tuple), 1))
+
+		removed, ok := events[1].(RemovedRow)
+		require.True(t, ok)
+		assertValuesEqual(t, types.Int(2), mustTupleGet(t, removed.Key.(types.Tuple), 1))
+		assertValuesEqual(t, types.Int(2), mustTupleGet(t, removed.Old.(types.Tuple), 1))
+
+		added, ok := events[2].(AddedRow)
+		require.True(t, ok)
+		assertValuesEqual(t, types.Int(4), mustTupleGet(t, added.Key.(types.Tuple), 1))
+		assertValuesEqual(t, types.Int(4), mustTupleGet(t, added.New.(types.Tuple), 1))
+	})
+
+	t.Run("keyless", func(t *testing.T) {
+		from := keylessTestMap(t, vrw, 7, 1, 8, 2)
+		to := keylessTestMap(t, vrw, 7, 3, 9, 1)
+
+		rd := NewRowDiffer(ctx, testKeylessSch, testKeylessSch, 8)
+		rd.Start(ctx, from, to)
+		events := collectRowEvents(t, rd)
+
+		// counts of events by the row's val
+		added := make(map[int64]int)
+		removed := make(map[int64]int)
+		for _, ev := range events {
+			switch ev := ev.(type) {
+			case AddedRow:
+				added[int64(mustTupleGet(t, ev.New.(types.Tuple), 3).(types.Int))]++
+			case RemovedRow:
+				removed[int64(mustTupleGet(t, ev.Old.(types.Tuple), 3).(types.Int))]++
+			default:
+				t.Fatalf("unexpected event %T", ev)
+			}
+		}
+
+		assert.Equal(t, map[int64]int{7: 2, 9: 1}, added)
+		assert.Equal(t, map[int64]int{8: 2}, removed)
+	})
+}
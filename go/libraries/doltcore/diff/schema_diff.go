@@ -204,3 +204,59 @@ func DiffForeignKeys(fromFks, toFKs []doltdb.ForeignKey) (diffs []ForeignKeyDiff
 	}
 	return diffs
 }
+
+// ColumnRename is a column whose name differs between two schemas.
+type ColumnRename struct {
+	Tag     uint64
+	OldName string
+	NewName string
+}
+
+// ColumnTypeChange is a column whose type differs between two schemas.
+type ColumnTypeChange struct {
+	Tag uint64
+	Old schema.Column
+	New schema.Column
+}
+
+// SchemaDelta is the structural difference between two schemas, matching columns by tag. A column that was both
+// renamed and had its type changed appears in both Renamed and TypeChanged. Columns are listed in the order
+// returned by DiffSchColumns.
+type SchemaDelta struct {
+	Added       []schema.Column
+	Dropped     []schema.Column
+	Renamed     []ColumnRename
+	TypeChanged []ColumnTypeChange
+}
+
+// IsEmpty returns true if no columns were added, dropped, renamed or had their types changed.
+func (sd SchemaDelta) IsEmpty() bool {
+	return len(sd.Added) == 0 && len(sd.Dropped) == 0 && len(sd.Renamed) == 0 && len(sd.TypeChanged) == 0
+}
+
+// DiffSchemaDelta returns the columns added, dropped, renamed and with changed types between |fromSch| and |toSch|.
+// Changes to constraints, defaults and primary key membership aren't reported.
+func DiffSchemaDelta(fromSch, toSch schema.Schema) SchemaDelta {
+	diffs, unionTags := DiffSchColumns(fromSch, toSch)
+
+	var sd SchemaDelta
+	for _, tag := range unionTags {
+		cd := diffs[tag]
+		switch cd.DiffType {
+		case SchDiffAdded:
+			sd.Added = append(sd.Added, *cd.New)
+		case SchDiffRemoved:
+			sd.Dropped = append(sd.Dropped, *cd.Old)
+		case SchDiffModified:
+			if cd.Old.Name != cd.New.Name {
+				sd.Renamed = append(sd.Renamed, ColumnRename{tag, cd.Old.Name, cd.New.Name})
+			}
+
+			if cd.Old.Kind != cd.New.Kind || !cd.Old.TypeInfo.Equals(cd.New.TypeInfo) {
+				sd.TypeChanged = append(sd.TypeChanged, ColumnTypeChange{tag, *cd.Old, *cd.New})
+			}
+		}
+	}
+
+	return sd
+}
@@ -15,11 +15,14 @@
 package diff
 
 import (
+	"context"
 	"reflect"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
 	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
 	"github.com/dolthub/dolt/go/store/types"
 )
@@ -66,3 +69,63 @@ func TestDiffSchemas(t *testing.T) {
 		t.Error(diffs, "!=", expected)
 	}
 }
+
+func TestTableDeltaSchemaDiff(t *testing.T) {
+	ctx := context.Background()
+	vrw := types.NewMemoryValueStore()
+
+	fromSch := schema.MustSchemaFromCols(mustColColl(
+		schema.NewColumn("pk", 0, types.IntKind, true, schema.NotNullConstraint{}),
+		schema.NewColumn("dropped", 1, types.StringKind, false),
+		schema.NewColumn("old_name", 2, types.StringKind, false),
+		schema.NewColumn("type_changed", 3, types.StringKind, false),
+		schema.NewColumn("unchanged", 4, types.StringKind, false),
+	))
+	toSch := schema.MustSchemaFromCols(mustColColl(
+		schema.NewColumn("pk", 0, types.IntKind, true, schema.NotNullConstraint{}),
+		schema.NewColumn("new_name", 2, types.StringKind, false),
+		schema.NewColumn("type_changed", 3, types.IntKind, false),
+		schema.NewColumn("unchanged", 4, types.StringKind, false),
+		schema.NewColumn("added", 5, types.StringKind, false),
+	))
+
+	empty, err := types.NewMap(ctx, vrw)
+	require.NoError(t, err)
+	root, err := doltdb.NewRootValue(ctx, vrw, nil, empty, empty)
+	require.NoError(t, err)
+	fromRoot, err := root.CreateEmptyTable(ctx, "t", fromSch)
+	require.NoError(t, err)
+	toRoot, err := root.CreateEmptyTable(ctx, "t", toSch)
+	require.NoError(t, err)
+
+	deltas, err := GetTableDeltas(ctx, fromRoot, toRoot)
+	require.NoError(t, err)
+	require.Len(t, deltas, 1)
+
+	sd, err := deltas[0].SchemaDiff(ctx)
+	require.NoError(t, err)
+	assert.False(t, sd.IsEmpty())
+
+	colNames := func(cols []schema.Column) (names []string) {
+		for _, col := range cols {
+			names = append(names, col.Name)
+		}
+		return names
+	}
+	assert.Equal(t, []string{"added"}, colNames(sd.Added))
+	assert.Equal(t, []string{"dropped"}, colNames(sd.Dropped))
+	assert.Equal(t, []ColumnRename{{2, "old_name", "new_name"}}, sd.Renamed)
+	require.Len(t, sd.TypeChanged, 1)
+	assert.Equal(t, uint64(3), sd.TypeChanged[0].Tag)
+	assert.Equal(t, types.StringKind, sd.TypeChanged[0].Old.Kind)
+	assert.Equal(t, types.IntKind, sd.TypeChanged[0].New.Kind)
+
+	assert.True(t, DiffSchemaDelta(fromSch, fromSch).IsEmpty())
+
+	deltas, err = GetTableDeltas(ctx, root, toRoot)
+	require.NoError(t, err)
+	require.Len(t, deltas, 1)
+	sd, err = deltas[0].SchemaDiff(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"pk", "new_name", "type_changed", "unchanged", "added"}, colNames(sd.Added))
+}
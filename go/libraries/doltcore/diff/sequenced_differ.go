@@ -0,0 +1,91 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"time"
+
+	"github.com/dolthub/dolt/go/store/diff"
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+// SequencedDifference is a difference along with its sequence number and its DifferenceHash. The sequence number
+// is only meaningful to the differ that assigned it, while the hash is the same for the same difference across
+// differs and restarts.
+type SequencedDifference struct {
+	*diff.Difference
+	Seq  uint64
+	Hash hash.Hash
+}
+
+// SequencedRowDiffer is a RowDiffer that numbers the differences it returns, so that consumers can detect missed
+// differences.
+type SequencedRowDiffer interface {
+	RowDiffer
+
+	// GetSequencedDiffs is GetDiffs, with each difference tagged with its sequence number.
+	GetSequencedDiffs(numDiffs int, timeout time.Duration) ([]SequencedDifference, bool, error)
+
+	// NextSeq returns the sequence number that the next difference will be given.
+	NextSeq() uint64
+}
+
+// NewSequencedRowDiffer returns a SequencedRowDiffer that numbers the differences returned by |rd| consecutively,
+// starting at |start|. Numbers are assigned to differences as they are returned, so each copy of a keyless row
+// gets its own number. Differences returned by GetDiffs use up sequence numbers too, so mixing calls to GetDiffs
+// and GetSequencedDiffs leaves gaps in the sequence.
+func NewSequencedRowDiffer(rd RowDiffer, start uint64) SequencedRowDiffer {
+	return &sequencedDiffer{RowDiffer: rd, next: start}
+}
+
+type sequencedDiffer struct {
+	RowDiffer
+	next uint64
+}
+
+var _ SequencedRowDiffer = &sequencedDiffer{}
+
+// GetDiffs implements RowDiffer.
+func (sd *sequencedDiffer) GetDiffs(numDiffs int, timeout time.Duration) ([]*diff.Difference, bool, error) {
+	diffs, more, err := sd.RowDiffer.GetDiffs(numDiffs, timeout)
+	sd.next += uint64(len(diffs))
+	return diffs, more, err
+}
+
+// GetSequencedDiffs implements SequencedRowDiffer.
+func (sd *sequencedDiffer) GetSequencedDiffs(numDiffs int, timeout time.Duration) ([]SequencedDifference, bool, error) {
+	diffs, more, err := sd.RowDiffer.GetDiffs(numDiffs, timeout)
+	if err != nil {
+		return nil, false, err
+	}
+
+	sequenced := make([]SequencedDifference, len(diffs))
+	for i, d := range diffs {
+		h, err := DifferenceHash(d)
+		if err != nil {
+			return nil, false, err
+		}
+
+		sequenced[i] = SequencedDifference{Difference: d, Seq: sd.next, Hash: h}
+		sd.next++
+	}
+
+	return sequenced, more, nil
+}
+
+// NextSeq implements SequencedRowDiffer.
+func (sd *sequencedDiffer) NextSeq() uint64 {
+	return sd.next
+}
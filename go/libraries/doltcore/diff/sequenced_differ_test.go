@@ -0,0 +1,69 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func TestSequencedRowDiffer(t *testing.T) {
+	ctx := context.Background()
+	vrw := types.NewMemoryValueStore()
+
+	// 3 copies of 1 added, 3 copies of 2 removed and 2 copies of 3 added
+	from := keylessTestMap(t, vrw, 1, 1, 2, 3)
+	to := keylessTestMap(t, vrw, 1, 4, 3, 2)
+
+	const start = 100
+	rd := NewSequencedRowDiffer(NewRowDiffer(ctx, testKeylessSch, testKeylessSch, 2), start)
+	rd.Start(ctx, from, to)
+
+	var seqs []uint64
+	seen := make(map[*types.DiffChangeType]bool)
+	for {
+		// fewer than the copies of a row, so expansions are split across calls
+		diffs, more, err := rd.GetSequencedDiffs(2, time.Second)
+		require.NoError(t, err)
+
+		for _, d := range diffs {
+			require.NotNil(t, d.Difference)
+			require.False(t, seen[&d.ChangeType], "differences should not be aliased")
+			seen[&d.ChangeType] = true
+			seqs = append(seqs, d.Seq)
+
+			h, err := DifferenceHash(d.Difference)
+			require.NoError(t, err)
+			assert.Equal(t, h, d.Hash)
+		}
+
+		if !more {
+			break
+		}
+	}
+	require.NoError(t, rd.Close())
+
+	require.Len(t, seqs, 8)
+	for i, seq := range seqs {
+		assert.Equal(t, uint64(start+i), seq)
+	}
+	assert.Equal(t, uint64(start+8), rd.NextSeq())
+}
@@ -0,0 +1,88 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"context"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/dolthub/dolt/go/store/diff"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// WithSequentialKeys makes a RowDiffer faster for tables whose changes are clustered in contiguous runs of keys, such
+// as append heavy tables with auto increment keys. The maps are diffed with types.Map.DiffLeftRight, which skips
+// identical subtrees of the maps, and the values of changed rows are taken from the map diff's cursors as it walks
+// them, rather than being looked up from the root of each map.
+func WithSequentialKeys() RowDifferOption {
+	return func(o *rowDifferOptions) {
+		o.diffFn = sequentialDiff
+		// for the buffer of changes between the map diff and the conversion to differences
+		o.buffers++
+	}
+}
+
+// sequentialDiff is equivalent to diff.Diff for maps of row tuples, but rather than looking up the values of each
+// changed key from the root of each map, it uses the values found by the map diff.
+func sequentialDiff(ctx context.Context, from, to types.Map, out chan<- diff.Difference) error {
+	return diffLeftRight(ctx, from.Format(), out, func(ctx context.Context, changes chan<- types.ValueChanged) error {
+		return to.DiffLeftRight(ctx, from, changes)
+	})
+}
+
+// diffLeftRight runs |mapDiff|, a left-right diff of two maps of row tuples, and sends each change it produces to
+// |out| as the equivalent diff.Difference.
+func diffLeftRight(ctx context.Context, nbf *types.NomsBinFormat, out chan<- diff.Difference, mapDiff func(ctx context.Context, changes chan<- types.ValueChanged) error) error {
+	eg, ctx := errgroup.WithContext(ctx)
+	changes := make(chan types.ValueChanged, cap(out))
+
+	eg.Go(func() error {
+		defer close(changes)
+		return mapDiff(ctx, changes)
+	})
+
+	eg.Go(func() error {
+		for change := range changes {
+			h, err := change.Key.Hash(nbf)
+			if err != nil {
+				return err
+			}
+
+			d := diff.Difference{
+				Path:         types.Path{types.NewHashIndexPath(h)},
+				ChangeType:   change.ChangeType,
+				OldValue:     change.OldValue,
+				NewValue:     change.NewValue,
+				KeyValue:     change.Key,
+				RootKeyValue: change.Key,
+			}
+
+			if change.ChangeType == types.DiffChangeAdded {
+				d.NewKeyValue = change.Key
+			}
+
+			select {
+			case out <- d:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+
+		return nil
+	})
+
+	return eg.Wait()
+}
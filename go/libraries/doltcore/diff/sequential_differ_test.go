@@ -0,0 +1,129 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// appendHeavyMaps returns a map of |n| sequential rows, and a map in which a few of those rows have been updated
+// or deleted and |n| more rows have been appended.
+func appendHeavyMaps(tb testing.TB, vrw types.ValueReadWriter, n int) (from, to types.Map) {
+	var fromVals, toVals []int
+	for i := 0; i < n; i++ {
+		fromVals = append(fromVals, i, i)
+		switch {
+		case i%97 == 0: // removed
+		case i%89 == 0: // modified
+			toVals = append(toVals, i, -i)
+		default:
+			toVals = append(toVals, i, i)
+		}
+	}
+	for i := n; i < 2*n; i++ {
+		toVals = append(toVals, i, i)
+	}
+
+	return keyedTestMap(tb, vrw, fromVals...), keyedTestMap(tb, vrw, toVals...)
+}
+
+func TestSequentialKeyRowDifferMatchesGeneral(t *testing.T) {
+	ctx := context.Background()
+	vrw := types.NewMemoryValueStore()
+
+	dense1, dense2 := appendHeavyMaps(t, vrw, 2000)
+	sparse1 := keyedTestMap(t, vrw, 0, 0, 1000, 1000, 1000000, 1)
+	sparse2 := keyedTestMap(t, vrw, 0, 0, 1000, 1001, 2000000, 2)
+
+	tests := []struct {
+		name     string
+		from, to types.Map
+	}{
+		{"dense", dense1, dense2},
+		{"sparse", sparse1, sparse2},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			general := NewRowDiffer(ctx, testKeyedSch, testKeyedSch, 64)
+			general.Start(ctx, test.from, test.to)
+			expected := drainDiffs(t, general)
+
+			seq := NewRowDiffer(ctx, testKeyedSch, testKeyedSch, 64, WithSequentialKeys())
+			seq.Start(ctx, test.from, test.to)
+			actual := drainDiffs(t, seq)
+
+			require.Equal(t, len(expected), len(actual))
+			for i := range expected {
+				assert.Equal(t, expected[i].ChangeType, actual[i].ChangeType)
+				assert.True(t, expected[i].KeyValue.Equals(actual[i].KeyValue))
+				assert.Equal(t, expected[i].Path.String(), actual[i].Path.String())
+				assertValuesEqual(t, expected[i].OldValue, actual[i].OldValue)
+				assertValuesEqual(t, expected[i].NewValue, actual[i].NewValue)
+			}
+		})
+	}
+}
+
+func assertValuesEqual(t *testing.T, expected, actual types.Value) {
+	if expected == nil {
+		assert.Nil(t, actual)
+		return
+	}
+	require.NotNil(t, actual)
+	assert.True(t, expected.Equals(actual))
+}
+
+func BenchmarkAppendHeavyDiff(b *testing.B) {
+	ctx := context.Background()
+	vrw := types.NewMemoryValueStore()
+	from, to := appendHeavyMaps(b, vrw, 50000)
+
+	benchmarks := []struct {
+		name      string
+		newDiffer func() RowDiffer
+	}{
+		{"general", func() RowDiffer { return NewRowDiffer(ctx, testKeyedSch, testKeyedSch, 1024) }},
+		{"sequential", func() RowDiffer { return NewRowDiffer(ctx, testKeyedSch, testKeyedSch, 1024, WithSequentialKeys()) }},
+	}
+
+	for _, bm := range benchmarks {
+		b.Run(bm.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				rd := bm.newDiffer()
+				rd.Start(ctx, from, to)
+				for {
+					_, more, err := rd.GetDiffs(1024, time.Second)
+					if err != nil {
+						b.Fatal(err)
+					}
+					if !more {
+						break
+					}
+				}
+				if err := rd.Close(); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
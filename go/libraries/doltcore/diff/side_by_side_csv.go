@@ -0,0 +1,159 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/row"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/table/untyped/csv"
+	"github.com/dolthub/dolt/go/store/diff"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+const (
+	// SideBySideDiffTypeCol is the name of the column of a side-by-side diff holding each row's change type
+	SideBySideDiffTypeCol = "diff_type"
+
+	sideBySideOldSuffix = "_old"
+	sideBySideNewSuffix = "_new"
+
+	// sideBySidePollTimeout is how long WriteSideBySideCSV waits on each call to GetDiffs
+	sideBySidePollTimeout = 100 * time.Millisecond
+	// sideBySideBatchSize is the number of differences WriteSideBySideCSV requests on each call to GetDiffs
+	sideBySideBatchSize = 1024
+)
+
+// SideBySideSchema returns the schema of the rows WriteSideBySideCSV writes for a table with schema |sch|. It has
+// a diff_type column holding one of "added", "modified" or "removed", then name_old and name_new columns for each
+// of |sch|'s columns, holding the values of the column in the old and new rows.
+func SideBySideSchema(sch schema.Schema) (schema.Schema, error) {
+	cols := []schema.Column{schema.NewColumn(SideBySideDiffTypeCol, 0, types.StringKind, false)}
+
+	tag := uint64(1)
+	err := sch.GetAllCols().Iter(func(_ uint64, col schema.Column) (stop bool, err error) {
+		cols = append(cols,
+			schema.NewColumn(col.Name+sideBySideOldSuffix, tag, col.Kind, false),
+			schema.NewColumn(col.Name+sideBySideNewSuffix, tag+1, col.Kind, false))
+		tag += 2
+		return false, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	cc, err := schema.NewColCollection(cols...)
+	if err != nil {
+		return nil, err
+	}
+
+	return schema.UnkeyedSchemaFromCols(cc), nil
+}
+
+// WriteSideBySideCSV reads every difference from |rd|, which must already be started, and writes it to |wr| as a
+// csv row laid out by SideBySideSchema for |sch|, which must be the schema of the diffed table. Added rows have
+// empty old columns, and removed rows have empty new columns. For keyless tables each added or removed copy of a
+// row is written as its own csv row. |wr| is closed when WriteSideBySideCSV returns, but |rd| is not.
+func WriteSideBySideCSV(ctx context.Context, rd RowDiffer, sch schema.Schema, wr io.WriteCloser, info *csv.CSVFileInfo) error {
+	outSch, err := SideBySideSchema(sch)
+	if err != nil {
+		wr.Close()
+		return err
+	}
+
+	csvWr, err := csv.NewCSVWriter(wr, outSch, info)
+	if err != nil {
+		return err
+	}
+
+	err = writeSideBySideRows(ctx, rd, sch, outSch, csvWr)
+	if err != nil {
+		_ = csvWr.Close(ctx)
+		return err
+	}
+
+	return csvWr.Close(ctx)
+}
+
+func writeSideBySideRows(ctx context.Context, rd RowDiffer, sch, outSch schema.Schema, csvWr *csv.CSVWriter) error {
+	for {
+		diffs, more, err := rd.GetDiffs(sideBySideBatchSize, sideBySidePollTimeout)
+		if err != nil {
+			return err
+		}
+
+		for _, d := range diffs {
+			r, err := sideBySideRow(sch, outSch, d)
+			if err != nil {
+				return err
+			}
+
+			err = csvWr.WriteRow(ctx, r)
+			if err != nil {
+				return err
+			}
+		}
+
+		if !more {
+			return nil
+		}
+
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+	}
+}
+
+// sideBySideRow returns the row of |outSch|, as returned by SideBySideSchema for |sch|, for |d|.
+func sideBySideRow(sch, outSch schema.Schema, d *diff.Difference) (row.Row, error) {
+	diffType, err := diffTypeName(d.ChangeType)
+	if err != nil {
+		return nil, err
+	}
+
+	oldRow, err := diffSideRow(sch, d.KeyValue, d.OldValue)
+	if err != nil {
+		return nil, err
+	}
+
+	newRow, err := diffSideRow(sch, d.KeyValue, d.NewValue)
+	if err != nil {
+		return nil, err
+	}
+
+	taggedVals := row.TaggedValues{0: types.String(diffType)}
+	tag := uint64(1)
+	_ = sch.GetAllCols().Iter(func(colTag uint64, col schema.Column) (stop bool, err error) {
+		if oldRow != nil {
+			if val, ok := oldRow.GetColVal(colTag); ok {
+				taggedVals[tag] = val
+			}
+		}
+
+		if newRow != nil {
+			if val, ok := newRow.GetColVal(colTag); ok {
+				taggedVals[tag+1] = val
+			}
+		}
+
+		tag += 2
+		return false, nil
+	})
+
+	return row.New(d.KeyValue.(types.Tuple).Format(), outSch, taggedVals)
+}
@@ -0,0 +1,81 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/table/untyped/csv"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+type closingBuffer struct {
+	bytes.Buffer
+	closed bool
+}
+
+func (b *closingBuffer) Close() error {
+	b.closed = true
+	return nil
+}
+
+func TestWriteSideBySideCSV(t *testing.T) {
+	ctx := context.Background()
+	vrw := types.NewMemoryValueStore()
+
+	from := keyedTestMap(t, vrw, 1, 10, 2, 20, 4, 40)
+	to := keyedTestMap(t, vrw, 2, 21, 3, 30, 4, 40)
+
+	rd := NewRowDiffer(ctx, testKeyedSch, testKeyedSch, 16)
+	rd.Start(ctx, from, to)
+	out := &closingBuffer{}
+	require.NoError(t, WriteSideBySideCSV(ctx, rd, testKeyedSch, out, csv.NewCSVInfo()))
+	require.NoError(t, rd.Close())
+
+	expected := "diff_type,pk_old,pk_new,val_old,val_new\n" +
+		"removed,1,,10,\n" +
+		"modified,2,2,20,21\n" +
+		"added,,3,,30\n"
+	assert.Equal(t, expected, out.String())
+	assert.True(t, out.closed)
+}
+
+func TestWriteSideBySideCSVKeyless(t *testing.T) {
+	ctx := context.Background()
+	vrw := types.NewMemoryValueStore()
+
+	from := keylessTestMap(t, vrw, 1, 1, 2, 3)
+	to := keylessTestMap(t, vrw, 2, 1, 3, 2)
+
+	rd := NewRowDiffer(ctx, testKeylessSch, testKeylessSch, 16)
+	rd.Start(ctx, from, to)
+	out := &closingBuffer{}
+	require.NoError(t, WriteSideBySideCSV(ctx, rd, testKeylessSch, out, csv.NewCSVInfo().SetDelim("|")))
+	require.NoError(t, rd.Close())
+
+	// each copy of a keyless row is written separately, and changes in cardinality are additions or removals
+	expected := "diff_type|val_old|val_new\n" +
+		"removed|1|\n" +
+		"removed|2|\n" +
+		"removed|2|\n" +
+		"added||3\n" +
+		"added||3\n"
+	assert.Equal(t, expected, out.String())
+}
@@ -0,0 +1,121 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dolthub/dolt/go/store/diff"
+	"github.com/dolthub/dolt/go/store/hash"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// ErrDiffStalled is returned by a RowDiffer created with WithStallTimeout when a read by its map diff stalls.
+var ErrDiffStalled = errors.New("diff stalled")
+
+// WithStallTimeout makes a RowDiffer abort its map diff with ErrDiffStalled, returned by GetDiffs and Close, if any
+// value read from storage by the map diff takes longer than |timeout|. Each read is given its own deadline, so long
+// runs of identical rows and slow consumers are not mistaken for stalls. A read that is stuck regardless of
+// cancellation is abandoned rather than waited on, and the map diff exits without it.
+func WithStallTimeout(timeout time.Duration) RowDifferOption {
+	return func(o *rowDifferOptions) {
+		o.wrapUnbuffered(func(diffFn mapDiffFunc) mapDiffFunc {
+			return withStallTimeout(diffFn, timeout)
+		})
+	}
+}
+
+// withStallTimeout returns a mapDiffFunc that runs |diffFn| on maps whose reads fail with ErrDiffStalled if they
+// take longer than |timeout|.
+func withStallTimeout(diffFn mapDiffFunc, timeout time.Duration) mapDiffFunc {
+	return func(ctx context.Context, from, to types.Map, out chan<- diff.Difference) error {
+		wrap := func(vrw types.ValueReadWriter) types.ValueReadWriter {
+			return deadlineValueReadWriter{ValueReadWriter: vrw, timeout: timeout}
+		}
+
+		from, err := from.WithValueReadWriter(wrap)
+		if err != nil {
+			return err
+		}
+
+		to, err = to.WithValueReadWriter(wrap)
+		if err != nil {
+			return err
+		}
+
+		return diffFn(ctx, from, to, out)
+	}
+}
+
+// deadlineValueReadWriter is a ValueReadWriter whose reads fail with ErrDiffStalled if they take longer than
+// |timeout|.
+type deadlineValueReadWriter struct {
+	types.ValueReadWriter
+	timeout time.Duration
+}
+
+func (vrw deadlineValueReadWriter) ReadValue(ctx context.Context, h hash.Hash) (types.Value, error) {
+	v, err := vrw.withDeadline(ctx, func(ctx context.Context) (interface{}, error) {
+		return vrw.ValueReadWriter.ReadValue(ctx, h)
+	})
+	if err != nil {
+		return nil, err
+	}
+	// |v| is nil if the value wasn't found
+	val, _ := v.(types.Value)
+	return val, nil
+}
+
+func (vrw deadlineValueReadWriter) ReadManyValues(ctx context.Context, hashes hash.HashSlice) (types.ValueSlice, error) {
+	vs, err := vrw.withDeadline(ctx, func(ctx context.Context) (interface{}, error) {
+		return vrw.ValueReadWriter.ReadManyValues(ctx, hashes)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return vs.(types.ValueSlice), nil
+}
+
+// withDeadline runs |read| on its own goroutine with a context that is cancelled after |timeout|, and returns
+// ErrDiffStalled if it hasn't returned by then.
+func (vrw deadlineValueReadWriter) withDeadline(ctx context.Context, read func(ctx context.Context) (interface{}, error)) (interface{}, error) {
+	ctx, cancel := context.WithTimeout(ctx, vrw.timeout)
+	defer cancel()
+
+	type result struct {
+		v   interface{}
+		err error
+	}
+
+	// buffered so that a read that returns after the deadline doesn't block its goroutine
+	done := make(chan result, 1)
+	go func() {
+		v, err := read(ctx)
+		done <- result{v, err}
+	}()
+
+	select {
+	case r := <-done:
+		return r.v, r.err
+	case <-ctx.Done():
+		if ctx.Err() == context.DeadlineExceeded {
+			return nil, fmt.Errorf("%w: a read took longer than %v", ErrDiffStalled, vrw.timeout)
+		}
+		return nil, ctx.Err()
+	}
+}
@@ -0,0 +1,147 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/store/chunks"
+	"github.com/dolthub/dolt/go/store/hash"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// stallingChunkStore is a ChunkStore whose reads block, ignoring cancellation, once |stalled| is set and until
+// |unstall| is closed. Each read also takes |delay|.
+type stallingChunkStore struct {
+	chunks.ChunkStore
+	stalled int32
+	unstall chan struct{}
+	delay   time.Duration
+}
+
+func (cs *stallingChunkStore) maybeStall() {
+	time.Sleep(cs.delay)
+	if atomic.LoadInt32(&cs.stalled) != 0 {
+		<-cs.unstall
+	}
+}
+
+func (cs *stallingChunkStore) Get(ctx context.Context, h hash.Hash) (chunks.Chunk, error) {
+	cs.maybeStall()
+	return cs.ChunkStore.Get(ctx, h)
+}
+
+func (cs *stallingChunkStore) GetMany(ctx context.Context, hashes hash.HashSet, found func(*chunks.Chunk)) error {
+	cs.maybeStall()
+	return cs.ChunkStore.GetMany(ctx, hashes, found)
+}
+
+// stallTestMaps returns maps large enough to span many chunks, which differ only in their last row, read through a
+// stallingChunkStore so that diffing them reads chunks from it.
+func stallTestMaps(t *testing.T) (from, to types.Map, cs *stallingChunkStore) {
+	ctx := context.Background()
+	storage := &chunks.MemoryStorage{}
+	vrw := types.NewValueStore(storage.NewView())
+	pkVals := make([]int, 0, 20000)
+	for i := 0; i < 10000; i++ {
+		pkVals = append(pkVals, i, i)
+	}
+	from = keyedTestMap(t, vrw, pkVals...)
+	pkVals[len(pkVals)-1] = -1
+	to = keyedTestMap(t, vrw, pkVals...)
+
+	tup, err := types.NewTuple(vrw.Format(), from, to)
+	require.NoError(t, err)
+	ref, err := vrw.WriteValue(ctx, tup)
+	require.NoError(t, err)
+	_, err = vrw.Commit(ctx, ref.TargetHash(), hash.Hash{})
+	require.NoError(t, err)
+
+	cs = &stallingChunkStore{ChunkStore: storage.NewView(), unstall: make(chan struct{})}
+	val, err := types.NewValueStore(cs).ReadValue(ctx, ref.TargetHash())
+	require.NoError(t, err)
+	return mustTupleGet(t, val.(types.Tuple), 0).(types.Map), mustTupleGet(t, val.(types.Tuple), 1).(types.Map), cs
+}
+
+func TestRowDifferWithStallTimeout(t *testing.T) {
+	ctx := context.Background()
+	from, to, cs := stallTestMaps(t)
+	defer close(cs.unstall)
+
+	atomic.StoreInt32(&cs.stalled, 1)
+
+	rd := NewRowDiffer(ctx, testKeyedSch, testKeyedSch, 8, WithStallTimeout(50*time.Millisecond))
+	rd.Start(ctx, from, to)
+
+	start := time.Now()
+	_, _, err := rd.GetDiffs(1, 10*time.Second)
+	assert.True(t, errors.Is(err, ErrDiffStalled), "unexpected error: %v", err)
+	assert.True(t, errors.Is(rd.Close(), ErrDiffStalled))
+	assert.Less(t, int64(time.Since(start)), int64(5*time.Second))
+}
+
+func TestRowDifferWithStallTimeoutSlowReads(t *testing.T) {
+	ctx := context.Background()
+	from, to, cs := stallTestMaps(t)
+	defer close(cs.unstall)
+
+	// the only difference comes after many reads, which together take longer than the timeout but each finish
+	// within it
+	cs.delay = 40 * time.Millisecond
+	rd := NewRowDiffer(ctx, testKeyedSch, testKeyedSch, 8, WithStallTimeout(100*time.Millisecond))
+	rd.Start(ctx, from, to)
+
+	start := time.Now()
+	diffs := drainDiffs(t, rd)
+	assert.Len(t, diffs, 1)
+	assert.Greater(t, int64(time.Since(start)), int64(100*time.Millisecond))
+}
+
+func TestRowDifferWithStallTimeoutNoStall(t *testing.T) {
+	ctx := context.Background()
+	vrw := types.NewMemoryValueStore()
+	from := keyedTestMap(t, vrw, 1, 1, 2, 2, 3, 3)
+	to := keyedTestMap(t, vrw, 1, 1, 2, 5, 4, 4)
+
+	// a consumer slower than the timeout is not a stall
+	rd := NewRowDiffer(ctx, testKeyedSch, testKeyedSch, 1, WithStallTimeout(20*time.Millisecond))
+	rd.Start(ctx, from, to)
+
+	n := 0
+	for {
+		time.Sleep(40 * time.Millisecond)
+		diffs, more, err := rd.GetDiffs(1, time.Second)
+		require.NoError(t, err)
+		n += len(diffs)
+		if !more {
+			break
+		}
+	}
+	assert.Equal(t, 3, n)
+	assert.NoError(t, rd.Close())
+}
+
+func mustTupleGet(t *testing.T, tup types.Tuple, i uint64) types.Value {
+	v, err := tup.Get(i)
+	require.NoError(t, err)
+	return v
+}
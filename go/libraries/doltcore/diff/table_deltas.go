@@ -489,6 +489,17 @@ func (td TableDelta) GetSchemas(ctx context.Context) (from, to schema.Schema, er
 	return from, to, nil
 }
 
+// SchemaDiff returns the columns added, dropped, renamed and with changed types between the table's schema at the
+// fromRoot and toRoot. If the table was added or dropped, all of its columns are reported as added or dropped.
+func (td TableDelta) SchemaDiff(ctx context.Context) (SchemaDelta, error) {
+	from, to, err := td.GetSchemas(ctx)
+	if err != nil {
+		return SchemaDelta{}, err
+	}
+
+	return DiffSchemaDelta(from, to), nil
+}
+
 func (td TableDelta) IsKeyless(ctx context.Context) (bool, error) {
 	f, t, err := td.GetSchemas(ctx)
 	if err != nil {
@@ -0,0 +1,192 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/dolthub/dolt/go/libraries/utils/async"
+	"github.com/dolthub/dolt/go/store/diff"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+const (
+	// teeBufferSize is the number of differences buffered for each consumer of a Tee
+	teeBufferSize = 1024
+	// teeBatchSize is the number of differences a Tee reads from its source at a time
+	teeBatchSize = 128
+	// teePollTimeout is how long a Tee waits on each call to its source's GetDiffs
+	teePollTimeout = 100 * time.Millisecond
+)
+
+// Tee returns |n| RowDiffers that each return every difference returned by |differ|, so that a diff that is read
+// once can feed several consumers. The consumers share the returned differences, which must not be modified.
+// Starting any of the returned RowDiffers starts |differ|, and later calls to Start are ignored, so that all of
+// them return the diff of the maps passed to the first call. At most teeBufferSize differences are buffered for
+// each consumer, after which |differ| isn't read until the slowest consumer catches up. Closing a returned
+// RowDiffer keeps it from holding up the others, and |differ| is closed once all of them have been closed.
+func Tee(differ RowDiffer, n int) []RowDiffer {
+	src := &teeSource{rd: differ, open: n}
+
+	rds := make([]RowDiffer, n)
+	src.branches = make([]*teeBranch, n)
+	for i := range rds {
+		src.branches[i] = &teeBranch{
+			src:   src,
+			diffs: make(chan *diff.Difference, teeBufferSize),
+			done:  make(chan struct{}),
+		}
+		rds[i] = src.branches[i]
+	}
+
+	return rds
+}
+
+// teeSource reads the differences from |rd| and sends each of them to all of its branches.
+type teeSource struct {
+	rd       RowDiffer
+	branches []*teeBranch
+
+	startOnce sync.Once
+	eg        *errgroup.Group
+	egCancel  func()
+
+	mu sync.Mutex
+	// open is the number of branches that haven't been closed
+	open int
+}
+
+func (src *teeSource) start(ctx context.Context, from, to types.Map) {
+	src.startOnce.Do(func() {
+		src.rd.Start(ctx, from, to)
+
+		var egCtx context.Context
+		src.eg, egCtx = errgroup.WithContext(ctx)
+		src.egCancel = async.GoWithCancel(egCtx, src.eg, src.pump)
+	})
+}
+
+func (src *teeSource) pump(ctx context.Context) error {
+	defer func() {
+		for _, b := range src.branches {
+			close(b.diffs)
+		}
+	}()
+
+	for {
+		diffs, more, err := src.rd.GetDiffs(teeBatchSize, teePollTimeout)
+		if err != nil {
+			return err
+		}
+
+		for _, d := range diffs {
+			for _, b := range src.branches {
+				select {
+				case b.diffs <- d:
+				case <-b.done:
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			}
+		}
+
+		if !more {
+			return nil
+		}
+
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+	}
+}
+
+// wait returns the error that stopped the source, if any.
+func (src *teeSource) wait() error {
+	return src.eg.Wait()
+}
+
+// closeBranch marks a branch closed, and closes the source if it was the last one open.
+func (src *teeSource) closeBranch() error {
+	src.mu.Lock()
+	src.open--
+	last := src.open == 0
+	src.mu.Unlock()
+
+	if !last {
+		return nil
+	}
+
+	var err error
+	if src.eg != nil {
+		src.egCancel()
+		err = src.eg.Wait()
+	}
+
+	closeErr := src.rd.Close()
+	if err == nil {
+		err = closeErr
+	}
+
+	return err
+}
+
+// teeBranch is one of the RowDiffers returned by Tee.
+type teeBranch struct {
+	src       *teeSource
+	diffs     chan *diff.Difference
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+var _ RowDiffer = &teeBranch{}
+
+// Start implements RowDiffer.
+func (b *teeBranch) Start(ctx context.Context, from, to types.Map) {
+	b.src.start(ctx, from, to)
+}
+
+// GetDiffs implements RowDiffer.
+func (b *teeBranch) GetDiffs(numDiffs int, timeout time.Duration) ([]*diff.Difference, bool, error) {
+	diffs := make([]*diff.Difference, 0, minDiffsCap)
+	timeoutChan := time.After(timeout)
+	for {
+		select {
+		case d, more := <-b.diffs:
+			if !more {
+				return diffs, false, b.src.wait()
+			}
+
+			diffs = append(diffs, d)
+			if numDiffs != 0 && numDiffs == len(diffs) {
+				return diffs, true, nil
+			}
+		case <-timeoutChan:
+			return diffs, true, nil
+		}
+	}
+}
+
+// Close implements RowDiffer.
+func (b *teeBranch) Close() (err error) {
+	b.closeOnce.Do(func() {
+		close(b.done)
+		err = b.src.closeBranch()
+	})
+	return err
+}
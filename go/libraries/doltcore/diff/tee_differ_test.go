@@ -0,0 +1,123 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/store/diff"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// readAllDiffs reads every difference from |rd|, pausing for |delay| between batches.
+func readAllDiffs(rd RowDiffer, delay time.Duration) ([]*diff.Difference, error) {
+	var all []*diff.Difference
+	for {
+		diffs, more, err := rd.GetDiffs(50, time.Second)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, diffs...)
+
+		if !more {
+			return all, nil
+		}
+		time.Sleep(delay)
+	}
+}
+
+func TestTee(t *testing.T) {
+	ctx := context.Background()
+	vrw := types.NewMemoryValueStore()
+
+	var fromVals, toVals []int
+	for i := 0; i < 2000; i++ {
+		fromVals = append(fromVals, i, i)
+		toVals = append(toVals, i, i+1)
+	}
+	from := keyedTestMap(t, vrw, fromVals...)
+	to := keyedTestMap(t, vrw, toVals...)
+
+	rd := NewRowDiffer(ctx, testKeyedSch, testKeyedSch, 64)
+	rd.Start(ctx, from, to)
+	expected := drainDiffs(t, rd)
+
+	rds := Tee(NewRowDiffer(ctx, testKeyedSch, testKeyedSch, 64), 2)
+	require.Len(t, rds, 2)
+
+	var wg sync.WaitGroup
+	results := make([][]*diff.Difference, len(rds))
+	errs := make([]error, len(rds))
+	for i, rd := range rds {
+		rd.Start(ctx, from, to)
+
+		wg.Add(1)
+		go func(i int, rd RowDiffer) {
+			defer wg.Done()
+			// the second consumer is slower, holding up the first
+			results[i], errs[i] = readAllDiffs(rd, time.Duration(i)*time.Millisecond)
+		}(i, rd)
+	}
+	wg.Wait()
+
+	for i, rd := range rds {
+		require.NoError(t, errs[i])
+		assert.NoError(t, rd.Close())
+		assertDiffsEqual(t, expected, results[i])
+	}
+}
+
+func TestTeeBoundsBuffering(t *testing.T) {
+	ctx := context.Background()
+	vrw := types.NewMemoryValueStore()
+
+	var fromVals, toVals []int
+	for i := 0; i < 3*teeBufferSize; i++ {
+		fromVals = append(fromVals, i, i)
+		toVals = append(toVals, i, i+1)
+	}
+	from := keyedTestMap(t, vrw, fromVals...)
+	to := keyedTestMap(t, vrw, toVals...)
+
+	rds := Tee(NewRowDiffer(ctx, testKeyedSch, testKeyedSch, 64), 2)
+	reader, idle := rds[0], rds[1]
+	reader.Start(ctx, from, to)
+
+	// while |idle| isn't read, |reader| gets no more than a buffer's worth of differences ahead of it
+	var read []*diff.Difference
+	for len(read) < teeBufferSize+1 {
+		diffs, more, err := reader.GetDiffs(teeBufferSize+1-len(read), time.Second)
+		require.NoError(t, err)
+		require.True(t, more)
+		read = append(read, diffs...)
+	}
+	diffs, more, err := reader.GetDiffs(0, 50*time.Millisecond)
+	require.NoError(t, err)
+	assert.True(t, more)
+	assert.Empty(t, diffs)
+
+	// once |idle| is closed it no longer holds |reader| up
+	require.NoError(t, idle.Close())
+	rest, err := readAllDiffs(reader, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 3*teeBufferSize, len(read)+len(rest))
+	assert.NoError(t, reader.Close())
+}
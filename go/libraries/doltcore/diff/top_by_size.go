@@ -0,0 +1,161 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"container/heap"
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/dolthub/dolt/go/store/diff"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// topBySizePollTimeout is how long TopDiffsBySize waits on each call to GetDiffs
+const topBySizePollTimeout = 100 * time.Millisecond
+
+var ErrInvalidTopN = errors.New("number of differences to report must be greater than zero")
+
+// SizedDifference is a difference along with the serialized sizes of its old and new values. A missing value has
+// size zero.
+type SizedDifference struct {
+	Diff    *diff.Difference
+	OldSize int
+	NewSize int
+}
+
+// Delta returns the number of bytes by which the difference grew or shrank the row.
+func (sd SizedDifference) Delta() int {
+	if sd.NewSize > sd.OldSize {
+		return sd.NewSize - sd.OldSize
+	}
+	return sd.OldSize - sd.NewSize
+}
+
+// TopDiffsBySize reads every difference from |rd|, which must already be started, and returns the |n| with the
+// largest Delta, largest first. Only |n| differences are held in memory at once. Differences with equal deltas are
+// ordered as |rd| returned them, and earlier differences are kept over later ones. |rd| is not closed.
+func TopDiffsBySize(ctx context.Context, rd RowDiffer, n int) ([]SizedDifference, error) {
+	if n <= 0 {
+		return nil, ErrInvalidTopN
+	}
+
+	h := &sizedDiffHeap{}
+	seq := 0
+	for {
+		diffs, more, err := rd.GetDiffs(n, topBySizePollTimeout)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, d := range diffs {
+			sd, err := sizeDifference(d)
+			if err != nil {
+				return nil, err
+			}
+
+			if h.Len() < n {
+				heap.Push(h, sequencedSizedDiff{sd, seq})
+			} else if sd.Delta() > (*h)[0].Delta() {
+				(*h)[0] = sequencedSizedDiff{sd, seq}
+				heap.Fix(h, 0)
+			}
+			seq++
+		}
+
+		if !more {
+			break
+		}
+
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+	}
+
+	sort.Slice(*h, func(i, j int) bool {
+		return (*h)[j].less((*h)[i])
+	})
+
+	top := make([]SizedDifference, len(*h))
+	for i, sd := range *h {
+		top[i] = sd.SizedDifference
+	}
+
+	return top, nil
+}
+
+func sizeDifference(d *diff.Difference) (SizedDifference, error) {
+	oldSize, err := encodedSize(d.OldValue)
+	if err != nil {
+		return SizedDifference{}, err
+	}
+
+	newSize, err := encodedSize(d.NewValue)
+	if err != nil {
+		return SizedDifference{}, err
+	}
+
+	return SizedDifference{d, oldSize, newSize}, nil
+}
+
+func encodedSize(v types.Value) (int, error) {
+	if v == nil {
+		return 0, nil
+	}
+
+	nbf := types.Format_Default
+	if t, ok := v.(types.Tuple); ok {
+		nbf = t.Format()
+	}
+
+	c, err := types.EncodeValue(v, nbf)
+	if err != nil {
+		return 0, err
+	}
+
+	return len(c.Data()), nil
+}
+
+// sequencedSizedDiff is a SizedDifference and its position in the diff, used to order equal deltas.
+type sequencedSizedDiff struct {
+	SizedDifference
+	seq int
+}
+
+// less orders by increasing delta, and by decreasing position for equal deltas, so that the smallest, latest
+// difference is at the root of a sizedDiffHeap.
+func (sd sequencedSizedDiff) less(other sequencedSizedDiff) bool {
+	if sd.Delta() != other.Delta() {
+		return sd.Delta() < other.Delta()
+	}
+	return sd.seq > other.seq
+}
+
+// sizedDiffHeap is a min-heap of differences by delta.
+type sizedDiffHeap []sequencedSizedDiff
+
+func (h sizedDiffHeap) Len() int            { return len(h) }
+func (h sizedDiffHeap) Less(i, j int) bool  { return h[i].less(h[j]) }
+func (h sizedDiffHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *sizedDiffHeap) Push(x interface{}) { *h = append(*h, x.(sequencedSizedDiff)) }
+
+func (h *sizedDiffHeap) Pop() interface{} {
+	old := *h
+	x := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return x
+}
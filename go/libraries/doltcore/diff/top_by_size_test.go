@@ -0,0 +1,99 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+var testStringValSch = schema.MustSchemaFromCols(mustColColl(
+	schema.NewColumn("pk", testPkTag, types.IntKind, true, schema.NotNullConstraint{}),
+	schema.NewColumn("val", testValTag, types.StringKind, false),
+))
+
+// stringValTestMap builds a row map for |testStringValSch| from |rows| of pk to val.
+func stringValTestMap(t *testing.T, vrw types.ValueReadWriter, rows map[int]string) types.Map {
+	var kvs []types.Value
+	for pk, val := range rows {
+		k, err := types.NewTuple(vrw.Format(), types.Uint(testPkTag), types.Int(pk))
+		require.NoError(t, err)
+		v, err := types.NewTuple(vrw.Format(), types.Uint(testValTag), types.String(val))
+		require.NoError(t, err)
+		kvs = append(kvs, k, v)
+	}
+
+	m, err := types.NewMap(context.Background(), vrw, kvs...)
+	require.NoError(t, err)
+	return m
+}
+
+func TestTopDiffsBySize(t *testing.T) {
+	ctx := context.Background()
+	vrw := types.NewMemoryValueStore()
+
+	from := stringValTestMap(t, vrw, map[int]string{
+		1: "a",
+		2: "unchanged",
+		3: "shrunk",
+		4: "removed",
+		6: strings.Repeat("f", 10),
+	})
+	to := stringValTestMap(t, vrw, map[int]string{
+		1: strings.Repeat("a", 100),
+		2: "unchanged",
+		3: "s",
+		5: strings.Repeat("e", 40),
+		6: strings.Repeat("g", 10),
+	})
+
+	topKeys := func(n int) ([]int64, []int) {
+		rd := NewRowDiffer(ctx, testStringValSch, testStringValSch, 8)
+		rd.Start(ctx, from, to)
+		defer rd.Close()
+
+		top, err := TopDiffsBySize(ctx, rd, n)
+		require.NoError(t, err)
+
+		var keys []int64
+		var deltas []int
+		for _, sd := range top {
+			keys = append(keys, int64(mustTupleGet(t, sd.Diff.KeyValue.(types.Tuple), 1).(types.Int)))
+			deltas = append(deltas, sd.Delta())
+		}
+		return keys, deltas
+	}
+
+	keys, deltas := topKeys(3)
+	assert.Equal(t, []int64{1, 5, 4}, keys)
+	assert.Equal(t, 99, deltas[0])
+	assert.True(t, deltas[1] > deltas[2])
+
+	// shrunk by 5 bytes, then an equal sized modification
+	keys, deltas = topKeys(10)
+	assert.Equal(t, []int64{1, 5, 4, 3, 6}, keys)
+	assert.Equal(t, []int{99, deltas[1], deltas[2], 5, 0}, deltas)
+
+	rd := NewRowDiffer(ctx, testStringValSch, testStringValSch, 8)
+	_, err := TopDiffsBySize(ctx, rd, 0)
+	assert.Equal(t, ErrInvalidTopN, err)
+}
@@ -0,0 +1,120 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+const (
+	// unifiedPatchPollTimeout is how long WriteUnifiedPatch waits on each call to GetDiffs
+	unifiedPatchPollTimeout = 100 * time.Millisecond
+	// unifiedPatchBatchSize is the number of differences WriteUnifiedPatch requests on each call to GetDiffs
+	unifiedPatchBatchSize = 1024
+
+	unifiedPatchSep  = " | "
+	unifiedPatchNull = "NULL"
+)
+
+// WriteUnifiedPatch reads every difference from |rd|, which must already be started, and writes it to |wr| as text
+// in the style of a unified diff. The first line is a header of |sch|'s column names, prefixed with a space like a
+// context line. Each removed row is written as a line prefixed with -, each added row as a line prefixed with +,
+// and each modified row as its old row prefixed with - followed by its new row prefixed with +. Values are rendered
+// by their columns' types and separated by " | ", with NULL for missing values. |sch| must be the schema of the
+// diffed table. For keyless tables each added or removed copy of a row is written as its own line. Neither |rd| nor
+// |wr| is closed.
+func WriteUnifiedPatch(ctx context.Context, rd RowDiffer, sch schema.Schema, wr io.Writer) error {
+	bw := bufio.NewWriter(wr)
+
+	if _, err := fmt.Fprintf(bw, " %s\n", strings.Join(sch.GetAllCols().GetColumnNames(), unifiedPatchSep)); err != nil {
+		return err
+	}
+
+	for {
+		diffs, more, err := rd.GetDiffs(unifiedPatchBatchSize, unifiedPatchPollTimeout)
+		if err != nil {
+			return err
+		}
+
+		for _, d := range diffs {
+			if d.OldValue != nil {
+				if err = writePatchLine(bw, '-', sch, d.KeyValue, d.OldValue); err != nil {
+					return err
+				}
+			}
+
+			if d.NewValue != nil {
+				if err = writePatchLine(bw, '+', sch, d.KeyValue, d.NewValue); err != nil {
+					return err
+				}
+			}
+		}
+
+		if !more {
+			return bw.Flush()
+		}
+
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+	}
+}
+
+// writePatchLine writes the row with key |key| and value |val| as a line of a unified patch prefixed with |prefix|.
+func writePatchLine(bw *bufio.Writer, prefix byte, sch schema.Schema, key, val types.Value) error {
+	r, err := diffSideRow(sch, key, val)
+	if err != nil {
+		return err
+	}
+
+	var fields []string
+	err = sch.GetAllCols().Iter(func(tag uint64, col schema.Column) (stop bool, err error) {
+		fields = append(fields, unifiedPatchNull)
+
+		v, ok := r.GetColVal(tag)
+		if !ok || types.IsNull(v) {
+			return false, nil
+		}
+
+		str, err := col.TypeInfo.FormatValue(v)
+		if err != nil {
+			return false, err
+		}
+
+		if str != nil {
+			fields[len(fields)-1] = *str
+		}
+		return false, nil
+	})
+
+	if err != nil {
+		return err
+	}
+
+	if err = bw.WriteByte(prefix); err != nil {
+		return err
+	}
+
+	_, err = bw.WriteString(strings.Join(fields, unifiedPatchSep) + "\n")
+	return err
+}
@@ -0,0 +1,72 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func TestWriteUnifiedPatch(t *testing.T) {
+	ctx := context.Background()
+	vrw := types.NewMemoryValueStore()
+
+	// 2 is modified, 3 is removed and 4 is added
+	from := keyedTestMap(t, vrw, 1, 1, 2, 2, 3, 3)
+	to := keyedTestMap(t, vrw, 1, 1, 2, 20, 4, 4)
+
+	rd := NewRowDiffer(ctx, testKeyedSch, testKeyedSch, 8)
+	rd.Start(ctx, from, to)
+	defer rd.Close()
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteUnifiedPatch(ctx, rd, testKeyedSch, &buf))
+
+	expected := ` pk | val
+-2 | 2
++2 | 20
+-3 | 3
++4 | 4
+`
+	assert.Equal(t, expected, buf.String())
+}
+
+func TestWriteUnifiedPatchKeyless(t *testing.T) {
+	ctx := context.Background()
+	vrw := types.NewMemoryValueStore()
+
+	// a copy of 1 is added and 2 is removed
+	from := keylessTestMap(t, vrw, 1, 1, 2, 1)
+	to := keylessTestMap(t, vrw, 1, 2)
+
+	rd := NewRowDiffer(ctx, testKeylessSch, testKeylessSch, 8)
+	rd.Start(ctx, from, to)
+	defer rd.Close()
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteUnifiedPatch(ctx, rd, testKeylessSch, &buf))
+
+	expected := ` val
++1
+-2
+`
+	assert.Equal(t, expected, buf.String())
+}
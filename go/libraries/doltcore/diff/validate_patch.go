@@ -0,0 +1,82 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// PatchConflict is a key whose value in a map isn't the one a patch expects to change.
+type PatchConflict struct {
+	// Key is the conflicting key
+	Key types.Value
+	// Expected is the value the patch expects the key to have, or nil if the patch expects it to be absent
+	Expected types.Value
+	// Actual is the value the key has in the map, or nil if it is absent
+	Actual types.Value
+}
+
+// ValidatePatch reads a patch in the format written by DiffToBlob from |r|, and checks it against |m| without
+// modifying it. It returns a conflict for each removed or modified row whose old value isn't the map's current
+// value, and for each added row whose key is already in the map. An empty result means the patch applies cleanly.
+// Values are read using |vrw|.
+func ValidatePatch(ctx context.Context, vrw types.ValueReadWriter, m types.Map, r io.Reader) ([]PatchConflict, error) {
+	itr := newBlobDiffIterator(r, vrw)
+
+	var conflicts []PatchConflict
+	for {
+		d, err := itr.Next()
+		if err == io.EOF {
+			return conflicts, nil
+		} else if err != nil {
+			return nil, err
+		}
+
+		if d.KeyValue == nil {
+			return nil, errors.New("patch difference has no key")
+		}
+
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		actual, ok, err := m.MaybeGet(ctx, d.KeyValue)
+		if err != nil {
+			return nil, err
+		} else if !ok {
+			actual = nil
+		}
+
+		var expected types.Value
+		if d.ChangeType != types.DiffChangeAdded {
+			expected = d.OldValue
+		}
+
+		if !valuesEqual(expected, actual) {
+			conflicts = append(conflicts, PatchConflict{Key: d.KeyValue, Expected: expected, Actual: actual})
+		}
+	}
+}
+
+func valuesEqual(v1, v2 types.Value) bool {
+	if v1 == nil || v2 == nil {
+		return v1 == nil && v2 == nil
+	}
+	return v1.Equals(v2)
+}
@@ -0,0 +1,67 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func TestValidatePatch(t *testing.T) {
+	ctx := context.Background()
+	vrw := types.NewMemoryValueStore()
+
+	tuple := func(tag uint64, val int) types.Value {
+		tup, err := types.NewTuple(vrw.Format(), types.Uint(tag), types.Int(val))
+		require.NoError(t, err)
+		return tup
+	}
+
+	from := keyedTestMap(t, vrw, 1, 1, 2, 2, 3, 3, 5, 5)
+	to := keyedTestMap(t, vrw, 1, 10, 3, 3, 4, 4, 5, 50)
+
+	rd := NewRowDiffer(ctx, testKeyedSch, testKeyedSch, 8)
+	rd.Start(ctx, from, to)
+	patch, err := DiffToBlob(ctx, rd, vrw)
+	require.NoError(t, err)
+	require.NoError(t, rd.Close())
+
+	conflicts, err := ValidatePatch(ctx, vrw, from, patch.Reader(ctx))
+	require.NoError(t, err)
+	assert.Empty(t, conflicts)
+
+	// 1 was modified, 2 was removed and 4 was added since the patch was taken. 5 still has the value it expects.
+	diverged := keyedTestMap(t, vrw, 1, 11, 3, 3, 4, 40, 5, 5)
+	conflicts, err = ValidatePatch(ctx, vrw, diverged, patch.Reader(ctx))
+	require.NoError(t, err)
+	require.Len(t, conflicts, 3)
+
+	assertValuesEqual(t, tuple(testPkTag, 1), conflicts[0].Key)
+	assertValuesEqual(t, tuple(testValTag, 1), conflicts[0].Expected)
+	assertValuesEqual(t, tuple(testValTag, 11), conflicts[0].Actual)
+
+	assertValuesEqual(t, tuple(testPkTag, 2), conflicts[1].Key)
+	assertValuesEqual(t, tuple(testValTag, 2), conflicts[1].Expected)
+	assert.Nil(t, conflicts[1].Actual)
+
+	assertValuesEqual(t, tuple(testPkTag, 4), conflicts[2].Key)
+	assert.Nil(t, conflicts[2].Expected)
+	assertValuesEqual(t, tuple(testValTag, 40), conflicts[2].Actual)
+}
@@ -0,0 +1,74 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doltdb
+
+import (
+	"context"
+	"io"
+
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+// CommitGraphDiff returns the commits reachable from |a| that are not reachable from |b|, and the commits
+// reachable from |b| that are not reachable from |a|, each in the order they are visited walking parents from
+// the respective commit.
+func CommitGraphDiff(ctx context.Context, ddb *DoltDB, a, b *Commit) (onlyA, onlyB []*Commit, err error) {
+	aHashes, aCommits, err := commitAncestry(ctx, ddb, a)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	bHashes, bCommits, err := commitAncestry(ctx, ddb, b)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return commitsNotIn(aHashes, aCommits, bHashes), commitsNotIn(bHashes, bCommits, aHashes), nil
+}
+
+// commitAncestry returns |cm| and all of its ancestors, along with their hashes.
+func commitAncestry(ctx context.Context, ddb *DoltDB, cm *Commit) ([]hash.Hash, []*Commit, error) {
+	var hashes []hash.Hash
+	var commits []*Commit
+
+	itr := CommitItrForRoots(ddb, cm)
+	for {
+		h, c, err := itr.Next(ctx)
+
+		if err == io.EOF {
+			return hashes, commits, nil
+		} else if err != nil {
+			return nil, nil, err
+		}
+
+		hashes = append(hashes, h)
+		commits = append(commits, c)
+	}
+}
+
+func commitsNotIn(hashes []hash.Hash, commits []*Commit, exclude []hash.Hash) []*Commit {
+	excludeSet := hash.NewHashSet(exclude...)
+
+	var result []*Commit
+	for i, h := range hashes {
+		if !excludeSet.Has(h) {
+			result = append(result, commits[i])
+		}
+	}
+
+	return result
+}
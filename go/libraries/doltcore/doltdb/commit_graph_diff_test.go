@@ -0,0 +1,85 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doltdb
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/store/hash"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func TestCommitGraphDiff(t *testing.T) {
+	ctx := context.Background()
+	ddb, err := LoadDoltDB(ctx, types.Format_Default, InMemDoltDB)
+	require.NoError(t, err)
+	require.NoError(t, ddb.WriteEmptyRepo(ctx, "Bill Billerson", "bigbillieb@fake.horse"))
+
+	initCm, err := ddb.ResolveRef(ctx, ref.NewBranchRef("master"))
+	require.NoError(t, err)
+	root, err := initCm.GetRootValue()
+	require.NoError(t, err)
+	valHash, err := ddb.WriteRootValue(ctx, root)
+	require.NoError(t, err)
+
+	commit := func(msg string, parents ...*Commit) *Commit {
+		meta, err := NewCommitMeta("Bill Billerson", "bigbillieb@fake.horse", msg)
+		require.NoError(t, err)
+		cm, err := ddb.CommitDanglingWithParentCommits(ctx, valHash, parents, meta)
+		require.NoError(t, err)
+		return cm
+	}
+
+	hashesOf := func(commits ...*Commit) []hash.Hash {
+		hashes := make([]hash.Hash, len(commits))
+		for i, cm := range commits {
+			h, err := cm.HashOf()
+			require.NoError(t, err)
+			hashes[i] = h
+		}
+		return hashes
+	}
+
+	// init <- c1 <- a1 <- merge
+	//           ^          /
+	//           +-- b1 <--+
+	//                ^
+	//                +-- b2
+	c1 := commit("c1", initCm)
+	a1 := commit("a1", c1)
+	b1 := commit("b1", c1)
+	b2 := commit("b2", b1)
+	merge := commit("merge", a1, b1)
+
+	onlyA, onlyB, err := CommitGraphDiff(ctx, ddb, merge, b2)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, hashesOf(merge, a1), hashesOf(onlyA...))
+	assert.ElementsMatch(t, hashesOf(b2), hashesOf(onlyB...))
+
+	onlyA, onlyB, err = CommitGraphDiff(ctx, ddb, a1, a1)
+	require.NoError(t, err)
+	assert.Empty(t, onlyA)
+	assert.Empty(t, onlyB)
+
+	onlyA, onlyB, err = CommitGraphDiff(ctx, ddb, c1, b2)
+	require.NoError(t, err)
+	assert.Empty(t, onlyA)
+	assert.ElementsMatch(t, hashesOf(b2, b1), hashesOf(onlyB...))
+}
@@ -35,6 +35,7 @@ var ErrTagNotFound = errors.New("tag not found")
 var ErrTableNotFound = errors.New("table not found")
 var ErrTableExists = errors.New("table already exists")
 var ErrAlreadyOnBranch = errors.New("Already on branch")
+var ErrRowDataNotMap = errors.New("table row data is not a map")
 
 var ErrNomsIO = errors.New("error reading from or writing to noms")
 
@@ -360,7 +360,11 @@ func (t *Table) GetRowData(ctx context.Context) (types.Map, error) {
 		return types.EmptyMap, err
 	}
 
-	rowMapRef := val.(types.Ref)
+	rowMapRef, ok := val.(types.Ref)
+
+	if !ok {
+		return types.EmptyMap, fmt.Errorf("%w: expected a ref to the row data, found %s", ErrRowDataNotMap, describeKind(val))
+	}
 
 	val, err = rowMapRef.TargetValue(ctx, t.vrw)
 
@@ -368,10 +372,23 @@ func (t *Table) GetRowData(ctx context.Context) (types.Map, error) {
 		return types.EmptyMap, err
 	}
 
-	rowMap := val.(types.Map)
+	rowMap, ok := val.(types.Map)
+
+	if !ok {
+		return types.EmptyMap, fmt.Errorf("%w: found a value of type %s", ErrRowDataNotMap, describeKind(val))
+	}
+
 	return rowMap, nil
 }
 
+// describeKind names the kind of |val| for error messages.
+func describeKind(val types.Value) string {
+	if val == nil {
+		return "nothing"
+	}
+	return val.Kind().String()
+}
+
 func (t *Table) ResolveConflicts(ctx context.Context, pkTuples []types.Value) (invalid, notFound []types.Value, tbl *Table, err error) {
 	removed := 0
 	_, confData, err := t.GetConflicts(ctx)
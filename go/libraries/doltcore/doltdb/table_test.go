@@ -0,0 +1,57 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doltdb
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func TestGetRowDataNotMap(t *testing.T) {
+	ctx := context.Background()
+	vrw := types.NewMemoryValueStore()
+
+	// tableWithRows returns a Table whose row data field holds |rows|
+	tableWithRows := func(rows types.Value) *Table {
+		st, err := types.NewStruct(vrw.Format(), tableStructName, types.StructData{tableRowsKey: rows})
+		require.NoError(t, err)
+		return &Table{vrw, st}
+	}
+
+	scalarRef, err := WriteValAndGetRef(ctx, vrw, types.String("not rows"))
+	require.NoError(t, err)
+
+	_, err = tableWithRows(scalarRef).GetRowData(ctx)
+	assert.True(t, errors.Is(err, ErrRowDataNotMap))
+	assert.Contains(t, err.Error(), "String")
+
+	_, err = tableWithRows(types.Float(1)).GetRowData(ctx)
+	assert.True(t, errors.Is(err, ErrRowDataNotMap))
+	assert.Contains(t, err.Error(), "Float")
+
+	m, err := types.NewMap(ctx, vrw)
+	require.NoError(t, err)
+	mapRef, err := WriteValAndGetRef(ctx, vrw, m)
+	require.NoError(t, err)
+	rows, err := tableWithRows(mapRef).GetRowData(ctx)
+	require.NoError(t, err)
+	assert.True(t, m.Equals(rows))
+}
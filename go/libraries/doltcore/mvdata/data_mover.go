@@ -30,13 +30,16 @@ import (
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/sqlutil"
 	"github.com/dolthub/dolt/go/libraries/doltcore/table"
 	"github.com/dolthub/dolt/go/libraries/doltcore/table/pipeline"
+	"github.com/dolthub/dolt/go/libraries/doltcore/table/untyped/csv"
 	"github.com/dolthub/dolt/go/libraries/utils/filesys"
 	"github.com/dolthub/dolt/go/libraries/utils/set"
 	"github.com/dolthub/dolt/go/store/types"
 )
 
 type CsvOptions struct {
-	Delim string
+	Delim             string
+	QuotedEmptyAsNull bool
+	NullValues        []string
 }
 
 type XlsxOptions struct {
@@ -54,11 +57,53 @@ type DataMoverOptions interface {
 	DestName() string
 }
 
+// CsvWriterOptions is implemented by DataMoverOptions that configure the output of csv writers.
+type CsvWriterOptions interface {
+	// TypedHeader returns whether the header line should include each column's type, as name:type
+	TypedHeader() bool
+	// NoHeader returns whether the header line should be left out, such as when resuming an earlier export
+	NoHeader() bool
+	// InvalidUTF8 returns how fields that aren't valid UTF-8 should be written
+	InvalidUTF8() csv.InvalidUTF8Policy
+	// Where returns the predicate rows must satisfy to be written, or nil to write every row
+	Where() *csv.RowPredicate
+	// SummaryLabel returns the label of a summary row to write after the data rows, or "" to write no summary row
+	SummaryLabel() string
+	// ColumnOrder returns the order columns should be written in, or nil to write them in the schema's order
+	ColumnOrder() []string
+}
+
+// csvInfoForWriting returns the CSVFileInfo for writing csv output as configured by |mvOpts|.
+func csvInfoForWriting(mvOpts DataMoverOptions) *csv.CSVFileInfo {
+	info := csv.NewCSVInfo()
+	if csvOpts, ok := mvOpts.(CsvWriterOptions); ok {
+		info.SetTypedHeader(csvOpts.TypedHeader())
+		info.SetHasHeaderLine(!csvOpts.NoHeader())
+		info.SetInvalidUTF8(csvOpts.InvalidUTF8())
+		info.SetWhere(csvOpts.Where())
+		info.SetSummaryLabel(csvOpts.SummaryLabel())
+		info.SetColumnOrder(csvOpts.ColumnOrder()...)
+	}
+	return info
+}
+
 type DataMoverCloser interface {
 	table.TableWriteCloser
 	Flush(context.Context) (*doltdb.RootValue, error)
 }
 
+// FlushedByteCounter is implemented by writers that can report how much output reached their destination, so
+// that a failed move can tell partial output from no output.
+type FlushedByteCounter interface {
+	BytesFlushed() int64
+}
+
+// WriteAborter is implemented by writers that can discard their output, so that a failed move leaves no partial
+// output behind.
+type WriteAborter interface {
+	Abort(ctx context.Context) error
+}
+
 type DataMover struct {
 	Rd         table.TableReadCloser
 	Transforms *pipeline.TransformCollection
@@ -100,6 +145,11 @@ type GCTableWriteCloser interface {
 func (imp *DataMover) Move(ctx context.Context) (badRowCount int64, err error) {
 	defer imp.Rd.Close(ctx)
 	defer func() {
+		if wa, ok := imp.Wr.(WriteAborter); ok && err != nil {
+			_ = wa.Abort(ctx)
+			return
+		}
+
 		closeErr := imp.Wr.Close(ctx)
 		if err == nil {
 			err = closeErr
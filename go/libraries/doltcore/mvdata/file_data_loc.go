@@ -85,6 +85,8 @@ func (dl FileDataLocation) NewReader(ctx context.Context, root *doltdb.RootValue
 	switch dl.Format {
 	case CsvFile:
 		delim := ","
+		quotedEmptyAsNull := false
+		var nullValues []string
 
 		if opts != nil {
 			csvOpts, _ := opts.(CsvOptions)
@@ -92,9 +94,11 @@ func (dl FileDataLocation) NewReader(ctx context.Context, root *doltdb.RootValue
 			if len(csvOpts.Delim) != 0 {
 				delim = csvOpts.Delim
 			}
+			quotedEmptyAsNull = csvOpts.QuotedEmptyAsNull
+			nullValues = csvOpts.NullValues
 		}
 
-		rd, err := csv.OpenCSVReader(root.VRW().Format(), dl.Path, fs, csv.NewCSVInfo().SetDelim(delim))
+		rd, err := csv.OpenCSVReader(root.VRW().Format(), dl.Path, fs, csv.NewCSVInfo().SetDelim(delim).SetQuotedEmptyAsNull(quotedEmptyAsNull).SetNullValues(nullValues...))
 
 		return rd, false, err
 
@@ -148,9 +152,9 @@ func (dl FileDataLocation) NewReader(ctx context.Context, root *doltdb.RootValue
 func (dl FileDataLocation) NewCreatingWriter(ctx context.Context, mvOpts DataMoverOptions, dEnv *env.DoltEnv, root *doltdb.RootValue, _ bool, outSch schema.Schema, _ noms.StatsCB, _ bool) (table.TableWriteCloser, error) {
 	switch dl.Format {
 	case CsvFile:
-		return csv.OpenCSVWriter(dl.Path, dEnv.FS, outSch, csv.NewCSVInfo())
+		return csv.OpenAtomicCSVWriter(dl.Path, dEnv.FS, outSch, csvInfoForWriting(mvOpts))
 	case PsvFile:
-		return csv.OpenCSVWriter(dl.Path, dEnv.FS, outSch, csv.NewCSVInfo().SetDelim("|"))
+		return csv.OpenAtomicCSVWriter(dl.Path, dEnv.FS, outSch, csvInfoForWriting(mvOpts).SetDelim("|"))
 	case XlsxFile:
 		panic("writing to xlsx files is not supported yet")
 	case JsonFile:
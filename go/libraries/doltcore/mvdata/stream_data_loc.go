@@ -53,6 +53,8 @@ func (dl StreamDataLocation) NewReader(ctx context.Context, root *doltdb.RootVal
 	switch dl.Format {
 	case CsvFile:
 		delim := ","
+		quotedEmptyAsNull := false
+		var nullValues []string
 
 		if opts != nil {
 			csvOpts, _ := opts.(CsvOptions)
@@ -60,9 +62,11 @@ func (dl StreamDataLocation) NewReader(ctx context.Context, root *doltdb.RootVal
 			if len(csvOpts.Delim) != 0 {
 				delim = csvOpts.Delim
 			}
+			quotedEmptyAsNull = csvOpts.QuotedEmptyAsNull
+			nullValues = csvOpts.NullValues
 		}
 
-		rd, err := csv.NewCSVReader(root.VRW().Format(), ioutil.NopCloser(dl.Reader), csv.NewCSVInfo().SetDelim(delim))
+		rd, err := csv.NewCSVReader(root.VRW().Format(), ioutil.NopCloser(dl.Reader), csv.NewCSVInfo().SetDelim(delim).SetQuotedEmptyAsNull(quotedEmptyAsNull).SetNullValues(nullValues...))
 
 		return rd, false, err
 
@@ -76,13 +80,13 @@ func (dl StreamDataLocation) NewReader(ctx context.Context, root *doltdb.RootVal
 
 // NewCreatingWriter will create a TableWriteCloser for a DataLocation that will create a new table, or overwrite
 // an existing table.
-func (dl StreamDataLocation) NewCreatingWriter(_ context.Context, _ DataMoverOptions, _ *env.DoltEnv, _ *doltdb.RootValue, _ bool, outSch schema.Schema, _ noms.StatsCB, _ bool) (table.TableWriteCloser, error) {
+func (dl StreamDataLocation) NewCreatingWriter(_ context.Context, mvOpts DataMoverOptions, _ *env.DoltEnv, _ *doltdb.RootValue, _ bool, outSch schema.Schema, _ noms.StatsCB, _ bool) (table.TableWriteCloser, error) {
 	switch dl.Format {
 	case CsvFile:
-		return csv.NewCSVWriter(iohelp.NopWrCloser(dl.Writer), outSch, csv.NewCSVInfo())
+		return csv.NewCSVWriter(iohelp.NopWrCloser(dl.Writer), outSch, csvInfoForWriting(mvOpts))
 
 	case PsvFile:
-		return csv.NewCSVWriter(iohelp.NopWrCloser(dl.Writer), outSch, csv.NewCSVInfo().SetDelim("|"))
+		return csv.NewCSVWriter(iohelp.NopWrCloser(dl.Writer), outSch, csvInfoForWriting(mvOpts).SetDelim("|"))
 	}
 
 	return nil, errors.New(string(dl.Format) + "is an unsupported format to write to stdout")
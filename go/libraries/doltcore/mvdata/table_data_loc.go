@@ -46,6 +46,12 @@ type TableDataLocation struct {
 	Name string
 }
 
+// TableReadOptions can be passed to TableDataLocation.NewReader to configure how rows are read.
+type TableReadOptions struct {
+	// Offset is the number of rows to skip before the first row read, such as when resuming an earlier export
+	Offset uint64
+}
+
 // String returns a string representation of the data location.
 func (dl TableDataLocation) String() string {
 	return DoltDB.ReadableStr() + ":" + dl.Name
@@ -57,7 +63,7 @@ func (dl TableDataLocation) Exists(ctx context.Context, root *doltdb.RootValue,
 }
 
 // NewReader creates a TableReadCloser for the DataLocation
-func (dl TableDataLocation) NewReader(ctx context.Context, root *doltdb.RootValue, _ filesys.ReadableFS, _ interface{}) (rdCl table.TableReadCloser, sorted bool, err error) {
+func (dl TableDataLocation) NewReader(ctx context.Context, root *doltdb.RootValue, _ filesys.ReadableFS, opts interface{}) (rdCl table.TableReadCloser, sorted bool, err error) {
 	tbl, ok, err := root.GetTable(ctx, dl.Name)
 	if err != nil {
 		return nil, false, err
@@ -66,6 +72,15 @@ func (dl TableDataLocation) NewReader(ctx context.Context, root *doltdb.RootValu
 		return nil, false, doltdb.ErrTableNotFound
 	}
 
+	if tblOpts, ok := opts.(TableReadOptions); ok && tblOpts.Offset > 0 {
+		rd, err := table.NewDoltTableReaderAt(ctx, tbl, tblOpts.Offset)
+		if err != nil {
+			return nil, false, err
+		}
+
+		return rd, true, nil
+	}
+
 	rd, err := table.NewDoltTableReader(ctx, tbl)
 	if err != nil {
 		return nil, false, err
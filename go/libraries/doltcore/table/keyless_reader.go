@@ -104,6 +104,41 @@ func newKeylessTableReader(ctx context.Context, tbl *doltdb.Table, sch schema.Sc
 	}, nil
 }
 
+func newKeylessTableReaderAt(ctx context.Context, tbl *doltdb.Table, sch schema.Schema, offset uint64) (*keylessTableReader, error) {
+	rdr, err := newKeylessTableReader(ctx, tbl, sch, false)
+	if err != nil {
+		return nil, err
+	}
+
+	for offset > 0 {
+		key, val, err := rdr.iter.Next(ctx)
+		if err != nil {
+			return nil, err
+		} else if key == nil {
+			break
+		}
+
+		card, err := val.(types.Tuple).Get(row.KeylessCardinalityValIdx)
+		if err != nil {
+			return nil, err
+		}
+
+		if uint64(card.(types.Uint)) <= offset {
+			offset -= uint64(card.(types.Uint))
+			continue
+		}
+
+		rdr.row, rdr.remainingCopies, err = row.KeylessRowsFromTuples(key.(types.Tuple), val.(types.Tuple))
+		if err != nil {
+			return nil, err
+		}
+		rdr.remainingCopies -= offset
+		offset = 0
+	}
+
+	return rdr, nil
+}
+
 // TODO: this is broken! (for partition boundaries that hit rows with cardinality > 1)
 func newKeylessTableReaderForPartition(ctx context.Context, tbl *doltdb.Table, sch schema.Schema, start, end uint64) (SqlTableReader, error) {
 	rows, err := tbl.GetRowData(ctx)
@@ -110,6 +110,23 @@ func newPkTableReaderFrom(ctx context.Context, tbl *doltdb.Table, sch schema.Sch
 	}, nil
 }
 
+func newPkTableReaderAt(ctx context.Context, tbl *doltdb.Table, sch schema.Schema, offset uint64) (pkTableReader, error) {
+	rows, err := tbl.GetRowData(ctx)
+	if err != nil {
+		return pkTableReader{}, err
+	}
+
+	iter, err := rows.BufferedIteratorAt(ctx, offset)
+	if err != nil {
+		return pkTableReader{}, err
+	}
+
+	return pkTableReader{
+		iter: iter,
+		sch:  sch,
+	}, nil
+}
+
 type partitionTableReader struct {
 	SqlTableReader
 	remaining uint64
@@ -84,6 +84,22 @@ func NewDoltTableReader(ctx context.Context, tbl *doltdb.Table) (TableReadCloser
 	return newPkTableReader(ctx, tbl, sch, false)
 }
 
+// NewDoltTableReaderAt creates a TableReadCloser that reads the rows of |tbl| beginning with the row at index
+// |offset|, skipping the rows before it. For tables with primary keys, the reader seeks straight to |offset|.
+// For keyless tables, where each map entry may hold several copies of a row, the entries before |offset| are read
+// to count their copies.
+func NewDoltTableReaderAt(ctx context.Context, tbl *doltdb.Table, offset uint64) (TableReadCloser, error) {
+	sch, err := tbl.GetSchema(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if schema.IsKeyless(sch) {
+		return newKeylessTableReaderAt(ctx, tbl, sch, offset)
+	}
+	return newPkTableReaderAt(ctx, tbl, sch, offset)
+}
+
 // NewBufferedTableReader creates a buffered SqlTableReader from |tbl| starting from the first record.
 func NewBufferedTableReader(ctx context.Context, tbl *doltdb.Table) (SqlTableReader, error) {
 	sch, err := tbl.GetSchema(ctx)
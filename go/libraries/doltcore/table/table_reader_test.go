@@ -0,0 +1,116 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package table_test
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	dtu "github.com/dolthub/dolt/go/libraries/doltcore/dtestutils"
+	"github.com/dolthub/dolt/go/libraries/doltcore/row"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema/encoding"
+	"github.com/dolthub/dolt/go/libraries/doltcore/table"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func TestDoltTableReaderAt(t *testing.T) {
+	dEnv := dtu.CreateTestEnv()
+	ctx := context.Background()
+	vrw := dEnv.DoltDB.ValueReadWriter()
+	empty := dtu.MustMap(t, vrw)
+
+	readAll := func(rd table.TableReadCloser) []row.Row {
+		var rows []row.Row
+		for {
+			r, err := rd.ReadRow(ctx)
+			if err == io.EOF {
+				break
+			}
+			require.NoError(t, err)
+			rows = append(rows, r)
+		}
+		require.NoError(t, rd.Close(ctx))
+		return rows
+	}
+
+	// checkOffsets asserts that reading from each offset continues exactly where reading the rows before it stopped
+	checkOffsets := func(t *testing.T, tbl *doltdb.Table, sch schema.Schema, numRows int) {
+		rd, err := table.NewDoltTableReader(ctx, tbl)
+		require.NoError(t, err)
+		all := readAll(rd)
+		require.Len(t, all, numRows)
+
+		for offset := 0; offset <= numRows+1; offset++ {
+			rd, err := table.NewDoltTableReaderAt(ctx, tbl, uint64(offset))
+			require.NoError(t, err)
+			rest := readAll(rd)
+
+			if offset >= numRows {
+				assert.Empty(t, rest)
+				continue
+			}
+
+			require.Len(t, rest, numRows-offset)
+			for i, r := range rest {
+				assert.True(t, row.AreEqual(all[offset+i], r, sch), "row %d from offset %d", i, offset)
+			}
+		}
+	}
+
+	t.Run("primary key", func(t *testing.T) {
+		sch := dtu.CreateSchema(
+			schema.NewColumn("pk", 0, types.IntKind, true, schema.NotNullConstraint{}),
+			schema.NewColumn("c1", 1, types.IntKind, false))
+		schVal, err := encoding.MarshalSchemaAsNomsValue(ctx, vrw, sch)
+		require.NoError(t, err)
+
+		var colVals []row.TaggedValues
+		for i := 0; i < 10; i++ {
+			colVals = append(colVals, row.TaggedValues{0: types.Int(i), 1: types.Int(i * 10)})
+		}
+		rowMap := dtu.MustRowData(t, ctx, vrw, sch, colVals)
+
+		tbl, err := doltdb.NewTable(ctx, vrw, schVal, *rowMap, empty)
+		require.NoError(t, err)
+		checkOffsets(t, tbl, sch, 10)
+	})
+
+	t.Run("keyless", func(t *testing.T) {
+		sch := dtu.CreateSchema(
+			schema.NewColumn("c0", 0, types.IntKind, false),
+			schema.NewColumn("c1", 1, types.IntKind, false))
+		schVal, err := encoding.MarshalSchemaAsNomsValue(ctx, vrw, sch)
+		require.NoError(t, err)
+
+		var tups []types.Value
+		for i, card := range []uint64{1, 2, 3, 1} {
+			k, v, err := encodeKeylessSqlRows(vrw, sch, sql.NewRow(int64(i), int64(i)), card)
+			require.NoError(t, err)
+			tups = append(tups, k, v)
+		}
+		rowMap := dtu.MustMap(t, vrw, tups...)
+
+		tbl, err := doltdb.NewTable(ctx, vrw, schVal, rowMap, empty)
+		require.NoError(t, err)
+		checkOffsets(t, tbl, sch, 7)
+	})
+}
@@ -0,0 +1,181 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package csv
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/row"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/table"
+)
+
+// ErrInvalidBatchSize is returned when a batch size is not positive.
+var ErrInvalidBatchSize = errors.New("batch size must be positive")
+
+// BatchSink receives batches of rows, each row a slice of the fields that would be written to a csv file. NULL
+// fields are empty strings. A sink must not retain |rows| after it returns.
+type BatchSink func(rows [][]string) error
+
+// BatchWriter implements TableWriteCloser. Rather than writing rows to a file, it formats them as a CSVWriter with
+// the same schema and CSVFileInfo would, and passes them to a BatchSink in batches. No header row is passed to the
+// sink, and the delimiter and line terminator are not used.
+type BatchWriter struct {
+	sch       schema.Schema
+	info      *CSVFileInfo
+	batchSize int
+	sink      BatchSink
+	batch     [][]string
+	closed    bool
+
+	// summary, if non-nil, accumulates the summary row passed to the sink on Close
+	summary *rowSummary
+
+	// order, if non-nil, is the order fields are passed to the sink in to follow the CSVFileInfo's ColumnOrder
+	order []int
+}
+
+var _ table.TableWriteCloser = &BatchWriter{}
+
+// NewBatchWriter returns a BatchWriter that passes rows to |sink| |batchSize| rows at a time.
+func NewBatchWriter(outSch schema.Schema, info *CSVFileInfo, batchSize int, sink BatchSink) (*BatchWriter, error) {
+	if batchSize <= 0 {
+		return nil, ErrInvalidBatchSize
+	}
+
+	bw := &BatchWriter{
+		sch:       outSch,
+		info:      info,
+		batchSize: batchSize,
+		sink:      sink,
+		batch:     make([][]string, 0, batchSize),
+	}
+
+	if info.SummaryLabel != "" {
+		bw.summary = newRowSummary(outSch, info)
+	}
+
+	names, _, err := fieldNames(outSch, info)
+
+	if err != nil {
+		return nil, err
+	}
+
+	bw.order = newFieldOrder(names, info.ColumnOrder)
+	return bw, nil
+}
+
+// GetSchema gets the schema of the rows that this writer writes
+func (bw *BatchWriter) GetSchema() schema.Schema {
+	return bw.sch
+}
+
+// WriteRow adds a row to the current batch, passing the batch to the sink once it is full. Rows that don't satisfy
+// the CSVFileInfo's Where predicate are skipped.
+func (bw *BatchWriter) WriteRow(ctx context.Context, r row.Row) error {
+	if bw.closed {
+		return errors.New("Already closed.")
+	}
+
+	if ok, err := rowMatches(ctx, bw.sch, bw.info, r); err != nil || !ok {
+		return err
+	}
+
+	fields, err := formatRow(ctx, bw.sch, bw.info, bw.order, r)
+
+	if err != nil {
+		return err
+	}
+
+	if bw.summary != nil {
+		bw.summary.add(r)
+	}
+
+	return bw.add(fields)
+}
+
+// add adds the row with |fields| to the current batch, passing the batch to the sink once it is full.
+func (bw *BatchWriter) add(fields []*string) error {
+	strs := make([]string, len(fields))
+	for i, f := range fields {
+		if f != nil {
+			strs[i] = *f
+		}
+	}
+
+	bw.batch = append(bw.batch, strs)
+	if len(bw.batch) < bw.batchSize {
+		return nil
+	}
+
+	return bw.flush()
+}
+
+// Close adds the summary row, if the CSVFileInfo has a SummaryLabel, and passes the final partial batch, if there
+// is one, to the sink
+func (bw *BatchWriter) Close(ctx context.Context) error {
+	if bw.closed {
+		return errors.New("Already closed.")
+	}
+
+	bw.closed = true
+	if bw.summary != nil {
+		if err := bw.add(bw.summary.record(bw.order)); err != nil {
+			return err
+		}
+	}
+
+	if len(bw.batch) == 0 {
+		return nil
+	}
+
+	return bw.flush()
+}
+
+func (bw *BatchWriter) flush() error {
+	err := bw.sink(bw.batch)
+	bw.batch = bw.batch[:0]
+	return err
+}
+
+// WriteBatched reads every row from |rd| and passes them to |sink| in batches of |batchSize|, formatted according
+// to |info|, with a final partial batch if the number of rows isn't a multiple of |batchSize|.
+func WriteBatched(ctx context.Context, rd table.TableReader, info *CSVFileInfo, batchSize int, sink BatchSink) error {
+	bw, err := NewBatchWriter(rd.GetSchema(), info, batchSize, sink)
+
+	if err != nil {
+		return err
+	}
+
+	for {
+		r, err := rd.ReadRow(ctx)
+
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return err
+		}
+
+		err = bw.WriteRow(ctx, r)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return bw.Close(ctx)
+}
@@ -0,0 +1,78 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package csv
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/table"
+)
+
+func TestWriteBatched(t *testing.T) {
+	ctx := context.Background()
+	rows := getSampleRows()
+
+	expected := [][]string{
+		{"Bill Billerson", "32", "Senior Dufus"},
+		{"Rob Robertson", "25", "Dufus"},
+		{"John Johnson", "21", ""},
+		{"Andy Anderson", "27", ""},
+	}
+
+	tests := []struct {
+		batchSize  int
+		expBatches []int
+	}{
+		{1, []int{1, 1, 1, 1}},
+		{2, []int{2, 2}},
+		{3, []int{3, 1}},
+		{10, []int{4}},
+	}
+
+	for _, test := range tests {
+		var batchSizes []int
+		var written [][]string
+		sink := func(batch [][]string) error {
+			batchSizes = append(batchSizes, len(batch))
+			written = append(written, batch...)
+			return nil
+		}
+
+		rd := table.NewInMemTableReader(table.NewInMemTableWithData(outSch, rows))
+		err := WriteBatched(ctx, rd, NewCSVInfo(), test.batchSize, sink)
+		require.NoError(t, err)
+		assert.Equal(t, test.expBatches, batchSizes)
+		assert.Equal(t, expected, written)
+	}
+
+	rd := table.NewInMemTableReader(table.NewInMemTableWithData(outSch, rows))
+	err := WriteBatched(ctx, rd, NewCSVInfo(), 0, nil)
+	assert.Equal(t, ErrInvalidBatchSize, err)
+
+	errSink := errors.New("sink failed")
+	calls := 0
+	rd = table.NewInMemTableReader(table.NewInMemTableWithData(outSch, rows))
+	err = WriteBatched(ctx, rd, NewCSVInfo(), 2, func(batch [][]string) error {
+		calls++
+		return errSink
+	})
+	assert.Equal(t, errSink, err)
+	assert.Equal(t, 1, calls)
+}
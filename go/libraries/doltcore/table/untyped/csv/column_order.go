@@ -0,0 +1,109 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package csv
+
+import (
+	"fmt"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// fieldNames returns the name and the kind of the values of each field written for rows of |sch|, in the order the
+// schema's columns and the virtual columns are written when there is no ColumnOrder.
+func fieldNames(sch schema.Schema, info *CSVFileInfo) ([]string, []types.NomsKind, error) {
+	var names []string
+	var kinds []types.NomsKind
+	err := sch.GetAllCols().Iter(func(tag uint64, col schema.Column) (stop bool, err error) {
+		if n, ok := info.ExpandedLists[col.Name]; ok {
+			for i := 0; i < n; i++ {
+				names = append(names, fmt.Sprintf("%s_%d", col.Name, i))
+				kinds = append(kinds, types.UnknownKind)
+			}
+			return false, nil
+		}
+
+		names = append(names, col.Name)
+		kinds = append(kinds, col.Kind)
+		return false, nil
+	})
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, vc := range info.VirtualColumns {
+		names = append(names, vc.Name)
+		kinds = append(kinds, types.StringKind)
+	}
+
+	return names, kinds, nil
+}
+
+// newFieldOrder returns the order in which the fields named |names| are written to follow the column order
+// |reference|. Each element is the index in |names| of the field written in that position, or -1 for a field of
+// |reference| that isn't in |names|, which is written empty. Fields that aren't in |reference| are written after
+// those that are, in their original order. It returns nil if |reference| is empty, as the fields are then written
+// in their original order.
+func newFieldOrder(names, reference []string) []int {
+	if len(reference) == 0 {
+		return nil
+	}
+
+	idx := make(map[string]int, len(names))
+	for i, name := range names {
+		if _, ok := idx[name]; !ok {
+			idx[name] = i
+		}
+	}
+
+	order := make([]int, 0, len(reference)+len(names))
+	placed := make([]bool, len(names))
+	for _, name := range reference {
+		i, ok := idx[name]
+		if !ok || placed[i] {
+			order = append(order, -1)
+			continue
+		}
+
+		order = append(order, i)
+		placed[i] = true
+	}
+
+	for i := range names {
+		if !placed[i] {
+			order = append(order, i)
+		}
+	}
+
+	return order
+}
+
+// reorderFields returns |fields| in the order given by |order|, with nil for fields missing from |fields|. It
+// returns |fields| unchanged if |order| is nil.
+func reorderFields(order []int, fields []*string) []*string {
+	if order == nil {
+		return fields
+	}
+
+	reordered := make([]*string, len(order))
+	for i, j := range order {
+		if j >= 0 {
+			reordered[i] = fields[j]
+		}
+	}
+
+	return reordered
+}
@@ -0,0 +1,91 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package csv
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/row"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/utils/filesys"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func TestWriterColumnOrder(t *testing.T) {
+	const root = "/"
+
+	// the second revision adds a title column before the age column
+	rev1Cols, err := schema.NewColCollection(
+		schema.NewColumn("name", 0, types.StringKind, true),
+		schema.NewColumn("age", 1, types.UintKind, false),
+	)
+	require.NoError(t, err)
+	rev1 := schema.MustSchemaFromCols(rev1Cols)
+
+	rev2Cols, err := schema.NewColCollection(
+		schema.NewColumn("name", 0, types.StringKind, true),
+		schema.NewColumn("title", 2, types.StringKind, false),
+		schema.NewColumn("age", 1, types.UintKind, false),
+	)
+	require.NoError(t, err)
+	rev2 := schema.MustSchemaFromCols(rev2Cols)
+
+	bill := row.TaggedValues{0: types.String("Bill"), 1: types.Uint(32), 2: types.String("Dufus")}
+	export := func(sch schema.Schema, info *CSVFileInfo) string {
+		fs := filesys.NewInMemFS(nil, nil, root)
+		csvWr, err := OpenCSVWriter("/file.csv", fs, sch, info)
+		require.NoError(t, err)
+
+		vals := make(row.TaggedValues)
+		for _, tag := range sch.GetAllCols().Tags {
+			vals[tag] = bill[tag]
+		}
+		writeToCSV(csvWr, []row.Row{mustRow(row.New(types.Format_7_18, sch, vals))}, t)
+
+		results, err := fs.ReadFile("/file.csv")
+		require.NoError(t, err)
+		return string(results)
+	}
+
+	assert.Equal(t, "name,age\nBill,32\n", export(rev1, NewCSVInfo()))
+	assert.Equal(t, "name,title,age\nBill,Dufus,32\n", export(rev2, NewCSVInfo()))
+
+	// the columns of the first revision's export keep their positions, and the new column is appended
+	assert.Equal(t, "name,age,title\nBill,32,Dufus\n", export(rev2, NewCSVInfo().SetColumnOrder("name", "age")))
+	assert.Equal(t, "name,age\nBill,32\n", export(rev1, NewCSVInfo().SetColumnOrder("name", "age")))
+
+	// columns of the reference order that aren't written are left empty
+	info := NewCSVInfo().SetColumnOrder("name", "dept", "age")
+	assert.Equal(t, "name,dept,age,title\nBill,,32,Dufus\n", export(rev2, info))
+
+	info = NewCSVInfo().SetColumnOrder("name", "dept", "age").SetTypedHeader(true)
+	assert.Equal(t, "name:string,dept:string,age:uint,title:string\nBill,,32,Dufus\n", export(rev2, info))
+
+	info = NewCSVInfo().SetColumnOrder("age", "name").SetSummaryLabel("TOTAL")
+	assert.Equal(t, "age,name,title\n32,Bill,Dufus\nTOTAL,1,1\n", export(rev2, info))
+}
+
+func TestNewFieldOrder(t *testing.T) {
+	names := []string{"a", "b", "c"}
+	assert.Nil(t, newFieldOrder(names, nil))
+	assert.Equal(t, []int{0, 1, 2}, newFieldOrder(names, names))
+	assert.Equal(t, []int{2, 0, 1}, newFieldOrder(names, []string{"c", "a"}))
+	assert.Equal(t, []int{1, -1, 0, 2}, newFieldOrder(names, []string{"b", "x", "a"}))
+	// a repeated column is only written once
+	assert.Equal(t, []int{0, -1, 1, 2}, newFieldOrder(names, []string{"a", "a"}))
+}
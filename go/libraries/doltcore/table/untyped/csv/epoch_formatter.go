@@ -0,0 +1,63 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package csv
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// EpochFormatter returns a ValueFormatter that renders numbers of seconds since the unix epoch, and timestamps, as
+// times in |loc| using |layout|. Daylight saving time is applied according to |loc| for each value.
+func EpochFormatter(layout string, loc *time.Location) ValueFormatter {
+	return func(val types.Value) (string, error) {
+		t, err := epochTime(val)
+		if err != nil {
+			return "", err
+		}
+
+		return t.In(loc).Format(layout), nil
+	}
+}
+
+// EpochFormatterInZone returns an EpochFormatter for the location named |zone|, such as "America/New_York", as
+// loaded by time.LoadLocation.
+func EpochFormatterInZone(layout, zone string) (ValueFormatter, error) {
+	loc, err := time.LoadLocation(zone)
+	if err != nil {
+		return nil, err
+	}
+
+	return EpochFormatter(layout, loc), nil
+}
+
+func epochTime(val types.Value) (time.Time, error) {
+	switch v := val.(type) {
+	case types.Int:
+		return time.Unix(int64(v), 0), nil
+	case types.Uint:
+		return time.Unix(int64(v), 0), nil
+	case types.Float:
+		sec, frac := math.Modf(float64(v))
+		return time.Unix(int64(sec), int64(frac*float64(time.Second))), nil
+	case types.Timestamp:
+		return time.Time(v), nil
+	default:
+		return time.Time{}, fmt.Errorf("cannot format value of kind %s as a time", val.Kind().String())
+	}
+}
@@ -0,0 +1,55 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package csv
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func TestEpochFormatterInZone(t *testing.T) {
+	f, err := EpochFormatterInZone(time.RFC3339, "America/New_York")
+	require.NoError(t, err)
+
+	// daylight saving time starts in New York at 2020-03-08 07:00:00 UTC
+	dstStart := time.Date(2020, 3, 8, 7, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		val      types.Value
+		expected string
+	}{
+		{types.Int(dstStart.Unix() - 1), "2020-03-08T01:59:59-05:00"},
+		{types.Uint(dstStart.Unix()), "2020-03-08T03:00:00-04:00"},
+		{types.Float(dstStart.Unix() + 3600), "2020-03-08T04:00:00-04:00"},
+		{types.Timestamp(dstStart.Add(-time.Hour)), "2020-03-08T01:00:00-05:00"},
+	}
+
+	for _, test := range tests {
+		str, err := f(test.val)
+		require.NoError(t, err)
+		assert.Equal(t, test.expected, str)
+	}
+
+	_, err = f(types.String("noon"))
+	assert.Error(t, err)
+
+	_, err = EpochFormatterInZone(time.RFC3339, "Not/A_Zone")
+	assert.Error(t, err)
+}
@@ -14,6 +14,43 @@
 
 package csv
 
+import (
+	"context"
+	"time"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/row"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// VirtualColumn is a column whose values are computed from each row written rather than read from it.
+type VirtualColumn struct {
+	// Name is the column's header
+	Name string
+	// Value computes the column's value for a row
+	Value func(ctx context.Context, r row.Row) (string, error)
+}
+
+// ValueFormatter renders a non-NULL column value as the string written to a csv file.
+type ValueFormatter func(val types.Value) (string, error)
+
+// RowTransform rewrites the fields of a row after they have been formatted and before they are written. An error
+// aborts the write.
+type RowTransform func(fields []string) ([]string, error)
+
+// InvalidUTF8Policy says how fields that aren't valid UTF-8 are written.
+type InvalidUTF8Policy int
+
+const (
+	// InvalidUTF8Passthrough writes fields' bytes unchanged
+	InvalidUTF8Passthrough InvalidUTF8Policy = iota
+	// InvalidUTF8Error fails the write with an error wrapping ErrInvalidUTF8
+	InvalidUTF8Error
+	// InvalidUTF8Replace writes each invalid byte as the replacement character U+FFFD
+	InvalidUTF8Replace
+	// InvalidUTF8EscapeHex writes each invalid byte as a \xNN hex escape
+	InvalidUTF8EscapeHex
+)
+
 // CSVFileInfo describes a csv file
 type CSVFileInfo struct {
 	// Delim says which character is used as a field delimiter
@@ -24,11 +61,47 @@ type CSVFileInfo struct {
 	Columns []string
 	// EscapeQuotes says whether quotes should be escaped when parsing the csv
 	EscapeQuotes bool
+	// ExpandedLists maps the names of list valued columns to a fixed length. When writing, each such column is
+	// written as that many positional columns named name_0, name_1, ...
+	ExpandedLists map[string]int
+	// QuotedEmptyAsNull says whether quoted empty fields should be read as NULL. By default only unquoted empty
+	// fields are NULL and quoted empty fields are empty strings
+	QuotedEmptyAsNull bool
+	// NullValues are strings that represent NULL when they appear as unquoted fields, e.g. \N or NULL
+	NullValues []string
+	// VirtualColumns are written after the schema's columns when writing
+	VirtualColumns []VirtualColumn
+	// TypedHeader says whether the header line written should include each column's type, as name:type
+	TypedHeader bool
+	// ColumnFormatters maps column names to the formatters used to write their values in place of the default
+	// formatting. NULL values are still written as NULL, and columns in ExpandedLists are not formatted
+	ColumnFormatters map[string]ValueFormatter
+	// InvalidUTF8 says how fields that aren't valid UTF-8 are written
+	InvalidUTF8 InvalidUTF8Policy
+	// Where, if set, limits the rows written to those that satisfy it
+	Where *RowPredicate
+	// SummaryLabel, if not empty, says to write a summary row after the rows written, with integer and float
+	// columns summed and every other column's non-NULL values counted. The first field of the summary row is
+	// SummaryLabel, in place of the first column's aggregate
+	SummaryLabel string
+	// FlushEveryRows, if positive, says to flush the rows written to the underlying writer every FlushEveryRows rows
+	// rather than only once the write buffer is full, so that readers of the output see rows sooner
+	FlushEveryRows int
+	// FlushInterval, if positive, says to flush rows written to the underlying writer no later than FlushInterval
+	// after they are written
+	FlushInterval time.Duration
+	// Transforms are applied in order to the fields of each row written, after every other kind of formatting. NULL
+	// fields are passed to them as empty strings, and are still written as NULL if they are left empty
+	Transforms []RowTransform
+	// ColumnOrder, if set, is the order columns are written in, such as the header of an earlier export, so that
+	// columns keep their positions as a schema evolves. Columns that aren't in ColumnOrder are written after those
+	// that are, and columns in ColumnOrder that aren't being written are left empty
+	ColumnOrder []string
 }
 
 // NewCSVInfo creates a new CSVInfo struct with default values
 func NewCSVInfo() *CSVFileInfo {
-	return &CSVFileInfo{",", true, nil, true}
+	return &CSVFileInfo{Delim: ",", HasHeaderLine: true, Columns: nil, EscapeQuotes: true}
 }
 
 // SetDelim sets the Delim member and returns the CSVFileInfo
@@ -54,3 +127,81 @@ func (info *CSVFileInfo) SetEscapeQuotes(escapeQuotes bool) *CSVFileInfo {
 	info.EscapeQuotes = escapeQuotes
 	return info
 }
+
+// SetExpandedLists sets the ExpandedLists member and returns the CSVFileInfo
+func (info *CSVFileInfo) SetExpandedLists(expandedLists map[string]int) *CSVFileInfo {
+	info.ExpandedLists = expandedLists
+	return info
+}
+
+// SetQuotedEmptyAsNull sets the QuotedEmptyAsNull member and returns the CSVFileInfo
+func (info *CSVFileInfo) SetQuotedEmptyAsNull(quotedEmptyAsNull bool) *CSVFileInfo {
+	info.QuotedEmptyAsNull = quotedEmptyAsNull
+	return info
+}
+
+// SetNullValues sets the NullValues member and returns the CSVFileInfo
+func (info *CSVFileInfo) SetNullValues(nullValues ...string) *CSVFileInfo {
+	info.NullValues = nullValues
+	return info
+}
+
+// SetVirtualColumns sets the VirtualColumns member and returns the CSVFileInfo
+func (info *CSVFileInfo) SetVirtualColumns(virtualColumns ...VirtualColumn) *CSVFileInfo {
+	info.VirtualColumns = virtualColumns
+	return info
+}
+
+// SetTypedHeader sets the TypedHeader member and returns the CSVFileInfo
+func (info *CSVFileInfo) SetTypedHeader(typedHeader bool) *CSVFileInfo {
+	info.TypedHeader = typedHeader
+	return info
+}
+
+// SetColumnFormatters sets the ColumnFormatters member and returns the CSVFileInfo
+func (info *CSVFileInfo) SetColumnFormatters(columnFormatters map[string]ValueFormatter) *CSVFileInfo {
+	info.ColumnFormatters = columnFormatters
+	return info
+}
+
+// SetInvalidUTF8 sets the InvalidUTF8 member and returns the CSVFileInfo
+func (info *CSVFileInfo) SetInvalidUTF8(invalidUTF8 InvalidUTF8Policy) *CSVFileInfo {
+	info.InvalidUTF8 = invalidUTF8
+	return info
+}
+
+// SetWhere sets the Where member and returns the CSVFileInfo
+func (info *CSVFileInfo) SetWhere(where *RowPredicate) *CSVFileInfo {
+	info.Where = where
+	return info
+}
+
+// SetSummaryLabel sets the SummaryLabel member and returns the CSVFileInfo
+func (info *CSVFileInfo) SetSummaryLabel(summaryLabel string) *CSVFileInfo {
+	info.SummaryLabel = summaryLabel
+	return info
+}
+
+// SetFlushEveryRows sets the FlushEveryRows member and returns the CSVFileInfo
+func (info *CSVFileInfo) SetFlushEveryRows(flushEveryRows int) *CSVFileInfo {
+	info.FlushEveryRows = flushEveryRows
+	return info
+}
+
+// SetFlushInterval sets the FlushInterval member and returns the CSVFileInfo
+func (info *CSVFileInfo) SetFlushInterval(flushInterval time.Duration) *CSVFileInfo {
+	info.FlushInterval = flushInterval
+	return info
+}
+
+// SetTransforms sets the Transforms member and returns the CSVFileInfo
+func (info *CSVFileInfo) SetTransforms(transforms ...RowTransform) *CSVFileInfo {
+	info.Transforms = transforms
+	return info
+}
+
+// SetColumnOrder sets the ColumnOrder member and returns the CSVFileInfo
+func (info *CSVFileInfo) SetColumnOrder(columnOrder ...string) *CSVFileInfo {
+	info.ColumnOrder = columnOrder
+	return info
+}
@@ -0,0 +1,113 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package csv
+
+import (
+	"bufio"
+	"sync"
+	"time"
+)
+
+// periodicFlusher flushes the rows buffered by a CSVWriter every |everyRows| rows, and |interval| after a row is
+// buffered, so that readers of the output see rows promptly. Timed flushes happen on their own goroutine, so writes
+// to the buffered writer must go through write once a periodicFlusher has been created for it.
+type periodicFlusher struct {
+	mu sync.Mutex
+	wr *bufio.Writer
+
+	everyRows int
+	interval  time.Duration
+
+	// pending is the number of rows buffered since the last flush
+	pending int
+	// gen is incremented by each flush, so that a timed flush can tell whether the rows it was started for were
+	// already flushed
+	gen     uint64
+	timer   *time.Timer
+	stopped bool
+}
+
+// newPeriodicFlusher returns a periodicFlusher for |wr| as configured by |info|, or nil if |info| doesn't configure
+// periodic flushing.
+func newPeriodicFlusher(wr *bufio.Writer, info *CSVFileInfo) *periodicFlusher {
+	if info.FlushEveryRows <= 0 && info.FlushInterval <= 0 {
+		return nil
+	}
+
+	return &periodicFlusher{wr: wr, everyRows: info.FlushEveryRows, interval: info.FlushInterval}
+}
+
+// write calls |writeRow| to buffer a row, then flushes if |everyRows| rows have been buffered since the last flush,
+// or starts the timer for a timed flush if one isn't pending. Errors from timed flushes are returned by later writes
+// and by flushing, as the buffered writer keeps them.
+func (pf *periodicFlusher) write(writeRow func() error) error {
+	pf.mu.Lock()
+	defer pf.mu.Unlock()
+
+	if err := writeRow(); err != nil {
+		return err
+	}
+
+	pf.pending++
+	if pf.everyRows > 0 && pf.pending >= pf.everyRows {
+		return pf.flush()
+	}
+
+	if pf.interval > 0 && pf.timer == nil {
+		gen := pf.gen
+		pf.timer = time.AfterFunc(pf.interval, func() {
+			pf.timedFlush(gen)
+		})
+	}
+
+	return nil
+}
+
+// flush flushes the buffered rows. pf.mu must be held.
+func (pf *periodicFlusher) flush() error {
+	pf.pending = 0
+	pf.gen++
+	if pf.timer != nil {
+		pf.timer.Stop()
+		pf.timer = nil
+	}
+
+	return pf.wr.Flush()
+}
+
+// timedFlush flushes the rows buffered since the flush numbered |gen|, unless they have already been flushed.
+func (pf *periodicFlusher) timedFlush(gen uint64) {
+	pf.mu.Lock()
+	defer pf.mu.Unlock()
+
+	if pf.stopped || gen != pf.gen {
+		return
+	}
+
+	_ = pf.flush()
+}
+
+// stop cancels any pending timed flush, and waits for one that is in progress. Once stopped, the buffered writer
+// is no longer written to by the periodicFlusher, and the final flush is left to the caller.
+func (pf *periodicFlusher) stop() {
+	pf.mu.Lock()
+	defer pf.mu.Unlock()
+
+	pf.stopped = true
+	if pf.timer != nil {
+		pf.timer.Stop()
+		pf.timer = nil
+	}
+}
@@ -0,0 +1,131 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package csv
+
+import (
+	"bytes"
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// syncBuffer is an io.WriteCloser whose contents can be read while it's written to on another goroutine.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (sb *syncBuffer) Write(p []byte) (int, error) {
+	sb.mu.Lock()
+	defer sb.mu.Unlock()
+	return sb.buf.Write(p)
+}
+
+func (sb *syncBuffer) Close() error {
+	return nil
+}
+
+func (sb *syncBuffer) String() string {
+	sb.mu.Lock()
+	defer sb.mu.Unlock()
+	return sb.buf.String()
+}
+
+func TestWriterPeriodicFlush(t *testing.T) {
+	ctx := context.Background()
+	rows := getSampleRows()
+
+	const header = "name,age,title\n"
+	lines := []string{
+		"Bill Billerson,32,Senior Dufus\n",
+		"Rob Robertson,25,Dufus\n",
+		"John Johnson,21,\"\"\n",
+		"Andy Anderson,27,\n",
+	}
+	all := header + lines[0] + lines[1] + lines[2] + lines[3]
+
+	t.Run("every rows", func(t *testing.T) {
+		out := &syncBuffer{}
+		csvWr, err := NewCSVWriter(out, outSch, NewCSVInfo().SetFlushEveryRows(2))
+		require.NoError(t, err)
+
+		require.NoError(t, csvWr.WriteRow(ctx, rows[0]))
+		assert.Equal(t, "", out.String())
+		require.NoError(t, csvWr.WriteRow(ctx, rows[1]))
+		assert.Equal(t, header+lines[0]+lines[1], out.String())
+		require.NoError(t, csvWr.WriteRow(ctx, rows[2]))
+		assert.Equal(t, header+lines[0]+lines[1], out.String())
+
+		// the final rows are flushed on close
+		require.NoError(t, csvWr.WriteRow(ctx, rows[3]))
+		require.NoError(t, csvWr.Close(ctx))
+		assert.Equal(t, all, out.String())
+		assert.Equal(t, int64(len(all)), csvWr.BytesFlushed())
+	})
+
+	t.Run("interval", func(t *testing.T) {
+		out := &syncBuffer{}
+		csvWr, err := NewCSVWriter(out, outSch, NewCSVInfo().SetFlushInterval(20*time.Millisecond))
+		require.NoError(t, err)
+
+		require.NoError(t, csvWr.WriteRow(ctx, rows[0]))
+		require.Eventually(t, func() bool {
+			return out.String() == header+lines[0]
+		}, time.Second, time.Millisecond)
+
+		require.NoError(t, csvWr.WriteRow(ctx, rows[1]))
+		require.NoError(t, csvWr.WriteRow(ctx, rows[2]))
+		require.Eventually(t, func() bool {
+			return out.String() == header+lines[0]+lines[1]+lines[2]
+		}, time.Second, time.Millisecond)
+
+		require.NoError(t, csvWr.WriteRow(ctx, rows[3]))
+		require.NoError(t, csvWr.Close(ctx))
+		assert.Equal(t, all, out.String())
+	})
+
+	t.Run("interval after close", func(t *testing.T) {
+		out := &syncBuffer{}
+		csvWr, err := NewCSVWriter(out, outSch, NewCSVInfo().SetFlushInterval(time.Millisecond))
+		require.NoError(t, err)
+
+		for _, r := range rows {
+			require.NoError(t, csvWr.WriteRow(ctx, r))
+		}
+		require.NoError(t, csvWr.Close(ctx))
+
+		// a timed flush doesn't write anything once the writer is closed
+		time.Sleep(10 * time.Millisecond)
+		assert.Equal(t, all, out.String())
+	})
+
+	t.Run("no periodic flushes by default", func(t *testing.T) {
+		out := &syncBuffer{}
+		csvWr, err := NewCSVWriter(out, outSch, NewCSVInfo())
+		require.NoError(t, err)
+
+		for _, r := range rows {
+			require.NoError(t, csvWr.WriteRow(ctx, r))
+		}
+		assert.Equal(t, "", out.String())
+
+		require.NoError(t, csvWr.Close(ctx))
+		assert.Equal(t, all, out.String())
+	})
+}
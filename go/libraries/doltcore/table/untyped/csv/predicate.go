@@ -0,0 +1,169 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package csv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/row"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// CompareOp is the comparison a RowPredicate makes between a column's value and its literal value.
+type CompareOp string
+
+const (
+	OpEq CompareOp = "=="
+	OpNe CompareOp = "!="
+	OpLt CompareOp = "<"
+	OpGt CompareOp = ">"
+	OpLe CompareOp = "<="
+	OpGe CompareOp = ">="
+)
+
+// compareOps are the supported operators, with each operator before any operator that is a prefix of it.
+var compareOps = []CompareOp{OpEq, OpNe, OpLe, OpGe, OpLt, OpGt}
+
+// RowPredicate is a condition of the form column op value, used to choose which rows are written.
+type RowPredicate struct {
+	// Column is the name of the column compared
+	Column string
+	// Op is the comparison made
+	Op CompareOp
+	// Value is the literal the column's value is compared to
+	Value string
+}
+
+// ParseRowPredicate parses an expression of the form column op value, such as status == active or age >= 21, where
+// op is one of ==, !=, <, >, <= or >=. The value can be quoted with single or double quotes to include surrounding
+// whitespace or to compare to the empty string.
+func ParseRowPredicate(expr string) (*RowPredicate, error) {
+	i := strings.IndexAny(expr, "=!<>")
+	if i < 0 {
+		return nil, fmt.Errorf("invalid expression '%s': expected column op value", expr)
+	}
+
+	col := strings.TrimSpace(expr[:i])
+	if col == "" {
+		return nil, fmt.Errorf("invalid expression '%s': missing column", expr)
+	}
+
+	var op CompareOp
+	for _, o := range compareOps {
+		if strings.HasPrefix(expr[i:], string(o)) {
+			op = o
+			break
+		}
+	}
+
+	if op == "" {
+		return nil, fmt.Errorf("invalid expression '%s': expected one of ==, !=, <, >, <= or >=", expr)
+	}
+
+	val := strings.TrimSpace(expr[i+len(op):])
+	if len(val) >= 2 && (val[0] == '"' || val[0] == '\'') && val[len(val)-1] == val[0] {
+		val = val[1 : len(val)-1]
+	} else if val == "" {
+		return nil, fmt.Errorf("invalid expression '%s': missing value", expr)
+	}
+
+	return &RowPredicate{Column: col, Op: op, Value: val}, nil
+}
+
+// Matches returns whether |r|, a row of |sch|, satisfies the predicate. The predicate's value is parsed as the same
+// kind as the row's value, so numbers are compared numerically and strings lexically. Values of other kinds are
+// compared by the strings written for them. A NULL value never matches.
+func (p *RowPredicate) Matches(ctx context.Context, sch schema.Schema, r row.Row) (bool, error) {
+	col, ok := sch.GetAllCols().GetByName(p.Column)
+	if !ok {
+		return false, fmt.Errorf("unknown column '%s' in expression", p.Column)
+	}
+
+	val, ok := r.GetColVal(col.Tag)
+	if !ok || types.IsNull(val) {
+		return false, nil
+	}
+
+	lit, err := parseLiteral(val.Kind(), p.Value)
+	if err != nil {
+		return false, fmt.Errorf("can't compare column '%s' to '%s': %w", p.Column, p.Value, err)
+	}
+
+	if lit == nil {
+		str, err := formatValue(ctx, val)
+		if err != nil {
+			return false, err
+		}
+
+		val, lit = types.String(*str), types.String(p.Value)
+	}
+
+	if p.Op == OpEq || p.Op == OpNe {
+		return val.Equals(lit) == (p.Op == OpEq), nil
+	}
+
+	isLess, err := val.Less(types.Format_Default, lit)
+	if err != nil {
+		return false, err
+	}
+
+	switch p.Op {
+	case OpLt:
+		return isLess, nil
+	case OpGe:
+		return !isLess, nil
+	case OpLe:
+		return isLess || val.Equals(lit), nil
+	case OpGt:
+		return !isLess && !val.Equals(lit), nil
+	}
+
+	return false, errors.New("unknown comparison " + string(p.Op))
+}
+
+// parseLiteral parses |str| as a value of |kind|, returning nil for kinds that are compared as strings.
+func parseLiteral(kind types.NomsKind, str string) (types.Value, error) {
+	switch kind {
+	case types.StringKind:
+		return types.String(str), nil
+	case types.IntKind:
+		i, err := strconv.ParseInt(str, 10, 64)
+		return types.Int(i), err
+	case types.UintKind:
+		u, err := strconv.ParseUint(str, 10, 64)
+		return types.Uint(u), err
+	case types.FloatKind:
+		f, err := strconv.ParseFloat(str, 64)
+		return types.Float(f), err
+	case types.BoolKind:
+		b, err := strconv.ParseBool(str)
+		return types.Bool(b), err
+	}
+
+	return nil, nil
+}
+
+// rowMatches returns whether |r| satisfies |info|'s Where predicate, if it has one.
+func rowMatches(ctx context.Context, sch schema.Schema, info *CSVFileInfo, r row.Row) (bool, error) {
+	if info.Where == nil {
+		return true, nil
+	}
+	return info.Where.Matches(ctx, sch, r)
+}
@@ -0,0 +1,103 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package csv
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/utils/filesys"
+)
+
+func TestParseRowPredicate(t *testing.T) {
+	tests := []struct {
+		expr     string
+		expected *RowPredicate
+	}{
+		{"status == active", &RowPredicate{"status", OpEq, "active"}},
+		{"age>=21", &RowPredicate{"age", OpGe, "21"}},
+		{"age <= 21", &RowPredicate{"age", OpLe, "21"}},
+		{"age < 21", &RowPredicate{"age", OpLt, "21"}},
+		{"age > 21", &RowPredicate{"age", OpGt, "21"}},
+		{"title != 'Senior Dufus'", &RowPredicate{"title", OpNe, "Senior Dufus"}},
+		{`title == ""`, &RowPredicate{"title", OpEq, ""}},
+		{`title == " padded "`, &RowPredicate{"title", OpEq, " padded "}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.expr, func(t *testing.T) {
+			p, err := ParseRowPredicate(test.expr)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, p)
+		})
+	}
+
+	for _, expr := range []string{"", "status", "== active", "status = active", "status ! active", "status =="} {
+		_, err := ParseRowPredicate(expr)
+		assert.Error(t, err, expr)
+	}
+}
+
+func TestWriterWhere(t *testing.T) {
+	const root = "/"
+	const path = "/file.csv"
+
+	tests := []struct {
+		where    string
+		expected string
+	}{
+		{"age > 24", "name,age,title\nBill Billerson,32,Senior Dufus\nRob Robertson,25,Dufus\nAndy Anderson,27,\n"},
+		{"age <= 25", "name,age,title\nRob Robertson,25,Dufus\nJohn Johnson,21,\"\"\n"},
+		{"title == Dufus", "name,age,title\nRob Robertson,25,Dufus\n"},
+		// NULL titles never match
+		{"title != Dufus", "name,age,title\nBill Billerson,32,Senior Dufus\nJohn Johnson,21,\"\"\n"},
+		{"name == Nobody", "name,age,title\n"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.where, func(t *testing.T) {
+			where, err := ParseRowPredicate(test.where)
+			require.NoError(t, err)
+
+			fs := filesys.NewInMemFS(nil, nil, root)
+			csvWr, err := OpenCSVWriter(path, fs, outSch, NewCSVInfo().SetWhere(where))
+			require.NoError(t, err)
+
+			writeToCSV(csvWr, getSampleRows(), t)
+
+			results, err := fs.ReadFile(path)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, string(results))
+		})
+	}
+
+	t.Run("errors", func(t *testing.T) {
+		ctx := context.Background()
+		r := getSampleRows()[0]
+
+		where, err := ParseRowPredicate("missing == 1")
+		require.NoError(t, err)
+		_, err = where.Matches(ctx, outSch, r)
+		assert.Error(t, err)
+
+		where, err = ParseRowPredicate("age > old")
+		require.NoError(t, err)
+		_, err = where.Matches(ctx, outSch, r)
+		assert.Error(t, err)
+	})
+}
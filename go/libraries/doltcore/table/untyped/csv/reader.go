@@ -51,9 +51,11 @@ type CSVReader struct {
 	// This parser has been adapted to differentiate between quoted and unquoted
 	// empty strings, and to use multi-rune delimiters. This adaptation removes the
 	// comment feature and the lazyQuotes option
-	delim           []byte
-	numLine         int
-	fieldsPerRecord int
+	delim             []byte
+	numLine           int
+	fieldsPerRecord   int
+	quotedEmptyAsNull bool
+	nullValues        map[string]struct{}
 }
 
 // OpenCSVReader opens a reader at a given path within a given filesys.  The CSVFileInfo should describe the csv file
@@ -87,14 +89,24 @@ func NewCSVReader(nbf *types.NomsBinFormat, r io.ReadCloser, info *CSVFileInfo)
 
 	_, sch := untyped.NewUntypedSchema(colStrs...)
 
+	var nullValues map[string]struct{}
+	if len(info.NullValues) > 0 {
+		nullValues = make(map[string]struct{}, len(info.NullValues))
+		for _, nv := range info.NullValues {
+			nullValues[nv] = struct{}{}
+		}
+	}
+
 	return &CSVReader{
-		closer:          r,
-		bRd:             br,
-		sch:             sch,
-		isDone:          false,
-		nbf:             nbf,
-		delim:           []byte(info.Delim),
-		fieldsPerRecord: sch.GetAllCols().Size(),
+		closer:            r,
+		bRd:               br,
+		sch:               sch,
+		isDone:            false,
+		nbf:               nbf,
+		delim:             []byte(info.Delim),
+		fieldsPerRecord:   sch.GetAllCols().Size(),
+		quotedEmptyAsNull: info.QuotedEmptyAsNull,
+		nullValues:        nullValues,
 	}, nil
 }
 
@@ -276,7 +288,8 @@ func (csvr *CSVReader) csvReadRecords(dst []*string) ([]*string, error) {
 	}
 
 	// nullString indicates whether to interpret an empty string as a NULL
-	// only empty strings escaped with double quotes will be non-null
+	// only empty strings escaped with double quotes will be non-null, unless
+	// quotedEmptyAsNull is set. Unquoted fields matching a null value are also null
 	nullString := make(map[int]bool)
 	fieldIdx := 0
 
@@ -286,12 +299,17 @@ func (csvr *CSVReader) csvReadRecords(dst []*string) ([]*string, error) {
 		rs.line = bytes.TrimLeftFunc(rs.line, unicode.IsSpace)
 		keep := true
 		if len(rs.line) == 0 || rs.line[0] != '"' {
+			start := len(rs.recordBuffer)
 			kontinue, keep, err = csvr.parseField(&rs)
-			if !keep {
+			if !keep || csvr.isNullValue(rs.recordBuffer[start:]) {
 				nullString[fieldIdx] = true
 			}
 		} else {
+			start := len(rs.recordBuffer)
 			kontinue, err = csvr.parseQuotedField(&rs)
+			if csvr.quotedEmptyAsNull && len(rs.recordBuffer) == start {
+				nullString[fieldIdx] = true
+			}
 		}
 		fieldIdx++
 	}
@@ -328,6 +346,16 @@ func (csvr *CSVReader) csvReadRecords(dst []*string) ([]*string, error) {
 	return dst, err
 }
 
+// isNullValue returns whether the unquoted |field| is one of the reader's null values
+func (csvr *CSVReader) isNullValue(field []byte) bool {
+	if csvr.nullValues == nil {
+		return false
+	}
+
+	_, ok := csvr.nullValues[string(field)]
+	return ok
+}
+
 func (csvr *CSVReader) parseField(rs *recordState) (kontinue bool, keep bool, err error) {
 	i := bytes.Index(rs.line, csvr.delim)
 	field := rs.line
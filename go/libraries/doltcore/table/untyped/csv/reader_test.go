@@ -167,3 +167,77 @@ func readTestRows(t *testing.T, inputStr string, info *CSVFileInfo) ([]row.Row,
 
 	return rows, badRows, err
 }
+
+func TestReaderQuotedEmpty(t *testing.T) {
+	const input = "a,b,c\nx,\"\",\n"
+
+	tests := []struct {
+		name     string
+		info     *CSVFileInfo
+		expected []*string
+	}{
+		{
+			name:     "quoted empty is empty string",
+			info:     NewCSVInfo(),
+			expected: []*string{strPtr("x"), strPtr(""), nil},
+		},
+		{
+			name:     "quoted empty is null",
+			info:     NewCSVInfo().SetQuotedEmptyAsNull(true),
+			expected: []*string{strPtr("x"), nil, nil},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rows, numBad, err := readTestRows(t, input, test.info)
+			if err != nil {
+				t.Fatal("Unexpected Error:", err)
+			}
+			if numBad != 0 || len(rows) != 1 {
+				t.Fatal("expected a single good row. bad:", numBad, "rows:", len(rows))
+			}
+
+			for tag, exp := range test.expected {
+				val, ok := rows[0].GetColVal(uint64(tag))
+				if exp == nil {
+					if ok && !types.IsNull(val) {
+						t.Error("expected null for column", tag, "got", val)
+					}
+				} else if !ok || val != types.String(*exp) {
+					t.Error("expected", *exp, "for column", tag, "got", val)
+				}
+			}
+		})
+	}
+}
+
+func TestReaderNullValues(t *testing.T) {
+	const input = "a,b,c,d,e\nx,\\N,NULL,,\"NULL\"\n"
+	info := NewCSVInfo().SetNullValues(`\N`, "NULL")
+
+	rows, numBad, err := readTestRows(t, input, info)
+	if err != nil {
+		t.Fatal("Unexpected Error:", err)
+	}
+	if numBad != 0 || len(rows) != 1 {
+		t.Fatal("expected a single good row. bad:", numBad, "rows:", len(rows))
+	}
+
+	// quoted fields are never null values
+	expected := []*string{strPtr("x"), nil, nil, nil, strPtr("NULL")}
+	for tag, exp := range expected {
+		val, ok := rows[0].GetColVal(uint64(tag))
+		if exp == nil {
+			if ok && !types.IsNull(val) {
+				t.Error("expected null for column", tag, "got", val)
+			}
+		} else if !ok || val != types.String(*exp) {
+			t.Error("expected", *exp, "for column", tag, "got", val)
+		}
+	}
+}
+
+func strPtr(s string) *string {
+	return &s
+}
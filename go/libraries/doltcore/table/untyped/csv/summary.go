@@ -0,0 +1,135 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package csv
+
+import (
+	"math/big"
+	"strconv"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/row"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// rowSummary accumulates the aggregates written in the summary row of a csv file as rows are written. Columns with
+// integer or float values are summed, and every other column counts its non-NULL values. Columns are aggregated by
+// the kinds of their values rather than of the schema's columns, as rows written with untyped schemas still hold
+// typed values. Virtual columns count the rows
+// written, and list columns written as expanded lists are left empty.
+type rowSummary struct {
+	label string
+	cols  []*columnAggregate
+	rows  int64
+	// nVirtual is the number of virtual columns written after the schema's columns
+	nVirtual int
+}
+
+// columnAggregate is the aggregate of one of the schema's columns.
+type columnAggregate struct {
+	tag      uint64
+	expanded int
+
+	intSum   big.Int
+	floatSum float64
+	count    int64
+	sawInt   bool
+	sawFloat bool
+}
+
+// newRowSummary returns a rowSummary for rows of |sch| written as |info| says.
+func newRowSummary(sch schema.Schema, info *CSVFileInfo) *rowSummary {
+	s := &rowSummary{label: info.SummaryLabel, nVirtual: len(info.VirtualColumns)}
+	_ = sch.GetAllCols().Iter(func(tag uint64, col schema.Column) (stop bool, err error) {
+		agg := &columnAggregate{tag: tag}
+		if n, ok := info.ExpandedLists[col.Name]; ok {
+			agg.expanded = n
+		}
+
+		s.cols = append(s.cols, agg)
+		return false, nil
+	})
+
+	return s
+}
+
+// add adds the values of |r| to the aggregates.
+func (s *rowSummary) add(r row.Row) {
+	s.rows++
+
+	var n big.Int
+	for _, agg := range s.cols {
+		val, ok := r.GetColVal(agg.tag)
+		if !ok || types.IsNull(val) || agg.expanded > 0 {
+			continue
+		}
+
+		agg.count++
+		switch v := val.(type) {
+		case types.Int:
+			agg.intSum.Add(&agg.intSum, n.SetInt64(int64(v)))
+			agg.sawInt = true
+		case types.Uint:
+			agg.intSum.Add(&agg.intSum, n.SetUint64(uint64(v)))
+			agg.sawInt = true
+		case types.Float:
+			agg.floatSum += float64(v)
+			agg.sawFloat = true
+		}
+	}
+}
+
+// record returns the fields of the summary row, in the order given by |order|. The first field is the summary's
+// label, in place of the first column's aggregate.
+func (s *rowSummary) record(order []int) []*string {
+	fields := make([]*string, 0, len(s.cols)+s.nVirtual)
+	for _, agg := range s.cols {
+		if agg.expanded > 0 {
+			for i := 0; i < agg.expanded; i++ {
+				fields = append(fields, nil)
+			}
+			continue
+		}
+
+		str := agg.String()
+		fields = append(fields, &str)
+	}
+
+	for i := 0; i < s.nVirtual; i++ {
+		str := strconv.FormatInt(s.rows, 10)
+		fields = append(fields, &str)
+	}
+
+	fields = reorderFields(order, fields)
+	if len(fields) > 0 {
+		label := s.label
+		fields[0] = &label
+	}
+
+	return fields
+}
+
+// String returns the aggregate as it is written in the summary row. Columns with both integer and float values
+// are summed as floats.
+func (agg *columnAggregate) String() string {
+	switch {
+	case agg.sawFloat:
+		intSum, _ := new(big.Float).SetInt(&agg.intSum).Float64()
+		return strconv.FormatFloat(agg.floatSum+intSum, 'g', -1, 64)
+	case agg.sawInt:
+		return agg.intSum.String()
+	default:
+		return strconv.FormatInt(agg.count, 10)
+	}
+}
@@ -0,0 +1,146 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package csv
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/row"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/utils/filesys"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func TestWriterSummary(t *testing.T) {
+	const root = "/"
+	const path = "/file.csv"
+
+	t.Run("sample rows", func(t *testing.T) {
+		fs := filesys.NewInMemFS(nil, nil, root)
+		csvWr, err := OpenCSVWriter(path, fs, outSch, NewCSVInfo().SetSummaryLabel("TOTAL"))
+		require.NoError(t, err)
+
+		writeToCSV(csvWr, getSampleRows(), t)
+
+		// ages are summed, and the non-NULL titles are counted
+		const expected = `name,age,title
+Bill Billerson,32,Senior Dufus
+Rob Robertson,25,Dufus
+John Johnson,21,""
+Andy Anderson,27,
+TOTAL,105,3
+`
+		results, err := fs.ReadFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, expected, string(results))
+	})
+
+	cols, err := schema.NewColCollection(
+		schema.NewColumn("id", 0, types.IntKind, true),
+		schema.NewColumn("qty", 1, types.IntKind, false),
+		schema.NewColumn("price", 2, types.FloatKind, false),
+		schema.NewColumn("note", 3, types.StringKind, false),
+	)
+	require.NoError(t, err)
+	sch := schema.MustSchemaFromCols(cols)
+
+	rows := []row.Row{
+		mustRow(row.New(types.Format_7_18, sch, row.TaggedValues{0: types.Int(1), 1: types.Int(-4), 2: types.Float(1.5), 3: types.String("a")})),
+		mustRow(row.New(types.Format_7_18, sch, row.TaggedValues{0: types.Int(2), 1: types.Int(10), 2: types.Float(0.25)})),
+		mustRow(row.New(types.Format_7_18, sch, row.TaggedValues{0: types.Int(3), 2: types.Float(2)})),
+	}
+	rowCount := VirtualColumn{
+		Name:  "one",
+		Value: func(ctx context.Context, r row.Row) (string, error) { return "1", nil },
+	}
+
+	t.Run("numeric and non-numeric columns", func(t *testing.T) {
+		fs := filesys.NewInMemFS(nil, nil, root)
+		info := NewCSVInfo().SetSummaryLabel("summary").SetVirtualColumns(rowCount)
+		csvWr, err := OpenCSVWriter(path, fs, sch, info)
+		require.NoError(t, err)
+
+		writeToCSV(csvWr, rows, t)
+
+		// integers and floats are summed, NULLs are skipped, and other columns, including virtual columns, are
+		// counted
+		const expected = `id,qty,price,note,one
+1,-4,1.5,a,1
+2,10,0.25,,1
+3,,2,,1
+summary,6,3.75,1,3
+`
+		results, err := fs.ReadFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, expected, string(results))
+	})
+
+	t.Run("where", func(t *testing.T) {
+		where, err := ParseRowPredicate("id > 1")
+		require.NoError(t, err)
+
+		fs := filesys.NewInMemFS(nil, nil, root)
+		csvWr, err := OpenCSVWriter(path, fs, sch, NewCSVInfo().SetSummaryLabel("summary").SetWhere(where))
+		require.NoError(t, err)
+
+		writeToCSV(csvWr, rows, t)
+
+		// only the rows written are aggregated
+		const expected = `id,qty,price,note
+2,10,0.25,
+3,,2,
+summary,10,2.25,0
+`
+		results, err := fs.ReadFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, expected, string(results))
+	})
+
+	t.Run("batch writer", func(t *testing.T) {
+		var written [][]string
+		sink := func(batch [][]string) error {
+			for _, r := range batch {
+				written = append(written, append([]string(nil), r...))
+			}
+			return nil
+		}
+
+		bw, err := NewBatchWriter(sch, NewCSVInfo().SetSummaryLabel("summary"), 2, sink)
+		require.NoError(t, err)
+		for _, r := range rows {
+			require.NoError(t, bw.WriteRow(context.Background(), r))
+		}
+		require.NoError(t, bw.Close(context.Background()))
+
+		require.Len(t, written, 4)
+		assert.Equal(t, []string{"summary", "6", "3.75", "1"}, written[3])
+	})
+
+	t.Run("no summary by default", func(t *testing.T) {
+		fs := filesys.NewInMemFS(nil, nil, root)
+		csvWr, err := OpenCSVWriter(path, fs, sch, NewCSVInfo())
+		require.NoError(t, err)
+
+		writeToCSV(csvWr, rows, t)
+
+		results, err := fs.ReadFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, "id,qty,price,note\n1,-4,1.5,a\n2,10,0.25,\n3,,2,\n", string(results))
+	})
+}
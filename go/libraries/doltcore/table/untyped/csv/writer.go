@@ -18,13 +18,17 @@ import (
 	"bufio"
 	"context"
 	"errors"
+	"fmt"
 	"io"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync/atomic"
 	"unicode"
 	"unicode/utf8"
 
+	"github.com/google/uuid"
+
 	"github.com/dolthub/dolt/go/libraries/doltcore/row"
 	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
 	"github.com/dolthub/dolt/go/libraries/utils/filesys"
@@ -35,13 +39,54 @@ import (
 // writers create their own buffer's using the value of this variable at the time they create their buffers.
 const writeBufSize = 256 * 1024
 
-// CSVWriter implements TableWriter.  It writes rows as comma separated string values
+// ErrInvalidUTF8 is returned when writing a field that isn't valid UTF-8 under the InvalidUTF8Error policy.
+var ErrInvalidUTF8 = errors.New("invalid UTF-8")
+
+// CSVWriter implements TableWriter.  It writes rows as comma separated string values. Rows are buffered, and are
+// only guaranteed to reach the underlying writer once Close is called. A row with a value that can't be formatted
+// is not written at all, so output is always made up of whole rows.
 type CSVWriter struct {
 	wr      *bufio.Writer
+	cw      *countingWriter
 	closer  io.Closer
 	info    *CSVFileInfo
 	sch     schema.Schema
 	useCRLF bool // True to use \r\n as the line terminator
+
+	// atomic, if non-nil, moves the file written into place when the writer is closed
+	atomic *atomicFile
+
+	// summary, if non-nil, accumulates the summary row written on Close
+	summary *rowSummary
+
+	// flusher, if non-nil, flushes rows as the CSVFileInfo's FlushEveryRows and FlushInterval say
+	flusher *periodicFlusher
+
+	// order, if non-nil, is the order fields are written in to follow the CSVFileInfo's ColumnOrder
+	order []int
+}
+
+// atomicFile is a temporary file that is moved to |path| once it has been written completely.
+type atomicFile struct {
+	fs        filesys.WritableFS
+	tempPath  string
+	path      string
+	committed bool
+}
+
+// commit moves the temporary file to its final path if |err| is nil, and otherwise removes it.
+func (af *atomicFile) commit(err error) error {
+	if err == nil {
+		err = af.fs.MoveFile(af.tempPath, af.path)
+	}
+
+	if err != nil {
+		_ = af.fs.DeleteFile(af.tempPath)
+		return err
+	}
+
+	af.committed = true
+	return nil
 }
 
 // OpenCSVWriter creates a file at the given path in the given filesystem and writes out rows based on the Schema,
@@ -62,27 +107,65 @@ func OpenCSVWriter(path string, fs filesys.WritableFS, outSch schema.Schema, inf
 	return NewCSVWriter(wr, outSch, info)
 }
 
+// OpenAtomicCSVWriter is OpenCSVWriter, except that rows are written to a temporary file beside |path| which is only
+// moved to |path| once the writer has been closed successfully. If writing or closing fails, or Abort is called,
+// the temporary file is removed, so |path| never holds partial output.
+func OpenAtomicCSVWriter(path string, fs filesys.WritableFS, outSch schema.Schema, info *CSVFileInfo) (*CSVWriter, error) {
+	dir, name := filepath.Split(path)
+	af := &atomicFile{
+		fs:       fs,
+		tempPath: filepath.Join(dir, fmt.Sprintf(".%s.%s.tmp", name, uuid.New().String())),
+		path:     path,
+	}
+
+	csvw, err := OpenCSVWriter(af.tempPath, fs, outSch, info)
+
+	if err != nil {
+		return nil, af.commit(err)
+	}
+
+	csvw.atomic = af
+	return csvw, nil
+}
+
 // NewCSVWriter writes rows to the given WriteCloser based on the Schema and CSVFileInfo provided
 func NewCSVWriter(wr io.WriteCloser, outSch schema.Schema, info *CSVFileInfo) (*CSVWriter, error) {
 
+	cw := &countingWriter{w: wr}
 	csvw := &CSVWriter{
-		wr:     bufio.NewWriterSize(wr, writeBufSize),
+		wr:     bufio.NewWriterSize(cw, writeBufSize),
+		cw:     cw,
 		closer: wr,
 		info:   info,
 		sch:    outSch,
 	}
 
+	if info.SummaryLabel != "" {
+		csvw.summary = newRowSummary(outSch, info)
+	}
+
+	names, kinds, err := fieldNames(outSch, info)
+
+	if err != nil {
+		wr.Close()
+		return nil, err
+	}
+
+	csvw.order = newFieldOrder(names, info.ColumnOrder)
+
 	if info.HasHeaderLine {
-		colNames := make([]*string, 0, outSch.GetAllCols().Size())
-		err := outSch.GetAllCols().Iter(func(tag uint64, col schema.Column) (stop bool, err error) {
-			nm := col.Name
-			colNames = append(colNames, &nm)
-			return false, nil
-		})
+		colNames := make([]*string, len(names))
+		for i := range names {
+			nm := headerName(info, names[i], kinds[i])
+			colNames[i] = &nm
+		}
 
-		if err != nil {
-			wr.Close()
-			return nil, err
+		colNames = reorderFields(csvw.order, colNames)
+		for i, j := range csvw.order {
+			if j < 0 {
+				nm := headerName(info, info.ColumnOrder[i], types.StringKind)
+				colNames[i] = &nm
+			}
 		}
 
 		err = csvw.write(colNames)
@@ -93,59 +176,341 @@ func NewCSVWriter(wr io.WriteCloser, outSch schema.Schema, info *CSVFileInfo) (*
 		}
 	}
 
+	csvw.flusher = newPeriodicFlusher(csvw.wr, info)
+
 	return csvw, nil
 }
 
+// headerName returns the header for a column named |name| whose values are of kind |kind|.
+func headerName(info *CSVFileInfo, name string, kind types.NomsKind) string {
+	if !info.TypedHeader {
+		return name
+	}
+
+	return name + ":" + headerTypeName(kind)
+}
+
+// headerTypeName returns the type of a column in a typed header. Kinds that don't describe a single type of value,
+// such as those of union or unknown typed columns, fall back to string, as that is how their values are written.
+func headerTypeName(kind types.NomsKind) string {
+	switch kind {
+	case types.UnknownKind, types.UnionKind, types.ValueKind, types.NullKind:
+		return "string"
+	}
+
+	if name := kind.String(); name != "" {
+		return strings.ToLower(name)
+	}
+
+	return "string"
+}
+
 // GetSchema gets the schema of the rows that this writer writes
 func (csvw *CSVWriter) GetSchema() schema.Schema {
 	return csvw.sch
 }
 
-// WriteRow will write a row to a table
+// WriteRow will write a row to a table. Rows that don't satisfy the CSVFileInfo's Where predicate are skipped.
 func (csvw *CSVWriter) WriteRow(ctx context.Context, r row.Row) error {
-	allCols := csvw.sch.GetAllCols()
+	if ok, err := rowMatches(ctx, csvw.sch, csvw.info, r); err != nil || !ok {
+		return err
+	}
+
+	colValStrs, err := formatRow(ctx, csvw.sch, csvw.info, csvw.order, r)
+
+	if err != nil {
+		return err
+	}
+
+	if csvw.flusher != nil {
+		err = csvw.flusher.write(func() error {
+			return csvw.write(colValStrs)
+		})
+	} else {
+		err = csvw.write(colValStrs)
+	}
+
+	if err == nil && csvw.summary != nil {
+		csvw.summary.add(r)
+	}
+
+	return err
+}
+
+// formatRow returns the fields written for |r|, in the order given by |order|, with nil for NULL fields.
+func formatRow(ctx context.Context, sch schema.Schema, info *CSVFileInfo, order []int, r row.Row) ([]*string, error) {
+	allCols := sch.GetAllCols()
 
 	colValStrs := make([]*string, 0, allCols.Size())
-	_, err := r.IterSchema(csvw.sch, func(tag uint64, val types.Value) (stop bool, err error) {
-		val, ok := r.GetColVal(tag)
-		if !ok || types.IsNull(val) {
-			colValStrs = append(colValStrs, nil)
-			return false, nil
+	err := allCols.Iter(func(tag uint64, col schema.Column) (stop bool, err error) {
+		start := len(colValStrs)
+		colValStrs, err = appendColumn(ctx, colValStrs, info, col, r)
+		if err != nil {
+			return false, err
 		}
 
-		var v string
-		if val.Kind() == types.StringKind {
-			v = string(val.(types.String))
-		} else {
-			v, err = types.EncodedValue(ctx, val)
-			if err != nil {
-				return false, err
+		return false, applyInvalidUTF8Policy(info.InvalidUTF8, col.Name, colValStrs[start:])
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, vc := range info.VirtualColumns {
+		str, err := vc.Value(ctx, r)
+		if err != nil {
+			return nil, err
+		}
+
+		colValStrs = append(colValStrs, &str)
+		err = applyInvalidUTF8Policy(info.InvalidUTF8, vc.Name, colValStrs[len(colValStrs)-1:])
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return applyTransforms(info.Transforms, reorderFields(order, colValStrs))
+}
+
+// applyTransforms applies |transforms| in order to the fields |cells|. A NULL field left empty by the transforms is
+// still NULL.
+func applyTransforms(transforms []RowTransform, cells []*string) ([]*string, error) {
+	if len(transforms) == 0 {
+		return cells, nil
+	}
+
+	fields := make([]string, len(cells))
+	for i, cell := range cells {
+		if cell != nil {
+			fields[i] = *cell
+		}
+	}
+
+	for _, transform := range transforms {
+		var err error
+		fields, err = transform(fields)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	transformed := make([]*string, len(fields))
+	for i := range fields {
+		if fields[i] == "" && i < len(cells) && cells[i] == nil {
+			continue
+		}
+
+		transformed[i] = &fields[i]
+	}
+
+	return transformed, nil
+}
+
+// appendColumn appends the cells written for |col| in |r| to |cells|.
+func appendColumn(ctx context.Context, cells []*string, info *CSVFileInfo, col schema.Column, r row.Row) ([]*string, error) {
+	val, ok := r.GetColVal(col.Tag)
+	if !ok {
+		val = types.NullValue
+	}
+
+	if n, ok := info.ExpandedLists[col.Name]; ok {
+		return appendExpandedList(ctx, cells, val, n)
+	}
+
+	if f, ok := info.ColumnFormatters[col.Name]; ok && !types.IsNull(val) {
+		str, err := f(val)
+		if err != nil {
+			return nil, err
+		}
+
+		return append(cells, &str), nil
+	}
+
+	str, err := formatValue(ctx, val)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(cells, str), nil
+}
+
+// applyInvalidUTF8Policy checks the cells written for the column |colName| for invalid UTF-8, replacing or escaping
+// invalid bytes in place or returning an error as |policy| says.
+func applyInvalidUTF8Policy(policy InvalidUTF8Policy, colName string, cells []*string) error {
+	if policy == InvalidUTF8Passthrough {
+		return nil
+	}
+
+	for i, cell := range cells {
+		if cell == nil || utf8.ValidString(*cell) {
+			continue
+		}
+
+		if policy == InvalidUTF8Error {
+			return fmt.Errorf("%w in column %s", ErrInvalidUTF8, colName)
+		}
+
+		var sb strings.Builder
+		for str := *cell; len(str) > 0; {
+			r, size := utf8.DecodeRuneInString(str)
+			if r == utf8.RuneError && size == 1 {
+				if policy == InvalidUTF8Replace {
+					sb.WriteRune(utf8.RuneError)
+				} else {
+					fmt.Fprintf(&sb, "\\x%02x", str[0])
+				}
+			} else {
+				sb.WriteString(str[:size])
 			}
+			str = str[size:]
 		}
-		colValStrs = append(colValStrs, &v)
 
-		return false, nil
-	})
+		fixed := sb.String()
+		cells[i] = &fixed
+	}
+
+	return nil
+}
+
+// formatValue returns the csv representation of |val|, or nil if |val| is NULL
+func formatValue(ctx context.Context, val types.Value) (*string, error) {
+	if types.IsNull(val) {
+		return nil, nil
+	}
+
+	var v string
+	if val.Kind() == types.StringKind {
+		v = string(val.(types.String))
+	} else {
+		var err error
+		v, err = types.EncodedValue(ctx, val)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &v, nil
+}
 
+// appendExpandedList appends |n| cells for a list valued column. A list of exactly |n| elements is written one
+// element per cell. Any other value, including a list of a different length, is written as a single nested cell
+// in the first position with the remaining cells left NULL, so that every row has the same number of cells.
+func appendExpandedList(ctx context.Context, cells []*string, val types.Value, n int) ([]*string, error) {
+	if n <= 0 {
+		return cells, nil
+	}
+
+	if l, ok := val.(types.List); ok && l.Len() == uint64(n) {
+		err := l.IterAll(ctx, func(v types.Value, _ uint64) error {
+			str, err := formatValue(ctx, v)
+			if err != nil {
+				return err
+			}
+
+			cells = append(cells, str)
+			return nil
+		})
+
+		return cells, err
+	}
+
+	str, err := formatValue(ctx, val)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	return csvw.write(colValStrs)
+	cells = append(cells, str)
+	for i := 1; i < n; i++ {
+		cells = append(cells, nil)
+	}
+
+	return cells, nil
 }
 
-// Close should flush all writes, release resources being held
+// Close writes the summary row, if the CSVFileInfo has a SummaryLabel, then flushes as many buffered rows as it can
+// to the underlying writer and closes it. If the flush fails its error is returned, otherwise the error from closing
+// the underlying writer is. Use BytesFlushed to tell whether any output was written.
 func (csvw *CSVWriter) Close(ctx context.Context) error {
 	if csvw.wr != nil {
-		_ = csvw.wr.Flush()
+		if csvw.flusher != nil {
+			csvw.flusher.stop()
+		}
+
+		var errFl error
+		if csvw.summary != nil {
+			errFl = csvw.write(csvw.summary.record(csvw.order))
+		}
+
+		if err := csvw.wr.Flush(); errFl == nil {
+			errFl = err
+		}
+
 		errCl := csvw.closer.Close()
 		csvw.wr = nil
-		return errCl
+
+		err := errFl
+		if err == nil {
+			err = errCl
+		}
+
+		if csvw.atomic != nil {
+			return csvw.atomic.commit(err)
+		}
+
+		return err
 	} else {
 		return errors.New("Already closed.")
 	}
 }
 
+// Abort closes the writer without completing its output. For writers created by OpenAtomicCSVWriter, nothing is
+// written to the destination path. Other writers are closed as by Close.
+func (csvw *CSVWriter) Abort(ctx context.Context) error {
+	if csvw.atomic == nil {
+		return csvw.Close(ctx)
+	}
+
+	if csvw.wr == nil {
+		return errors.New("Already closed.")
+	}
+
+	if csvw.flusher != nil {
+		csvw.flusher.stop()
+	}
+
+	csvw.wr = nil
+	errCl := csvw.closer.Close()
+	errDel := csvw.atomic.fs.DeleteFile(csvw.atomic.tempPath)
+
+	if errCl != nil {
+		return errCl
+	}
+	return errDel
+}
+
+// BytesFlushed returns the number of bytes that have been written to the underlying writer. After a failed write
+// or Close, a non-zero count means that incomplete output was written. For writers created by OpenAtomicCSVWriter,
+// nothing reaches the destination path until the writer is closed successfully.
+func (csvw *CSVWriter) BytesFlushed() int64 {
+	if csvw.atomic != nil && !csvw.atomic.committed {
+		return 0
+	}
+	return atomic.LoadInt64(&csvw.cw.n)
+}
+
+// countingWriter counts the bytes written to |w|. The count is updated atomically, as rows can be flushed on the
+// goroutine of a timed flush.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	atomic.AddInt64(&cw.n, int64(n))
+	return n, err
+}
+
 func (csvw *CSVWriter) write(record []*string) error {
 	return WriteCSVRow(csvw.wr, record, csvw.info.Delim, csvw.useCRLF)
 }
@@ -16,7 +16,13 @@ package csv
 
 import (
 	"context"
+	"errors"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/dolthub/dolt/go/libraries/doltcore/row"
 	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
@@ -134,3 +140,383 @@ Andy Anderson|27|
 		t.Errorf(`%s != %s`, results, expected)
 	}
 }
+
+func TestWriterExpandedLists(t *testing.T) {
+	const root = "/"
+	const path = "/file.csv"
+	const expected = `name,coord_0,coord_1,coord_2
+origin,0,0,0
+point,1,-2,3
+short,"[
+  1,
+  2,
+]",,
+missing,,,
+`
+	ctx := context.Background()
+	vrw := types.NewMemoryValueStore()
+	mustList := func(vals ...types.Value) types.List {
+		l, err := types.NewList(ctx, vrw, vals...)
+		require.NoError(t, err)
+		return l
+	}
+
+	cols, err := schema.NewColCollection(
+		schema.Column{Name: "name", Tag: 0, Kind: types.StringKind, IsPartOfPK: true},
+		schema.Column{Name: "coord", Tag: 1, Kind: types.ListKind},
+	)
+	require.NoError(t, err)
+	sch := schema.MustSchemaFromCols(cols)
+
+	rows := []row.Row{
+		mustRow(row.New(types.Format_7_18, sch, row.TaggedValues{
+			0: types.String("origin"),
+			1: mustList(types.Int(0), types.Int(0), types.Int(0))})),
+		mustRow(row.New(types.Format_7_18, sch, row.TaggedValues{
+			0: types.String("point"),
+			1: mustList(types.Int(1), types.Int(-2), types.Int(3))})),
+		// variable length lists fall back to a single nested cell
+		mustRow(row.New(types.Format_7_18, sch, row.TaggedValues{
+			0: types.String("short"),
+			1: mustList(types.Int(1), types.Int(2))})),
+		mustRow(row.New(types.Format_7_18, sch, row.TaggedValues{
+			0: types.String("missing")})),
+	}
+
+	info := NewCSVInfo().SetExpandedLists(map[string]int{"coord": 3})
+
+	fs := filesys.NewInMemFS(nil, nil, root)
+	csvWr, err := OpenCSVWriter(path, fs, sch, info)
+	require.NoError(t, err)
+
+	writeToCSV(csvWr, rows, t)
+
+	results, err := fs.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, expected, string(results))
+}
+
+func TestWriterVirtualColumns(t *testing.T) {
+	const root = "/"
+	const path = "/file.csv"
+	const expected = `first,last,full_name
+Bill,Billerson,Bill Billerson
+Rob,,Rob
+`
+	cols, err := schema.NewColCollection(
+		schema.NewColumn("first", 0, types.StringKind, true),
+		schema.NewColumn("last", 1, types.StringKind, false),
+	)
+	require.NoError(t, err)
+	sch := schema.MustSchemaFromCols(cols)
+
+	rows := []row.Row{
+		mustRow(row.New(types.Format_7_18, sch, row.TaggedValues{0: types.String("Bill"), 1: types.String("Billerson")})),
+		mustRow(row.New(types.Format_7_18, sch, row.TaggedValues{0: types.String("Rob")})),
+	}
+
+	fullName := VirtualColumn{
+		Name: "full_name",
+		Value: func(ctx context.Context, r row.Row) (string, error) {
+			names := make([]string, 0, 2)
+			for _, tag := range []uint64{0, 1} {
+				if v, ok := r.GetColVal(tag); ok && !types.IsNull(v) {
+					names = append(names, string(v.(types.String)))
+				}
+			}
+			return strings.Join(names, " "), nil
+		},
+	}
+	info := NewCSVInfo().SetVirtualColumns(fullName)
+
+	fs := filesys.NewInMemFS(nil, nil, root)
+	csvWr, err := OpenCSVWriter(path, fs, sch, info)
+	require.NoError(t, err)
+
+	writeToCSV(csvWr, rows, t)
+
+	results, err := fs.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, expected, string(results))
+}
+
+func TestWriterColumnFormatters(t *testing.T) {
+	const root = "/"
+	const path = "/file.csv"
+	const expected = `name,created
+Bill,2020-06-01T12:00:00Z
+Rob,
+`
+	cols, err := schema.NewColCollection(
+		schema.NewColumn("name", 0, types.StringKind, true),
+		schema.NewColumn("created", 1, types.FloatKind, false),
+	)
+	require.NoError(t, err)
+	sch := schema.MustSchemaFromCols(cols)
+
+	created := time.Date(2020, 6, 1, 12, 0, 0, 0, time.UTC)
+	rows := []row.Row{
+		mustRow(row.New(types.Format_7_18, sch, row.TaggedValues{0: types.String("Bill"), 1: types.Float(created.Unix())})),
+		mustRow(row.New(types.Format_7_18, sch, row.TaggedValues{0: types.String("Rob")})),
+	}
+
+	epochToISO := func(val types.Value) (string, error) {
+		return time.Unix(int64(val.(types.Float)), 0).UTC().Format(time.RFC3339), nil
+	}
+	info := NewCSVInfo().SetColumnFormatters(map[string]ValueFormatter{"created": epochToISO})
+
+	fs := filesys.NewInMemFS(nil, nil, root)
+	csvWr, err := OpenCSVWriter(path, fs, sch, info)
+	require.NoError(t, err)
+
+	writeToCSV(csvWr, rows, t)
+
+	results, err := fs.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, expected, string(results))
+
+	errFormat := errors.New("can't format")
+	info = NewCSVInfo().SetColumnFormatters(map[string]ValueFormatter{"name": func(types.Value) (string, error) {
+		return "", errFormat
+	}})
+	csvWr, err = OpenCSVWriter(path, fs, sch, info)
+	require.NoError(t, err)
+	defer csvWr.Close(context.Background())
+	assert.Equal(t, errFormat, csvWr.WriteRow(context.Background(), rows[0]))
+}
+
+func TestWriterTransforms(t *testing.T) {
+	const root = "/"
+	const path = "/file.csv"
+	const expected = `name,age,title
+BIL***,32,SENIOR DUFUS
+ROB***,25,DUFUS
+JOH***,21,""
+AND***,27,
+`
+	upper := func(fields []string) ([]string, error) {
+		for i := range fields {
+			fields[i] = strings.ToUpper(fields[i])
+		}
+		return fields, nil
+	}
+	maskName := func(fields []string) ([]string, error) {
+		fields[nameColTag] = fields[nameColTag][:3] + "***"
+		return fields, nil
+	}
+	info := NewCSVInfo().SetTransforms(upper, maskName)
+
+	fs := filesys.NewInMemFS(nil, nil, root)
+	csvWr, err := OpenCSVWriter(path, fs, outSch, info)
+	require.NoError(t, err)
+
+	writeToCSV(csvWr, getSampleRows(), t)
+
+	results, err := fs.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, expected, string(results))
+
+	errTransform := errors.New("can't transform")
+	info = NewCSVInfo().SetTransforms(upper, func([]string) ([]string, error) {
+		return nil, errTransform
+	})
+	csvWr, err = OpenCSVWriter(path, fs, outSch, info)
+	require.NoError(t, err)
+	defer csvWr.Close(context.Background())
+	assert.Equal(t, errTransform, csvWr.WriteRow(context.Background(), getSampleRows()[0]))
+}
+
+func TestWriterTypedHeader(t *testing.T) {
+	const root = "/"
+	const path = "/file.csv"
+	const expected = `id:int,name:string,score:float,active:bool,untyped:string
+1,Bill,2.5,true,
+`
+	cols, err := schema.NewColCollection(
+		schema.NewColumn("id", 0, types.IntKind, true),
+		schema.NewColumn("name", 1, types.StringKind, false),
+		schema.NewColumn("score", 2, types.FloatKind, false),
+		schema.NewColumn("active", 3, types.BoolKind, false),
+		schema.NewColumn("untyped", 4, types.NullKind, false),
+	)
+	require.NoError(t, err)
+	sch := schema.MustSchemaFromCols(cols)
+
+	rows := []row.Row{
+		mustRow(row.New(types.Format_7_18, sch, row.TaggedValues{
+			0: types.Int(1),
+			1: types.String("Bill"),
+			2: types.Float(2.5),
+			3: types.Bool(true),
+		})),
+	}
+
+	fs := filesys.NewInMemFS(nil, nil, root)
+	csvWr, err := OpenCSVWriter(path, fs, sch, NewCSVInfo().SetTypedHeader(true))
+	require.NoError(t, err)
+
+	writeToCSV(csvWr, rows, t)
+
+	results, err := fs.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, expected, string(results))
+}
+
+// failingWriter accepts up to |limit| bytes and then fails
+type failingWriter struct {
+	limit   int
+	written int
+}
+
+func (fw *failingWriter) Write(p []byte) (int, error) {
+	if fw.written+len(p) > fw.limit {
+		n := fw.limit - fw.written
+		fw.written = fw.limit
+		return n, errors.New("write failed")
+	}
+	fw.written += len(p)
+	return len(p), nil
+}
+
+func (fw *failingWriter) Close() error {
+	return nil
+}
+
+func TestWriterFailedFlush(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name       string
+		limit      int
+		expFlushed int64
+	}{
+		{"clean failure", 0, 0},
+		{"partial output", 20, 20},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			csvWr, err := NewCSVWriter(&failingWriter{limit: test.limit}, outSch, NewCSVInfo())
+			require.NoError(t, err)
+
+			for _, r := range getSampleRows() {
+				require.NoError(t, csvWr.WriteRow(ctx, r))
+			}
+			assert.Equal(t, int64(0), csvWr.BytesFlushed())
+
+			assert.Error(t, csvWr.Close(ctx))
+			assert.Equal(t, test.expFlushed, csvWr.BytesFlushed())
+		})
+	}
+}
+
+func TestAtomicWriter(t *testing.T) {
+	const root = "/"
+	const path = "/out/file.csv"
+	const expected = `name,age,title
+Bill Billerson,32,Senior Dufus
+Rob Robertson,25,Dufus
+John Johnson,21,""
+Andy Anderson,27,
+`
+	ctx := context.Background()
+
+	listFiles := func(fs filesys.Filesys) []string {
+		var files []string
+		err := fs.Iter(root, true, func(path string, size int64, isDir bool) (stop bool) {
+			if !isDir {
+				files = append(files, path)
+			}
+			return false
+		})
+		require.NoError(t, err)
+		return files
+	}
+
+	t.Run("aborted", func(t *testing.T) {
+		fs := filesys.NewInMemFS(nil, nil, root)
+		csvWr, err := OpenAtomicCSVWriter(path, fs, outSch, NewCSVInfo())
+		require.NoError(t, err)
+
+		for _, r := range getSampleRows() {
+			require.NoError(t, csvWr.WriteRow(ctx, r))
+		}
+		require.NoError(t, csvWr.Abort(ctx))
+
+		assert.Empty(t, listFiles(fs))
+		assert.Equal(t, int64(0), csvWr.BytesFlushed())
+	})
+
+	t.Run("closed", func(t *testing.T) {
+		fs := filesys.NewInMemFS(nil, nil, root)
+		csvWr, err := OpenAtomicCSVWriter(path, fs, outSch, NewCSVInfo())
+		require.NoError(t, err)
+
+		for _, r := range getSampleRows() {
+			require.NoError(t, csvWr.WriteRow(ctx, r))
+		}
+		exists, _ := fs.Exists(path)
+		assert.False(t, exists, "output should not exist until the writer is closed")
+
+		require.NoError(t, csvWr.Close(ctx))
+
+		assert.Equal(t, []string{path}, listFiles(fs))
+		results, err := fs.ReadFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, expected, string(results))
+		assert.Equal(t, int64(len(expected)), csvWr.BytesFlushed())
+	})
+}
+
+func TestWriterInvalidUTF8(t *testing.T) {
+	const root = "/"
+	const path = "/file.csv"
+
+	cols, err := schema.NewColCollection(
+		schema.NewColumn("id", 0, types.IntKind, true),
+		schema.NewColumn("legacy", 1, types.StringKind, false),
+	)
+	require.NoError(t, err)
+	sch := schema.MustSchemaFromCols(cols)
+
+	rows := []row.Row{
+		mustRow(row.New(types.Format_7_18, sch, row.TaggedValues{0: types.Int(1), 1: types.String("caf\xe9 \xff\xfe")})),
+		mustRow(row.New(types.Format_7_18, sch, row.TaggedValues{0: types.Int(2), 1: types.String("café")})),
+	}
+
+	tests := []struct {
+		name     string
+		policy   InvalidUTF8Policy
+		expected string
+	}{
+		{"passthrough", InvalidUTF8Passthrough, "id,legacy\n1,caf\xe9 \xff\xfe\n2,café\n"},
+		{"replace", InvalidUTF8Replace, "id,legacy\n1,caf� ��\n2,café\n"},
+		{"escape hex", InvalidUTF8EscapeHex, "id,legacy\n1,caf\\xe9 \\xff\\xfe\n2,café\n"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fs := filesys.NewInMemFS(nil, nil, root)
+			csvWr, err := OpenCSVWriter(path, fs, sch, NewCSVInfo().SetInvalidUTF8(test.policy))
+			require.NoError(t, err)
+
+			writeToCSV(csvWr, rows, t)
+
+			results, err := fs.ReadFile(path)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, string(results))
+		})
+	}
+
+	t.Run("error", func(t *testing.T) {
+		fs := filesys.NewInMemFS(nil, nil, root)
+		csvWr, err := OpenCSVWriter(path, fs, sch, NewCSVInfo().SetInvalidUTF8(InvalidUTF8Error))
+		require.NoError(t, err)
+		defer csvWr.Close(context.Background())
+
+		err = csvWr.WriteRow(context.Background(), rows[0])
+		assert.True(t, errors.Is(err, ErrInvalidUTF8))
+		assert.Contains(t, err.Error(), "legacy")
+		assert.NoError(t, csvWr.WriteRow(context.Background(), rows[1]))
+	})
+}
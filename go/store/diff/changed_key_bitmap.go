@@ -0,0 +1,72 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"context"
+	"math"
+
+	"github.com/RoaringBitmap/roaring"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// ChangedKeyBitmap diffs |from| and |to| and returns a bitmap with a bit set for each key that was added, removed
+// or modified. |keyToIndex| maps each changed key to the position of its bit, and returns false for keys that have
+// no position, such as non-integer keys, which are left out of the bitmap. Positions must fit in 32 bits, and keys
+// mapped to larger positions are also left out. This is more compact than a list of differences when keys are dense
+// integers. IntegerKeyIndex maps integer keys to themselves.
+func ChangedKeyBitmap(ctx context.Context, from, to types.Map, keyToIndex func(types.Value) (uint64, bool)) (*roaring.Bitmap, error) {
+	eg, ctx := errgroup.WithContext(ctx)
+	changes := make(chan types.ValueChanged, 128)
+
+	eg.Go(func() error {
+		defer close(changes)
+		return to.Diff(ctx, from, changes)
+	})
+
+	bm := roaring.New()
+	eg.Go(func() error {
+		for change := range changes {
+			if idx, ok := keyToIndex(change.Key); ok && idx <= math.MaxUint32 {
+				bm.Add(uint32(idx))
+			}
+		}
+		return nil
+	})
+
+	if err := eg.Wait(); err != nil {
+		return nil, err
+	}
+
+	return bm, nil
+}
+
+// IntegerKeyIndex is a key mapping for ChangedKeyBitmap that maps non-negative Int and Uint keys to their values.
+// Other keys have no position.
+func IntegerKeyIndex(key types.Value) (uint64, bool) {
+	switch k := key.(type) {
+	case types.Int:
+		if k < 0 {
+			return 0, false
+		}
+		return uint64(k), true
+	case types.Uint:
+		return uint64(k), true
+	default:
+		return 0, false
+	}
+}
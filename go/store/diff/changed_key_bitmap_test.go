@@ -0,0 +1,100 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func TestChangedKeyBitmap(t *testing.T) {
+	ctx := context.Background()
+	vs := newTestValueStore()
+	defer vs.Close()
+
+	const numKeys = 10000
+	kvs := make([]types.Value, 0, 2*numKeys)
+	for i := 0; i < numKeys; i++ {
+		kvs = append(kvs, types.Int(i), types.String("v"))
+	}
+	from, err := types.NewMap(ctx, vs, kvs...)
+	require.NoError(t, err)
+
+	to, err := from.Edit().
+		Set(types.Int(10), types.String("modified")).
+		Set(types.Int(5000), types.String("modified")).
+		Remove(types.Int(9999)).
+		Set(types.Int(numKeys), types.String("added")).
+		Set(types.Int(numKeys+1), types.String("added")).
+		// keys without a position are left out
+		Set(types.Int(-5), types.String("added")).
+		Set(types.String("key"), types.String("added")).
+		Map(ctx)
+	require.NoError(t, err)
+
+	bm, err := ChangedKeyBitmap(ctx, from, to, IntegerKeyIndex)
+	require.NoError(t, err)
+	assert.Equal(t, []uint32{10, 5000, 9999, numKeys, numKeys + 1}, bm.ToArray())
+
+	t.Run("custom mapping", func(t *testing.T) {
+		// positions are offsets from key 1000, and keys before it or too far past it are out of range
+		offset := func(key types.Value) (uint64, bool) {
+			idx, ok := IntegerKeyIndex(key)
+			if !ok || idx < 1000 {
+				return 0, false
+			}
+			return (idx - 1000) << 20, true
+		}
+
+		bm, err := ChangedKeyBitmap(ctx, from, to, offset)
+		require.NoError(t, err)
+		assert.Equal(t, []uint32{4000 << 20}, bm.ToArray())
+	})
+
+	t.Run("no changes", func(t *testing.T) {
+		bm, err := ChangedKeyBitmap(ctx, from, from, IntegerKeyIndex)
+		require.NoError(t, err)
+		assert.True(t, bm.IsEmpty())
+	})
+
+	t.Run("cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(ctx)
+		cancel()
+		_, err := ChangedKeyBitmap(ctx, from, to, IntegerKeyIndex)
+		assert.Error(t, err)
+	})
+}
+
+func TestIntegerKeyIndex(t *testing.T) {
+	idx, ok := IntegerKeyIndex(types.Int(7))
+	assert.True(t, ok)
+	assert.Equal(t, uint64(7), idx)
+
+	idx, ok = IntegerKeyIndex(types.Uint(8))
+	assert.True(t, ok)
+	assert.Equal(t, uint64(8), idx)
+
+	_, ok = IntegerKeyIndex(types.Int(-1))
+	assert.False(t, ok)
+	_, ok = IntegerKeyIndex(types.Float(1))
+	assert.False(t, ok)
+	_, ok = IntegerKeyIndex(types.String("1"))
+	assert.False(t, ok)
+}
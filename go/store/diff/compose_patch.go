@@ -0,0 +1,148 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// ErrIncompatiblePatches is returned by ComposePatches when the second patch can't follow the first, such as when a
+// value removed by the first patch is modified by the second.
+var ErrIncompatiblePatches = errors.New("patches can not be composed")
+
+// ComposePatches combines |ab|, a patch from A to B, and |bc|, a patch from B to C, into a single patch from A to C
+// without diffing A against C. Differences are matched by their paths. A value added then modified is added with
+// its newest value, and one added then removed has no difference. A value modified then modified, or removed then
+// added, is modified from its oldest to its newest value, and has no difference if those are equal. A value modified
+// then removed is removed with its oldest value.
+// Any other pairing returns ErrIncompatiblePatches. Because differences are matched by path, the patches should
+// come from diffs that identify values by key, such as map and struct diffs, rather than list diffs whose indexes
+// shift as values are inserted and removed. Neither patch is modified, and the result is sorted by PatchSort.
+func ComposePatches(nbf *types.NomsBinFormat, ab, bc Patch) (Patch, error) {
+	ab, err := sortedPatchCopy(nbf, ab)
+	if err != nil {
+		return nil, err
+	}
+
+	bc, err = sortedPatchCopy(nbf, bc)
+	if err != nil {
+		return nil, err
+	}
+
+	composed := make(Patch, 0, len(ab)+len(bc))
+	i, j := 0, 0
+	for i < len(ab) && j < len(bc) {
+		if ab[i].Path.Equals(bc[j].Path) {
+			dif, ok, err := composeDifferences(ab[i], bc[j])
+			if err != nil {
+				return nil, err
+			}
+
+			if ok {
+				composed = append(composed, dif)
+			}
+
+			i++
+			j++
+			continue
+		}
+
+		isLess, err := pathIsLess(nbf, ab[i].Path, bc[j].Path)
+		if err != nil {
+			return nil, err
+		}
+
+		if isLess {
+			composed = append(composed, ab[i])
+			i++
+		} else {
+			composed = append(composed, bc[j])
+			j++
+		}
+	}
+
+	composed = append(composed, ab[i:]...)
+	composed = append(composed, bc[j:]...)
+
+	return composed, nil
+}
+
+// sortedPatchCopy returns a copy of |patch| sorted by PatchSort, and an error if it has more than one difference for
+// a path.
+func sortedPatchCopy(nbf *types.NomsBinFormat, patch Patch) (Patch, error) {
+	sorted := make(Patch, len(patch))
+	copy(sorted, patch)
+
+	err := types.SortWithErroringLess(PatchSort{sorted, nbf})
+	if err != nil {
+		return nil, err
+	}
+
+	for i := 1; i < len(sorted); i++ {
+		if sorted[i].Path.Equals(sorted[i-1].Path) {
+			return nil, fmt.Errorf("%w: more than one difference for %s", ErrIncompatiblePatches, sorted[i].Path.String())
+		}
+	}
+
+	return sorted, nil
+}
+
+// composeDifferences combines |ab| and |bc|, two differences for the same path, returning false if they cancel out.
+func composeDifferences(ab, bc Difference) (Difference, bool, error) {
+	switch {
+	case ab.ChangeType == types.DiffChangeAdded && bc.ChangeType == types.DiffChangeModified:
+		dif := bc
+		dif.ChangeType = types.DiffChangeAdded
+		dif.OldValue = nil
+		return dif, true, nil
+
+	case ab.ChangeType == types.DiffChangeAdded && bc.ChangeType == types.DiffChangeRemoved:
+		return Difference{}, false, nil
+
+	case ab.ChangeType == types.DiffChangeModified && bc.ChangeType == types.DiffChangeModified,
+		ab.ChangeType == types.DiffChangeRemoved && bc.ChangeType == types.DiffChangeAdded:
+		if ab.OldValue != nil && bc.NewValue != nil && ab.OldValue.Equals(bc.NewValue) {
+			return Difference{}, false, nil
+		}
+
+		dif := bc
+		dif.ChangeType = types.DiffChangeModified
+		dif.OldValue = ab.OldValue
+		return dif, true, nil
+
+	case ab.ChangeType == types.DiffChangeModified && bc.ChangeType == types.DiffChangeRemoved:
+		dif := bc
+		dif.OldValue = ab.OldValue
+		return dif, true, nil
+	}
+
+	return Difference{}, false, fmt.Errorf("%w: %s %s after %s", ErrIncompatiblePatches, ab.Path.String(),
+		changeTypeName(bc.ChangeType), changeTypeName(ab.ChangeType))
+}
+
+func changeTypeName(ct types.DiffChangeType) string {
+	switch ct {
+	case types.DiffChangeAdded:
+		return "added"
+	case types.DiffChangeRemoved:
+		return "removed"
+	case types.DiffChangeModified:
+		return "modified"
+	}
+	return "changed"
+}
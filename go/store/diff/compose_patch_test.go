@@ -0,0 +1,106 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func TestComposePatches(t *testing.T) {
+	assert := assert.New(t)
+
+	path := mustParsePath(assert, `["k"]`)
+	added := func(v string) Difference {
+		return Difference{Path: path, ChangeType: types.DiffChangeAdded, NewValue: types.String(v)}
+	}
+	removed := func(v string) Difference {
+		return Difference{Path: path, ChangeType: types.DiffChangeRemoved, OldValue: types.String(v)}
+	}
+	modified := func(from, to string) Difference {
+		return Difference{Path: path, ChangeType: types.DiffChangeModified, OldValue: types.String(from), NewValue: types.String(to)}
+	}
+
+	tests := []struct {
+		name     string
+		ab       Difference
+		bc       Difference
+		expected Patch
+	}{
+		{"add+modify", added("b"), modified("b", "c"), Patch{added("c")}},
+		{"add+remove", added("b"), removed("b"), Patch{}},
+		{"modify+modify", modified("a", "b"), modified("b", "c"), Patch{modified("a", "c")}},
+		{"modify+modify back", modified("a", "b"), modified("b", "a"), Patch{}},
+		{"modify+remove", modified("a", "b"), removed("b"), Patch{removed("a")}},
+		{"remove+add", removed("a"), added("c"), Patch{modified("a", "c")}},
+		{"remove+add back", removed("a"), added("a"), Patch{}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			composed, err := ComposePatches(types.Format_Default, Patch{test.ab}, Patch{test.bc})
+			require.NoError(t, err)
+			require.Equal(t, test.expected, composed)
+		})
+	}
+
+	incompatible := []struct {
+		name string
+		ab   Difference
+		bc   Difference
+	}{
+		{"add+add", added("b"), added("c")},
+		{"modify+add", modified("a", "b"), added("c")},
+		{"remove+modify", removed("a"), modified("b", "c")},
+		{"remove+remove", removed("a"), removed("b")},
+	}
+
+	for _, test := range incompatible {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := ComposePatches(types.Format_Default, Patch{test.ab}, Patch{test.bc})
+			require.True(t, errors.Is(err, ErrIncompatiblePatches))
+		})
+	}
+}
+
+func TestComposePatchesDisjointPaths(t *testing.T) {
+	assert := assert.New(t)
+
+	dif := func(p string, ct types.DiffChangeType) Difference {
+		return Difference{Path: mustParsePath(assert, p), ChangeType: ct, NewValue: types.String(p)}
+	}
+
+	ab := Patch{dif(`["c"]`, types.DiffChangeAdded), dif(`["a"]`, types.DiffChangeModified)}
+	bc := Patch{dif(`["d"]`, types.DiffChangeRemoved), dif(`["b"]`, types.DiffChangeAdded)}
+	composed, err := ComposePatches(types.Format_Default, ab, bc)
+	assert.NoError(err)
+	assert.Equal(Patch{
+		dif(`["a"]`, types.DiffChangeModified),
+		dif(`["b"]`, types.DiffChangeAdded),
+		dif(`["c"]`, types.DiffChangeAdded),
+		dif(`["d"]`, types.DiffChangeRemoved),
+	}, composed)
+
+	// the inputs aren't reordered
+	assert.Equal(dif(`["c"]`, types.DiffChangeAdded), ab[0])
+
+	_, err = ComposePatches(types.Format_Default, Patch{ab[0], ab[0]}, bc)
+	assert.True(errors.Is(err, ErrIncompatiblePatches))
+}
@@ -28,6 +28,7 @@ import (
 
 	"golang.org/x/sync/errgroup"
 
+	"github.com/dolthub/dolt/go/store/hash"
 	"github.com/dolthub/dolt/go/store/types"
 )
 
@@ -52,14 +53,50 @@ type Difference struct {
 	NewKeyValue types.Value
 	// KeyValue holds the key associated with a changed map value
 	KeyValue types.Value
+	// RootKeyValue holds the key of the top level map entry beneath which the
+	// change occurred. For changes to top level entries it is equal to KeyValue.
+	RootKeyValue types.Value
 }
 
 func (dif Difference) IsEmpty() bool {
 	return dif.Path == nil && dif.OldValue == nil && dif.NewValue == nil
 }
 
+// Depth returns the number of path parts between the root of the diff and the
+// changed value. Changes to top level entries have a depth of 1.
+func (dif Difference) Depth() int {
+	return len(dif.Path)
+}
+
+// NestedPath returns the portion of Path beneath the top level entry, which
+// is empty for changes to top level entries.
+func (dif Difference) NestedPath() types.Path {
+	if len(dif.Path) <= 1 {
+		return nil
+	}
+	return dif.Path[1:]
+}
+
 type ShouldDescFunc func(v1, v2 types.Value) bool
 
+// DescendDecision is what a HashedDescFunc decides to do with a pair of differing values.
+type DescendDecision int
+
+const (
+	// Descend diffs the contents of the values.
+	Descend DescendDecision = iota
+	// Report reports the values as a single modification without diffing their contents.
+	Report
+	// SkipEqual treats the values as equal, neither diffing their contents nor reporting them.
+	SkipEqual
+)
+
+// HashedDescFunc decides what to do with two differing values, given the values and their hashes. It lets callers
+// that can tell two subtrees are equivalent from their hashes, such as by looking them up in a cache of previously
+// compared subtrees, skip them without diffing their contents. Values that ShouldDescend wouldn't descend into are
+// reported rather than descended into, whatever the HashedDescFunc decides.
+type HashedDescFunc func(v1, v2 types.Value, h1, h2 hash.Hash) DescendDecision
+
 // differ is used internally to hold information necessary for diffing two graphs.
 type differ struct {
 	// Channel used to send Difference objects back to caller
@@ -68,6 +105,12 @@ type differ struct {
 	leftRight bool
 
 	shouldDescend ShouldDescFunc
+	// hashedDescend, if non-nil, is used in place of shouldDescend
+	hashedDescend HashedDescFunc
+	nbf           *types.NomsBinFormat
+
+	// rootKey is the key of the top level entry being descended into, if any
+	rootKey types.Value
 
 	eg         *errgroup.Group
 	asyncPanic *atomic.Value
@@ -118,17 +161,75 @@ func Diff(ctx context.Context, v1, v2 types.Value, dChan chan<- Difference, left
 		eg:         eg,
 		asyncPanic: new(atomic.Value),
 	}
-	if !v1.Equals(v2) {
-		if !d.shouldDescend(v1, v2) {
-			return d.sendDiff(ctx, Difference{Path: nil, ChangeType: types.DiffChangeModified, OldValue: v1, NewValue: v2})
-		} else {
-			d.GoCatchPanic(func() error {
-				return d.diff(ctx, nil, v1, v2)
-			})
-			return d.Wait()
+	return d.diffRoots(ctx, v1, v2)
+}
+
+// DiffWithHashedDescFunc is Diff, with each pair of differing values that could be descended into passed to
+// |descFunc| along with their hashes in |nbf| to decide whether to descend into them, report them as modified,
+// or skip them as equal.
+func DiffWithHashedDescFunc(ctx context.Context, nbf *types.NomsBinFormat, v1, v2 types.Value, dChan chan<- Difference, leftRight bool, descFunc HashedDescFunc) error {
+	eg, ctx := errgroup.WithContext(ctx)
+	d := differ{
+		diffChan:      dChan,
+		leftRight:     leftRight,
+		hashedDescend: descFunc,
+		nbf:           nbf,
+
+		eg:         eg,
+		asyncPanic: new(atomic.Value),
+	}
+	return d.diffRoots(ctx, v1, v2)
+}
+
+func (d differ) diffRoots(ctx context.Context, v1, v2 types.Value) error {
+	if v1.Equals(v2) {
+		return nil
+	}
+
+	decision, err := d.decide(v1, v2)
+	if err != nil {
+		return err
+	}
+
+	switch decision {
+	case SkipEqual:
+		return nil
+	case Report:
+		return d.sendDiff(ctx, Difference{Path: nil, ChangeType: types.DiffChangeModified, OldValue: v1, NewValue: v2})
+	default:
+		d.GoCatchPanic(func() error {
+			return d.diff(ctx, nil, v1, v2)
+		})
+		return d.Wait()
+	}
+}
+
+// decide returns what to do with the differing values |v1| and |v2|.
+func (d differ) decide(v1, v2 types.Value) (DescendDecision, error) {
+	if d.hashedDescend == nil {
+		if d.shouldDescend(v1, v2) {
+			return Descend, nil
 		}
+		return Report, nil
 	}
-	return nil
+
+	h1, err := v1.Hash(d.nbf)
+	if err != nil {
+		return Descend, err
+	}
+
+	h2, err := v2.Hash(d.nbf)
+	if err != nil {
+		return Descend, err
+	}
+
+	decision := d.hashedDescend(v1, v2, h1, h2)
+	if decision == Descend && !ShouldDescend(v1, v2) {
+		// values that can't be descended into are reported
+		return Report, nil
+	}
+
+	return decision, nil
 }
 
 func (d differ) diff(ctx context.Context, p types.Path, v1, v2 types.Value) error {
@@ -193,7 +294,14 @@ func (d differ) diffLists(ctx context.Context, p types.Path, v1, v2 types.List)
 					return err
 				}
 
-				if d.shouldDescend(lastEl, newEl) {
+				decision, err := d.decide(lastEl, newEl)
+				if err != nil {
+					return err
+				}
+
+				if decision == SkipEqual {
+					continue
+				} else if decision == Descend {
 					idx := types.Float(splice.SpAt + i)
 					err := d.diff(ctx, append(p, types.NewIndexPath(idx)), lastEl, newEl)
 					if err != nil {
@@ -399,8 +507,20 @@ func (d differ) diffOrdered(ctx context.Context, p types.Path, ppf pathPartFunc,
 				return err
 			}
 
-			if d.shouldDescend(c1, c2) {
-				err = d.diff(ctx, p1, c1, c2)
+			decision, err := d.decide(c1, c2)
+			if err != nil {
+				return err
+			}
+
+			if decision == SkipEqual {
+				continue
+			} else if decision == Descend {
+				nested := d
+				if nested.rootKey == nil {
+					nested.rootKey = change.Key
+				}
+
+				err = nested.diff(ctx, p1, c1, c2)
 				if err != nil {
 					return err
 				}
@@ -426,6 +546,12 @@ func ShouldDescend(v1, v2 types.Value) bool {
 }
 
 func (d differ) sendDiff(ctx context.Context, dif Difference) error {
+	if d.rootKey != nil {
+		dif.RootKeyValue = d.rootKey
+	} else {
+		dif.RootKeyValue = dif.KeyValue
+	}
+
 	select {
 	case <-ctx.Done():
 		return ctx.Err()
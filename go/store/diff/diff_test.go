@@ -31,6 +31,7 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"github.com/dolthub/dolt/go/store/d"
+	"github.com/dolthub/dolt/go/store/hash"
 	"github.com/dolthub/dolt/go/store/types"
 	"github.com/dolthub/dolt/go/store/util/test"
 	"github.com/dolthub/dolt/go/store/util/writers"
@@ -555,3 +556,149 @@ func TestNomsDiffPrintRef(t *testing.T) {
 	tf(true)
 	tf(false)
 }
+
+func TestNomsDiffNestedPath(t *testing.T) {
+	assert := assert.New(t)
+
+	dChan := make(chan Difference)
+	var derr error
+	go func() {
+		defer close(dChan)
+		derr = Diff(context.Background(), mm3, mm3x, dChan, true, nil)
+	}()
+
+	var diffs []Difference
+	for d := range dChan {
+		diffs = append(diffs, d)
+	}
+	assert.NoError(derr)
+	assert.Len(diffs, 2)
+
+	top, nested := diffs[0], diffs[1]
+	if top.Depth() > nested.Depth() {
+		top, nested = nested, top
+	}
+
+	assert.Equal(1, top.Depth())
+	assert.Empty(top.NestedPath())
+	assert.Equal(types.String("m3"), top.RootKeyValue)
+	assert.Equal(top.KeyValue, top.RootKeyValue)
+
+	assert.Equal(2, nested.Depth())
+	assert.Equal(`["a1"]`, nested.NestedPath().String())
+	assert.Equal(types.String("m4"), nested.RootKeyValue)
+	assert.Equal(types.String("a1"), nested.KeyValue)
+}
+
+// knownEqual is a HashedDescFunc that skips pairs of values whose hashes it has been told are equivalent.
+type knownEqual map[[2]hash.Hash]bool
+
+func (ke knownEqual) descend(v1, v2 types.Value, h1, h2 hash.Hash) DescendDecision {
+	if ke[[2]hash.Hash{h1, h2}] {
+		return SkipEqual
+	}
+	return Descend
+}
+
+func pathsFromHashedDiff(v1, v2 types.Value, descFunc HashedDescFunc) ([]string, error) {
+	var derr error
+	dChan := make(chan Difference)
+	go func() {
+		defer close(dChan)
+		derr = DiffWithHashedDescFunc(context.Background(), types.Format_7_18, v1, v2, dChan, true, descFunc)
+	}()
+
+	var paths []string
+	for d := range dChan {
+		paths = append(paths, d.Path.String())
+	}
+
+	return paths, derr
+}
+
+func TestDiffWithHashedDescFunc(t *testing.T) {
+	assert := assert.New(t)
+
+	bb1 := createMap("b1", "b-one", "b2", "b-two")
+	bb1x := createMap("b1", "b-one", "b2", "b-two-diff")
+	m1 := createMap("a", aa1, "b", bb1, "c", "c-one")
+	m2 := createMap("a", aa1x, "b", bb1x, "c", "c-one-diff")
+
+	paths, err := pathsFromHashedDiff(m1, m2, knownEqual{}.descend)
+	assert.NoError(err)
+	assert.Equal([]string{`["a"]["a1"]`, `["b"]["b2"]`, `["c"]`}, paths)
+
+	ke := knownEqual{{mustHash(aa1), mustHash(aa1x)}: true}
+	paths, err = pathsFromHashedDiff(m1, m2, ke.descend)
+	assert.NoError(err)
+	assert.Equal([]string{`["b"]["b2"]`, `["c"]`}, paths)
+
+	// descend into the roots, and report the values beneath them without descending
+	report := func(v1, v2 types.Value, h1, h2 hash.Hash) DescendDecision {
+		if v1.Equals(m1) {
+			return Descend
+		}
+		return Report
+	}
+	paths, err = pathsFromHashedDiff(m1, m2, report)
+	assert.NoError(err)
+	assert.Equal([]string{`["a"]`, `["b"]`, `["c"]`}, paths)
+
+	// the roots themselves can be skipped
+	ke = knownEqual{{mustHash(m1), mustHash(m2)}: true}
+	paths, err = pathsFromHashedDiff(m1, m2, ke.descend)
+	assert.NoError(err)
+	assert.Empty(paths)
+}
+
+func BenchmarkDiffWithHashedDescFunc(b *testing.B) {
+	const numNested = 64
+	const nestedSize = 1024
+
+	ctx := context.Background()
+	vs := newTestValueStore()
+	defer vs.Close()
+
+	var kvs1, kvs2 []types.Value
+	ke := knownEqual{}
+	for i := 0; i < numNested; i++ {
+		var nkvs []types.Value
+		for j := 0; j < nestedSize; j++ {
+			nkvs = append(nkvs, types.Float(j), types.Float(j))
+		}
+		n1, err := types.NewMap(ctx, vs, nkvs...)
+		d.PanicIfError(err)
+		nkvs[len(nkvs)-1] = types.Float(-1)
+		n2, err := types.NewMap(ctx, vs, nkvs...)
+		d.PanicIfError(err)
+
+		kvs1 = append(kvs1, types.Float(i), n1)
+		kvs2 = append(kvs2, types.Float(i), n2)
+		ke[[2]hash.Hash{mustHash(n1), mustHash(n2)}] = true
+	}
+
+	m1, err := types.NewMap(ctx, vs, kvs1...)
+	d.PanicIfError(err)
+	m2, err := types.NewMap(ctx, vs, kvs2...)
+	d.PanicIfError(err)
+
+	b.Run("descend", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_, err := pathsFromHashedDiff(m1, m2, knownEqual{}.descend)
+			d.PanicIfError(err)
+		}
+	})
+
+	b.Run("skip known equal", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_, err := pathsFromHashedDiff(m1, m2, ke.descend)
+			d.PanicIfError(err)
+		}
+	})
+}
+
+func mustHash(v types.Value) hash.Hash {
+	h, err := v.Hash(types.Format_7_18)
+	d.PanicIfError(err)
+	return h
+}
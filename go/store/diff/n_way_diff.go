@@ -0,0 +1,142 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// NWayRow is a key and its values in each of the maps compared by NWayDiff.
+type NWayRow struct {
+	// Key is the key of the row
+	Key types.Value
+	// Values holds each map's value for Key, in the order the maps were given, or nil for maps without it
+	Values []types.Value
+}
+
+// Present returns whether the |i|th map has the row's key.
+func (r NWayRow) Present(i int) bool {
+	return r.Values[i] != nil
+}
+
+// Groups partitions the maps that have the row's key by value. Each group holds the indexes of the maps with equal
+// values, in ascending order, and groups are ordered by their first index.
+func (r NWayRow) Groups() [][]int {
+	var groups [][]int
+	for i, v := range r.Values {
+		if v == nil {
+			continue
+		}
+
+		found := false
+		for j, g := range groups {
+			if r.Values[g[0]].Equals(v) {
+				groups[j] = append(g, i)
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			groups = append(groups, []int{i})
+		}
+	}
+
+	return groups
+}
+
+// Agree returns whether every map has the row's key with equal values.
+func (r NWayRow) Agree() bool {
+	for _, v := range r.Values {
+		if v == nil || !v.Equals(r.Values[0]) {
+			return false
+		}
+	}
+	return true
+}
+
+// NWayDiff compares |maps| by walking them in lockstep in key order. It returns a function that returns an NWayRow
+// for each key in any of the maps, in key order, and io.EOF once every key has been returned. Rows are returned for
+// every key, so callers looking for differences should skip rows that Agree. This generalizes the comparison made by
+// a three way merge to any number of maps.
+func NWayDiff(ctx context.Context, maps []types.Map) (func() (NWayRow, error), error) {
+	if len(maps) == 0 {
+		return nil, errors.New("NWayDiff requires at least one map")
+	}
+
+	nbf := maps[0].Format()
+	iters := make([]types.MapIterator, len(maps))
+	keys := make([]types.Value, len(maps))
+	vals := make([]types.Value, len(maps))
+
+	for i, m := range maps {
+		itr, err := m.Iterator(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		iters[i] = itr
+		keys[i], vals[i], err = itr.Next(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return func() (NWayRow, error) {
+		if ctx.Err() != nil {
+			return NWayRow{}, ctx.Err()
+		}
+
+		var minKey types.Value
+		for _, k := range keys {
+			if k == nil {
+				continue
+			}
+
+			if minKey == nil {
+				minKey = k
+			} else if isLess, err := k.Less(nbf, minKey); err != nil {
+				return NWayRow{}, err
+			} else if isLess {
+				minKey = k
+			}
+		}
+
+		if minKey == nil {
+			return NWayRow{}, io.EOF
+		}
+
+		row := NWayRow{Key: minKey, Values: make([]types.Value, len(maps))}
+		for i, k := range keys {
+			if k == nil || !k.Equals(minKey) {
+				continue
+			}
+
+			row.Values[i] = vals[i]
+
+			var err error
+			keys[i], vals[i], err = iters[i].Next(ctx)
+			if err != nil {
+				return NWayRow{}, err
+			}
+		}
+
+		return row, nil
+	}, nil
+}
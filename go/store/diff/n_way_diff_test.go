@@ -0,0 +1,87 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func TestNWayDiff(t *testing.T) {
+	ctx := context.Background()
+
+	m1 := createMap("a", 1, "b", 2, "c", 3, "d", 4, "f", 6)
+	m2 := createMap("a", 1, "b", 2, "c", 30, "e", 5, "f", 60)
+	m3 := createMap("a", 1, "b", 20, "c", 300, "d", 4, "f", 6)
+
+	next, err := NWayDiff(ctx, []types.Map{m1, m2, m3})
+	require.NoError(t, err)
+
+	type expectedRow struct {
+		key     string
+		agree   bool
+		present []bool
+		groups  [][]int
+	}
+
+	expected := []expectedRow{
+		// every map agrees
+		{"a", true, []bool{true, true, true}, [][]int{{0, 1, 2}}},
+		// one map differs from the other two
+		{"b", false, []bool{true, true, true}, [][]int{{0, 1}, {2}}},
+		// every map differs
+		{"c", false, []bool{true, true, true}, [][]int{{0}, {1}, {2}}},
+		// missing from one map, and the others agree
+		{"d", false, []bool{true, false, true}, [][]int{{0, 2}}},
+		// only in one map
+		{"e", false, []bool{false, true, false}, [][]int{{1}}},
+		// the first and last maps agree
+		{"f", false, []bool{true, true, true}, [][]int{{0, 2}, {1}}},
+	}
+
+	for _, exp := range expected {
+		row, err := next()
+		require.NoError(t, err)
+		assert.True(t, types.String(exp.key).Equals(row.Key), "expected %s got %v", exp.key, row.Key)
+		assert.Equal(t, exp.agree, row.Agree(), exp.key)
+		for i, present := range exp.present {
+			assert.Equal(t, present, row.Present(i), exp.key)
+		}
+		assert.Equal(t, exp.groups, row.Groups(), exp.key)
+	}
+
+	_, err = next()
+	assert.Equal(t, io.EOF, err)
+	_, err = next()
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestNWayDiffEmpty(t *testing.T) {
+	ctx := context.Background()
+
+	_, err := NWayDiff(ctx, nil)
+	assert.Error(t, err)
+
+	next, err := NWayDiff(ctx, []types.Map{createMap(), createMap()})
+	require.NoError(t, err)
+	_, err = next()
+	assert.Equal(t, io.EOF, err)
+}
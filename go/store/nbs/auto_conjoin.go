@@ -0,0 +1,138 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nbs
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"sync"
+)
+
+// autoConjoiner triggers a conjoin in the background when the number of tables in a directory exceeds a threshold.
+// Conjoining requires updating the manifest, which a tablePersister has no access to, so the conjoin itself is
+// performed by a function supplied by the persister's owner, which returns the number of tables it removed. Tables are
+// counted by listing the directory on the first persist, and from then on by counting persists and the tables removed
+// by conjoins. At most one conjoin runs at a time. Conjoins run with a context that is cancelled by close, and their
+// errors are returned by close rather than by the persists that triggered them, which have already succeeded.
+type autoConjoiner struct {
+	threshold int
+	conjoin   func(ctx context.Context) (removed int, err error)
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	mu sync.Mutex
+	// tables is the number of tables in the directory, or -1 until they are first counted
+	tables  int
+	running bool
+	closed  bool
+	err     error
+}
+
+func newAutoConjoiner(threshold int, conjoin func(ctx context.Context) (int, error)) *autoConjoiner {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &autoConjoiner{threshold: threshold, conjoin: conjoin, ctx: ctx, cancel: cancel, tables: -1}
+}
+
+// maybeConjoin counts a table persisted to |dir|, and starts a background conjoin if the number of tables exceeds
+// the threshold and no conjoin is already running. It is called once the table has been persisted, so rather than
+// failing the persist, an error counting the tables is recorded to be returned by close.
+func (ac *autoConjoiner) maybeConjoin(dir string) {
+	ac.mu.Lock()
+	defer ac.mu.Unlock()
+
+	if ac.closed {
+		return
+	}
+
+	if ac.tables < 0 {
+		n, err := countTableFiles(dir)
+
+		if err != nil {
+			ac.err = err
+			return
+		}
+
+		ac.tables = n
+	} else {
+		ac.tables++
+	}
+
+	if ac.running || ac.tables <= ac.threshold {
+		return
+	}
+
+	ac.running = true
+	ac.wg.Add(1)
+	go func() {
+		defer ac.wg.Done()
+
+		removed, err := ac.conjoin(ac.ctx)
+
+		ac.mu.Lock()
+		defer ac.mu.Unlock()
+
+		ac.running = false
+		ac.tables -= removed
+
+		// a conjoin cancelled by close has nothing to report
+		if err != nil && !(ac.ctx.Err() != nil && errors.Is(err, ac.ctx.Err())) {
+			ac.err = err
+		}
+	}()
+}
+
+// wait blocks until any running conjoin completes and returns the most recent error.
+func (ac *autoConjoiner) wait() error {
+	ac.wg.Wait()
+
+	ac.mu.Lock()
+	defer ac.mu.Unlock()
+	return ac.err
+}
+
+// close cancels any running conjoin, waits for it to exit, and returns the most recent error. No conjoins are started
+// after close.
+func (ac *autoConjoiner) close() error {
+	ac.mu.Lock()
+	ac.closed = true
+	ac.mu.Unlock()
+
+	ac.cancel()
+	return ac.wait()
+}
+
+func countTableFiles(dir string) (int, error) {
+	fileInfos, err := ioutil.ReadDir(dir)
+
+	if err != nil {
+		return 0, err
+	}
+
+	n := 0
+	for _, info := range fileInfos {
+		if info.IsDir() {
+			continue
+		}
+
+		if _, ok := tableFileAddr(info.Name()); ok {
+			n++
+		}
+	}
+
+	return n, nil
+}
@@ -0,0 +1,141 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nbs
+
+import (
+	"context"
+	"errors"
+	"os"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFSTablePersisterAutoConjoin(t *testing.T) {
+	dir := makeTempDir(t)
+	defer os.RemoveAll(dir)
+	fc := newFDCache(defaultMaxTables)
+	defer fc.Drop()
+
+	var conjoins int32
+	release := make(chan struct{})
+	var srcs chunkSources
+	var fts tablePersister
+	fts = newFSTablePersister(dir, fc, nil, withAutoConjoin(3, func(ctx context.Context) (int, error) {
+		atomic.AddInt32(&conjoins, 1)
+		<-release
+
+		_, err := fts.ConjoinAll(ctx, srcs, &Stats{})
+		return len(srcs) - 1, err
+	}))
+	ac := fts.(*fsTablePersister).autoConjoiner
+
+	for i := 0; i < 3; i++ {
+		src, err := persistTableData(fts, []byte{byte(i)})
+		require.NoError(t, err)
+		srcs = append(srcs, src)
+	}
+	assert.Equal(t, int32(0), atomic.LoadInt32(&conjoins))
+
+	// crossing the threshold starts a conjoin, and further persists don't start another while it runs
+	for i := 3; i < 6; i++ {
+		src, err := persistTableData(fts, []byte{byte(i)})
+		require.NoError(t, err)
+		srcs = append(srcs, src)
+	}
+	close(release)
+	require.NoError(t, ac.wait())
+	assert.Equal(t, int32(1), atomic.LoadInt32(&conjoins))
+
+	n, err := countTableFiles(dir)
+	require.NoError(t, err)
+	assert.Equal(t, 7, n)
+
+	// the tables removed by the conjoin are no longer counted, although their files remain, so the next persist
+	// doesn't start another
+	_, err = persistTableData(fts, []byte{6})
+	require.NoError(t, err)
+	require.NoError(t, ac.wait())
+	assert.Equal(t, int32(1), atomic.LoadInt32(&conjoins))
+}
+
+func TestAutoConjoinerCloseRacesPersists(t *testing.T) {
+	dir := makeTempDir(t)
+	defer os.RemoveAll(dir)
+
+	var closed, lateConjoins int32
+	ac := newAutoConjoiner(0, func(ctx context.Context) (int, error) {
+		if atomic.LoadInt32(&closed) != 0 {
+			atomic.AddInt32(&lateConjoins, 1)
+		}
+		return 0, nil
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				ac.maybeConjoin(dir)
+			}
+		}()
+	}
+
+	require.NoError(t, ac.close())
+	atomic.StoreInt32(&closed, 1)
+	wg.Wait()
+
+	// no conjoin starts once close has returned
+	assert.Equal(t, int32(0), atomic.LoadInt32(&lateConjoins))
+}
+
+func TestFSTablePersisterAutoConjoinClose(t *testing.T) {
+	dir := makeTempDir(t)
+	defer os.RemoveAll(dir)
+	fc := newFDCache(defaultMaxTables)
+	defer fc.Drop()
+
+	errConjoin := errors.New("conjoin failed")
+	started := make(chan struct{})
+	fts := newFSTablePersister(dir, fc, nil, withAutoConjoin(1, func(ctx context.Context) (int, error) {
+		close(started)
+		<-ctx.Done()
+		return 0, ctx.Err()
+	})).(*fsTablePersister)
+
+	// the persist that triggers the conjoin succeeds regardless of the conjoin
+	for i := 0; i < 2; i++ {
+		_, err := persistTableData(fts, []byte{byte(i)})
+		require.NoError(t, err)
+	}
+	<-started
+
+	// closing cancels the running conjoin, whose cancellation isn't reported, and stops further conjoins
+	require.NoError(t, fts.Close())
+	_, err := persistTableData(fts, []byte{2})
+	require.NoError(t, err)
+	require.NoError(t, fts.Close())
+
+	failing := newFSTablePersister(dir, fc, nil, withAutoConjoin(1, func(ctx context.Context) (int, error) {
+		return 0, errConjoin
+	})).(*fsTablePersister)
+	_, err = persistTableData(failing, []byte{3})
+	require.NoError(t, err)
+	assert.True(t, errors.Is(failing.Close(), errConjoin))
+}
@@ -0,0 +1,81 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nbs
+
+import (
+	"bytes"
+	"sort"
+)
+
+// DiffChunkSources compares the chunk addresses in the indexes of |a| and |b|, without reading any chunk data. It
+// returns the addresses only in |a|, only in |b|, and in both, each in sorted order.
+func DiffChunkSources(a, b chunkSource) (onlyA, onlyB, both []addr, err error) {
+	aAddrs, err := sortedIndexAddrs(a)
+
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	bAddrs, err := sortedIndexAddrs(b)
+
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	onlyA, onlyB, both = diffSortedAddrs(aAddrs, bAddrs)
+	return onlyA, onlyB, both, nil
+}
+
+// diffSortedAddrs merges the sorted address lists |aAddrs| and |bAddrs|, returning the addresses only in |aAddrs|,
+// only in |bAddrs|, and in both, each in sorted order.
+func diffSortedAddrs(aAddrs, bAddrs addrSlice) (onlyA, onlyB, both []addr) {
+	i, j := 0, 0
+	for i < len(aAddrs) && j < len(bAddrs) {
+		switch c := bytes.Compare(aAddrs[i][:], bAddrs[j][:]); {
+		case c < 0:
+			onlyA = append(onlyA, aAddrs[i])
+			i++
+		case c > 0:
+			onlyB = append(onlyB, bAddrs[j])
+			j++
+		default:
+			both = append(both, aAddrs[i])
+			i++
+			j++
+		}
+	}
+
+	onlyA = append(onlyA, aAddrs[i:]...)
+	onlyB = append(onlyB, bAddrs[j:]...)
+
+	return onlyA, onlyB, both
+}
+
+// sortedIndexAddrs returns the address of every chunk in the index of |cs|, sorted.
+func sortedIndexAddrs(cs chunkSource) (addrSlice, error) {
+	index, err := cs.index()
+
+	if err != nil {
+		return nil, err
+	}
+
+	addrs := make(addrSlice, index.ChunkCount())
+	for i := range addrs {
+		index.IndexEntry(uint32(i), &addrs[i])
+	}
+
+	sort.Sort(addrs)
+	return addrs, nil
+}
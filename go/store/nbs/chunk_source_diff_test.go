@@ -0,0 +1,65 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nbs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffChunkSources(t *testing.T) {
+	open := func(chunks [][]byte) chunkSource {
+		tableData, name, err := buildTable(chunks)
+		require.NoError(t, err)
+		src, err := newReaderFromIndexData(nil, tableData, name, tableReaderAtFromBytes(tableData), fileBlockSize)
+		require.NoError(t, err)
+		return src
+	}
+
+	addrsOf := func(chunks ...string) []addr {
+		addrs := make([]addr, len(chunks))
+		for i, c := range chunks {
+			addrs[i] = computeAddr([]byte(c))
+		}
+		return addrs
+	}
+
+	toBytes := func(chunks ...string) [][]byte {
+		bs := make([][]byte, len(chunks))
+		for i, c := range chunks {
+			bs[i] = []byte(c)
+		}
+		return bs
+	}
+
+	a := open(toBytes("hello2", "goodbye2", "badbye2", "only in a"))
+	defer a.Close()
+	b := open(toBytes("goodbye2", "hello2", "only in b", "also only in b"))
+	defer b.Close()
+
+	onlyA, onlyB, both, err := DiffChunkSources(a, b)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, addrsOf("badbye2", "only in a"), onlyA)
+	assert.ElementsMatch(t, addrsOf("only in b", "also only in b"), onlyB)
+	assert.ElementsMatch(t, addrsOf("hello2", "goodbye2"), both)
+
+	onlyA, onlyB, both, err = DiffChunkSources(a, a)
+	require.NoError(t, err)
+	assert.Empty(t, onlyA)
+	assert.Empty(t, onlyB)
+	assert.Len(t, both, 4)
+}
@@ -0,0 +1,91 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nbs
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrConjoinNoBenefit is returned by conjoinIfBeneficial when conjoining its sources isn't expected to save enough
+// space to be worth rewriting them.
+var ErrConjoinNoBenefit = errors.New("conjoin skipped: estimated savings below threshold")
+
+// conjoinSavings is an estimate of the space that conjoining a set of table files would save.
+type conjoinSavings struct {
+	// duplicateData is the length of chunk data stored in more than one source
+	duplicateData uint64
+	// footers is the length of the footers that would be replaced by a single footer
+	footers uint64
+	// total is the combined size of the sources
+	total uint64
+}
+
+// ratio returns the estimated savings as a fraction of the sources' combined size.
+func (cs conjoinSavings) ratio() float64 {
+	if cs.total == 0 {
+		return 0
+	}
+	return float64(cs.duplicateData+cs.footers) / float64(cs.total)
+}
+
+// estimateConjoinSavings compares the chunk addresses in each of |sources| to find the chunk data they share.
+func estimateConjoinSavings(sources chunkSources) (conjoinSavings, error) {
+	var cs conjoinSavings
+	seen := make(map[addr]struct{})
+
+	for _, src := range sources {
+		index, err := src.index()
+
+		if err != nil {
+			return conjoinSavings{}, err
+		}
+
+		cs.total += index.TableFileSize()
+
+		for i := uint32(0); i < index.ChunkCount(); i++ {
+			var a addr
+			e := index.IndexEntry(i, &a)
+
+			if _, ok := seen[a]; ok {
+				cs.duplicateData += uint64(e.Length())
+				continue
+			}
+			seen[a] = struct{}{}
+		}
+	}
+
+	if len(sources) > 1 {
+		cs.footers = uint64(len(sources)-1) * footerSize
+	}
+
+	return cs, nil
+}
+
+// conjoinIfBeneficial conjoins |sources| with |p| only if the estimated savings are at least |minSavings|, as a
+// fraction of the sources' combined size. Otherwise it returns ErrConjoinNoBenefit without writing anything.
+func conjoinIfBeneficial(ctx context.Context, p tablePersister, sources chunkSources, minSavings float64, stats *Stats) (chunkSource, error) {
+	cs, err := estimateConjoinSavings(sources)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if cs.ratio() < minSavings {
+		return nil, ErrConjoinNoBenefit
+	}
+
+	return p.ConjoinAll(ctx, sources, stats)
+}
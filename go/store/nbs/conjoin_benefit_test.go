@@ -0,0 +1,89 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nbs
+
+import (
+	"context"
+	"crypto/rand"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConjoinIfBeneficial(t *testing.T) {
+	dir := makeTempDir(t)
+	defer os.RemoveAll(dir)
+	fc := newFDCache(defaultMaxTables)
+	defer fc.Drop()
+	fts := newFSTablePersister(dir, fc, nil)
+
+	randChunk := func() []byte {
+		c := make([]byte, 1024)
+		_, err := rand.Read(c)
+		if err != nil {
+			panic(err)
+		}
+		return c
+	}
+
+	var shared [][]byte
+	for i := 0; i < 4; i++ {
+		shared = append(shared, randChunk())
+	}
+
+	persist := func(t *testing.T, chunx ...[]byte) chunkSource {
+		mt := newMemTable(1 << 20)
+		for _, c := range chunx {
+			require.True(t, mt.addChunk(computeAddr(c), c))
+		}
+		src, err := fts.Persist(context.Background(), mt, nil, &Stats{})
+		require.NoError(t, err)
+		return src
+	}
+
+	t.Run("low overlap", func(t *testing.T) {
+		sources := chunkSources{
+			persist(t, randChunk(), randChunk(), randChunk()),
+			persist(t, randChunk(), randChunk(), randChunk()),
+		}
+
+		before, err := countTableFiles(dir)
+		require.NoError(t, err)
+
+		_, err = conjoinIfBeneficial(context.Background(), fts, sources, 0.25, &Stats{})
+		assert.Equal(t, ErrConjoinNoBenefit, err)
+
+		after, err := countTableFiles(dir)
+		require.NoError(t, err)
+		assert.Equal(t, before, after)
+	})
+
+	t.Run("high overlap", func(t *testing.T) {
+		sources := chunkSources{
+			persist(t, append([][]byte{randChunk()}, shared...)...),
+			persist(t, append([][]byte{randChunk()}, shared...)...),
+		}
+
+		cs, err := estimateConjoinSavings(sources)
+		require.NoError(t, err)
+		assert.True(t, cs.duplicateData >= 4*1024) // stored chunks include compression framing and a checksum
+
+		src, err := conjoinIfBeneficial(context.Background(), fts, sources, 0.25, &Stats{})
+		require.NoError(t, err)
+		assert.Equal(t, uint32(10), mustUint32(src.count()))
+	})
+}
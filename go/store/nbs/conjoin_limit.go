@@ -0,0 +1,77 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nbs
+
+import (
+	"context"
+	"fmt"
+	"sort"
+)
+
+// conjoinSmallest conjoins at most |maxSources| of |sources| with |p|, choosing those with the fewest chunks, so
+// that a large set of tables can be conjoined incrementally over several calls without holding every source open in
+// a single plan. It returns the sources that weren't conjoined, in their original order, followed by the conjoined
+// source. If there are no more than |maxSources| sources, all of them are conjoined.
+func conjoinSmallest(ctx context.Context, p tablePersister, sources chunkSources, maxSources int, stats *Stats) (chunkSources, error) {
+	if maxSources < 2 {
+		return nil, fmt.Errorf("can not conjoin fewer than 2 sources, got a limit of %d", maxSources)
+	}
+
+	toConjoin := sources
+	var toKeep chunkSources
+
+	if len(sources) > maxSources {
+		counts := make([]uint32, len(sources))
+		for i, src := range sources {
+			cnt, err := src.count()
+
+			if err != nil {
+				return nil, err
+			}
+
+			counts[i] = cnt
+		}
+
+		order := make([]int, len(sources))
+		for i := range order {
+			order[i] = i
+		}
+
+		sort.SliceStable(order, func(i, j int) bool {
+			return counts[order[i]] < counts[order[j]]
+		})
+
+		chosen := make([]bool, len(sources))
+		toConjoin = make(chunkSources, 0, maxSources)
+		for _, i := range order[:maxSources] {
+			chosen[i] = true
+			toConjoin = append(toConjoin, sources[i])
+		}
+
+		for i, src := range sources {
+			if !chosen[i] {
+				toKeep = append(toKeep, src)
+			}
+		}
+	}
+
+	conjoined, err := p.ConjoinAll(ctx, toConjoin, stats)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return append(toKeep, conjoined), nil
+}
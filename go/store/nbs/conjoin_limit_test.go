@@ -0,0 +1,79 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nbs
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConjoinSmallest(t *testing.T) {
+	ctx := context.Background()
+	dir := makeTempDir(t)
+	defer os.RemoveAll(dir)
+	fc := newFDCache(defaultMaxTables)
+	defer fc.Drop()
+	fts := newFSTablePersister(dir, fc, nil)
+
+	counts := []int{5, 1, 7, 2, 9, 3, 8, 4, 10, 6}
+	chunksBySource := make([][][]byte, len(counts))
+	sources := make(chunkSources, len(counts))
+	for i, cnt := range counts {
+		for j := 0; j < cnt; j++ {
+			chunksBySource[i] = append(chunksBySource[i], []byte(fmt.Sprintf("source %d chunk %d", i, j)))
+		}
+
+		src, err := persistTableData(fts, chunksBySource[i]...)
+		require.NoError(t, err)
+		sources[i] = src
+	}
+
+	result, err := conjoinSmallest(ctx, fts, sources, 4, &Stats{})
+	require.NoError(t, err)
+	require.Len(t, result, len(sources)-4+1)
+
+	// the sources with 1, 2, 3 and 4 chunks are conjoined, and the rest are returned in order
+	var conjoinedChunks [][]byte
+	kept := result[:len(result)-1]
+	k := 0
+	for i, cnt := range counts {
+		if cnt <= 4 {
+			conjoinedChunks = append(conjoinedChunks, chunksBySource[i]...)
+			continue
+		}
+
+		assert.True(t, sources[i] == kept[k], "source %d wasn't returned untouched", i)
+		k++
+	}
+	assert.Equal(t, len(kept), k)
+
+	conjoined := result[len(result)-1]
+	assert.Equal(t, uint32(1+2+3+4), mustUint32(conjoined.count()))
+	assertChunksInReader(conjoinedChunks, conjoined, assert.New(t))
+
+	// with no more than the limit, every source is conjoined
+	result, err = conjoinSmallest(ctx, fts, result, 10, &Stats{})
+	require.NoError(t, err)
+	require.Len(t, result, 1)
+	assert.Equal(t, uint32(55), mustUint32(result[0].count()))
+
+	_, err = conjoinSmallest(ctx, fts, sources, 1, &Stats{})
+	assert.Error(t, err)
+}
@@ -0,0 +1,138 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nbs
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net"
+	"syscall"
+	"time"
+
+	"github.com/jpillora/backoff"
+)
+
+const (
+	// conjoinRetryMinBackoff and conjoinRetryMaxBackoff bound the time waited before retrying a failed source read
+	conjoinRetryMinBackoff = 10 * time.Millisecond
+	conjoinRetryMaxBackoff = time.Second
+)
+
+// isTransientReadErr returns whether |err|, from reading a table file, is likely to succeed if the read is retried,
+// such as a timeout or a reset connection to network storage.
+func isTransientReadErr(err error) bool {
+	var temp interface{ Temporary() bool }
+	if errors.As(err, &temp) && temp.Temporary() {
+		return true
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+
+	var errno syscall.Errno
+	if errors.As(err, &errno) {
+		switch errno {
+		case syscall.EAGAIN, syscall.EINTR, syscall.ETIMEDOUT, syscall.ECONNRESET:
+			return true
+		}
+	}
+
+	return false
+}
+
+// copySourceData copies the chunk data of |sws| to |w| through |buf|. Transient errors reading the source are
+// retried up to the persister's readRetries times. Each retry gets a new reader for the source and skips the data
+// that was already copied, so that none is copied twice or left out.
+func (ftp *fsTablePersister) copySourceData(ctx context.Context, w io.Writer, sws sourceWithSize, buf []byte) error {
+	b := &backoff.Backoff{
+		Min:    conjoinRetryMinBackoff,
+		Max:    conjoinRetryMaxBackoff,
+		Factor: 2,
+		Jitter: true,
+	}
+
+	var copied uint64
+	for retries := 0; ; retries++ {
+		n, err := copySourceDataFrom(ctx, w, sws, copied, buf)
+		copied += n
+
+		if err == nil {
+			return nil
+		}
+
+		var readErr *sourceReadError
+		if retries >= ftp.readRetries || !errors.As(err, &readErr) || !isTransientReadErr(readErr.err) {
+			return err
+		}
+
+		select {
+		case <-time.After(b.Duration()):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// copySourceDataFrom copies the chunk data of |sws| from |off| to |w| through |buf|, and returns the number of bytes
+// copied. Errors reading the source are returned as sourceReadErrors.
+func copySourceDataFrom(ctx context.Context, w io.Writer, sws sourceWithSize, off uint64, buf []byte) (uint64, error) {
+	r, err := sws.source.reader(ctx)
+
+	if err != nil {
+		return 0, &sourceReadError{sws.source, err}
+	}
+
+	if err = skipSourceData(r, off); err != nil {
+		return 0, &sourceReadError{sws.source, err}
+	}
+
+	// hide w's ReadFrom so that the copy goes through |buf| rather than the file's own fixed size buffer
+	rr := &readErrRecorder{r: r}
+	n, err := io.CopyBuffer(struct{ io.Writer }{w}, io.LimitReader(rr, int64(sws.dataLen-off)), buf)
+
+	if rr.err != nil {
+		return uint64(n), &sourceReadError{sws.source, rr.err}
+	} else if err != nil {
+		return uint64(n), err
+	}
+
+	if uint64(n) != sws.dataLen-off {
+		return uint64(n), &sourceReadError{sws.source, io.ErrUnexpectedEOF}
+	}
+
+	return uint64(n), nil
+}
+
+// skipSourceData advances |r|, which reads a source from the start of its chunk data, to |off|.
+func skipSourceData(r io.Reader, off uint64) error {
+	if off == 0 {
+		return nil
+	}
+
+	if s, ok := r.(io.Seeker); ok {
+		_, err := s.Seek(int64(off), io.SeekCurrent)
+		return err
+	}
+
+	n, err := io.CopyN(ioutil.Discard, r, int64(off))
+	if err == io.EOF && uint64(n) < off {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
@@ -0,0 +1,181 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nbs
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// transientErr is an error that says it's temporary.
+type transientErr struct{}
+
+func (transientErr) Error() string   { return "transient" }
+func (transientErr) Temporary() bool { return true }
+
+// failingReader is an io.Reader that always fails with |err|.
+type failingReader struct {
+	err error
+}
+
+func (fr failingReader) Read(p []byte) (int, error) {
+	return 0, fr.err
+}
+
+// flakyChunkSource is a chunkSource whose first |failures| readers fail with |err|, either when they are requested
+// or, if |failAt| is positive, after reading |failAt| bytes.
+type flakyChunkSource struct {
+	chunkSource
+	failures int
+	failAt   int64
+	err      error
+	readers  int
+}
+
+func (fcs *flakyChunkSource) reader(ctx context.Context) (io.Reader, error) {
+	fcs.readers++
+	r, err := fcs.chunkSource.reader(ctx)
+
+	if err != nil || fcs.failures == 0 {
+		return r, err
+	}
+
+	fcs.failures--
+	if fcs.failAt <= 0 {
+		return nil, fcs.err
+	}
+
+	return io.MultiReader(io.LimitReader(r, fcs.failAt), failingReader{fcs.err}), nil
+}
+
+func TestCopySourceDataRetries(t *testing.T) {
+	ctx := context.Background()
+	dir := makeTempDir(t)
+	defer os.RemoveAll(dir)
+	fc := newFDCache(defaultMaxTables)
+	defer fc.Drop()
+
+	// sources with incompressible chunks, so that their data is long enough to fail partway through
+	sourceChunks := make([][][]byte, 3)
+	sources := make(chunkSources, len(sourceChunks))
+	for i := range sourceChunks {
+		mt := newMemTable(1 << 12)
+		for j := 0; j < 4; j++ {
+			c := make([]byte, 512)
+			_, err := rand.Read(c)
+			require.NoError(t, err)
+			require.True(t, mt.addChunk(computeAddr(c), c))
+			sourceChunks[i] = append(sourceChunks[i], c)
+		}
+
+		var err error
+		sources[i], err = newFSTablePersister(dir, fc, nil).Persist(ctx, mt, nil, &Stats{})
+		require.NoError(t, err)
+	}
+
+	// conjoin without failures to get the expected table file
+	expectedDir := filepath.Join(dir, "expected")
+	require.NoError(t, os.Mkdir(expectedDir, 0777))
+	expected, err := newFSTablePersister(dir, fc, nil).(*fsTablePersister).ConjoinAllInto(ctx, expectedDir, sources, &Stats{})
+	require.NoError(t, err)
+	expectedName := mustAddr(expected.hash())
+	expectedData, err := ioutil.ReadFile(filepath.Join(expectedDir, expectedName.String()))
+	require.NoError(t, err)
+	require.NoError(t, expected.Close())
+
+	withFlakySource := func(flaky *flakyChunkSource) chunkSources {
+		flaky.chunkSource = sources[1]
+		return chunkSources{sources[0], flaky, sources[2]}
+	}
+
+	tests := []struct {
+		name  string
+		flaky *flakyChunkSource
+	}{
+		{"failure reading data", &flakyChunkSource{failures: 1, failAt: 100, err: transientErr{}}},
+		{"repeated failures reading data", &flakyChunkSource{failures: 2, failAt: 700, err: transientErr{}}},
+		{"failure getting reader", &flakyChunkSource{failures: 1, err: transientErr{}}},
+		{"timeout", &flakyChunkSource{failures: 1, failAt: 1, err: os.ErrDeadlineExceeded}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			outDir := filepath.Join(dir, test.name)
+			require.NoError(t, os.Mkdir(outDir, 0777))
+
+			fts := newFSTablePersister(dir, fc, nil, withReadRetries(3)).(*fsTablePersister)
+			src, err := fts.ConjoinAllInto(ctx, outDir, withFlakySource(test.flaky), &Stats{})
+			require.NoError(t, err)
+			defer src.Close()
+
+			assert.Equal(t, 0, test.flaky.failures)
+			assert.Equal(t, expectedName, mustAddr(src.hash()))
+			data, err := ioutil.ReadFile(filepath.Join(outDir, expectedName.String()))
+			require.NoError(t, err)
+			assert.Equal(t, expectedData, data)
+
+			var all [][]byte
+			for _, chunks := range sourceChunks {
+				all = append(all, chunks...)
+			}
+			assertChunksInReader(all, src, assert.New(t))
+		})
+	}
+
+	t.Run("permanent errors aren't retried", func(t *testing.T) {
+		permanent := errors.New("permanent")
+		flaky := &flakyChunkSource{failures: 1, failAt: 100, err: permanent}
+
+		fts := newFSTablePersister(dir, fc, nil, withReadRetries(3)).(*fsTablePersister)
+		_, err := fts.ConjoinAll(ctx, withFlakySource(flaky), &Stats{})
+		assert.True(t, errors.Is(err, permanent))
+		assert.Equal(t, 1, flaky.readers)
+	})
+
+	t.Run("retries are bounded", func(t *testing.T) {
+		flaky := &flakyChunkSource{failures: 5, failAt: 100, err: transientErr{}}
+
+		fts := newFSTablePersister(dir, fc, nil, withReadRetries(2)).(*fsTablePersister)
+		_, err := fts.ConjoinAll(ctx, withFlakySource(flaky), &Stats{})
+		assert.True(t, errors.Is(err, transientErr{}))
+		assert.Equal(t, 3, flaky.readers)
+	})
+
+	t.Run("no retries by default", func(t *testing.T) {
+		flaky := &flakyChunkSource{failures: 1, failAt: 100, err: transientErr{}}
+
+		fts := newFSTablePersister(dir, fc, nil).(*fsTablePersister)
+		_, err := fts.ConjoinAll(ctx, withFlakySource(flaky), &Stats{})
+		assert.True(t, errors.Is(err, transientErr{}))
+		assert.Equal(t, 1, flaky.readers)
+	})
+}
+
+func TestIsTransientReadErr(t *testing.T) {
+	assert.True(t, isTransientReadErr(transientErr{}))
+	assert.True(t, isTransientReadErr(&os.PathError{Op: "read", Path: "table", Err: os.ErrDeadlineExceeded}))
+	assert.False(t, isTransientReadErr(errors.New("permanent")))
+	assert.False(t, isTransientReadErr(io.ErrUnexpectedEOF))
+	assert.False(t, isTransientReadErr(os.ErrNotExist))
+}
@@ -0,0 +1,57 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nbs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrChunkNotInTable is returned by ExtractChunks when a requested chunk isn't in the table file.
+var ErrChunkNotInTable = errors.New("chunk not present in table file")
+
+// ExtractChunks looks up each of |addrs| in the index of |source| and returns the decompressed data of each chunk,
+// keyed by its address. It returns an error wrapping ErrChunkNotInTable if any of |addrs| is not in |source|.
+func ExtractChunks(ctx context.Context, source chunkSource, addrs []addr) (map[addr][]byte, error) {
+	index, err := source.index()
+
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &Stats{}
+	extracted := make(map[addr][]byte, len(addrs))
+	for _, a := range addrs {
+		if _, ok := extracted[a]; ok {
+			continue
+		}
+
+		a := a
+		if _, found := index.Lookup(&a); !found {
+			return nil, fmt.Errorf("%w: %s", ErrChunkNotInTable, a.String())
+		}
+
+		data, err := source.get(ctx, a, stats)
+
+		if err != nil {
+			return nil, err
+		}
+
+		extracted[a] = data
+	}
+
+	return extracted, nil
+}
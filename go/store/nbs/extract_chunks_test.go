@@ -0,0 +1,52 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nbs
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractChunks(t *testing.T) {
+	ctx := context.Background()
+
+	chunks := [][]byte{
+		[]byte("hello2"),
+		[]byte("goodbye2"),
+		[]byte("badbye2"),
+		[]byte("so much to do"),
+	}
+
+	tableData, name, err := buildTable(chunks)
+	require.NoError(t, err)
+	src, err := newReaderFromIndexData(nil, tableData, name, tableReaderAtFromBytes(tableData), fileBlockSize)
+	require.NoError(t, err)
+	defer src.Close()
+
+	subset := []addr{computeAddr(chunks[1]), computeAddr(chunks[3]), computeAddr(chunks[1])}
+	extracted, err := ExtractChunks(ctx, src, subset)
+	require.NoError(t, err)
+	assert.Equal(t, map[addr][]byte{
+		computeAddr(chunks[1]): chunks[1],
+		computeAddr(chunks[3]): chunks[3],
+	}, extracted)
+
+	_, err = ExtractChunks(ctx, src, []addr{computeAddr(chunks[0]), computeAddr([]byte("not present"))})
+	assert.True(t, errors.Is(err, ErrChunkNotInTable))
+}
@@ -155,6 +155,19 @@ func (fc *fdCache) ShrinkCache() error {
 	return nil
 }
 
+// ShrinkFile removes the file handle for |path| if it has a refcount of zero.
+func (fc *fdCache) ShrinkFile(path string) error {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+	ce, present := fc.cache[path]
+	if !present || ce.refCount != 0 {
+		return nil
+	}
+
+	delete(fc.cache, path)
+	return ce.f.Close()
+}
+
 // Drop dumps the entire cache and closes all currently open files.
 func (fc *fdCache) Drop() {
 	fc.mu.Lock()
@@ -22,6 +22,7 @@
 package nbs
 
 import (
+	"bufio"
 	"context"
 	"errors"
 	"io"
@@ -68,7 +69,7 @@ func MaybeMigrateFileManifest(ctx context.Context, dir string) (bool, error) {
 		return false, err
 	}
 
-	fm5 := fileManifestV5{dir}
+	fm5 := fileManifestV5{dir: dir}
 	ok, _, err := fm5.ParseIfExists(ctx, &Stats{}, nil)
 	if ok && err == nil {
 		// on v5, no need to migrate
@@ -116,9 +117,16 @@ func getFileManifest(ctx context.Context, dir string) (manifest, error) {
 		err = f.Close()
 	}()
 
-	fm5 := fileManifestV5{dir}
+	fm5 := fileManifestV5{dir: dir}
 	ok, _, err := fm5.ParseIfExists(ctx, &Stats{}, nil)
 	if ok && err == nil {
+		// once a store may contain extended table files, its manifest must keep saying so
+		fm5.extendedTables, err = isExtendedTablesManifest(f)
+
+		if err != nil {
+			return nil, err
+		}
+
 		return fm5, nil
 	}
 
@@ -131,6 +139,30 @@ func getFileManifest(ctx context.Context, dir string) (manifest, error) {
 	return nil, ErrUnreadableManifest
 }
 
+// extendedTablesFileManifest returns the manifest of the store in |dir| for a store that may write table files in
+// the extended format, first migrating an existing manifest to fileManifestV5 if necessary.
+func extendedTablesFileManifest(ctx context.Context, dir string) (manifest, error) {
+	_, err := MaybeMigrateFileManifest(ctx, dir)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return fileManifestV5{dir: dir, extendedTables: true}, nil
+}
+
+// isExtendedTablesManifest returns whether the fileManifestV5 manifest read from |r| has the storage version of a
+// store that may contain extended table files.
+func isExtendedTablesManifest(r io.Reader) (bool, error) {
+	vers, err := bufio.NewReader(r).ReadString(':')
+
+	if err != nil {
+		return false, err
+	}
+
+	return strings.TrimSuffix(vers, ":") == extendedTablesStorageVersion, nil
+}
+
 // fileManifestV5 provides access to a NomsBlockStore manifest stored on disk in |dir|. The format
 // is currently human readable. The prefix contains 5 strings, followed by pairs of table file
 // hashes and their counts:
@@ -142,6 +174,10 @@ func getFileManifest(ctx context.Context, dir string) (manifest, error) {
 // :table 1 hash:table 1 cnt:...:table N hash:table N cnt|
 type fileManifestV5 struct {
 	dir string
+
+	// extendedTables is set when the store may contain table files in the extended format, in which case the manifest
+	// is written with extendedTablesStorageVersion rather than StorageVersion
+	extendedTables bool
 }
 
 func newLock(dir string) *fslock.Lock {
@@ -240,7 +276,7 @@ func (fm5 fileManifestV5) parseManifest(r io.Reader) (manifestContents, error) {
 		return manifestContents{}, ErrCorruptManifest
 	}
 
-	if StorageVersion != string(slices[0]) {
+	if StorageVersion != string(slices[0]) && extendedTablesStorageVersion != string(slices[0]) {
 		return manifestContents{}, errors.New("invalid storage version")
 	}
 
@@ -269,7 +305,12 @@ func (fm5 fileManifestV5) parseManifest(r io.Reader) (manifestContents, error) {
 
 func (fm5 fileManifestV5) writeManifest(temp io.Writer, contents manifestContents) error {
 	strs := make([]string, 2*len(contents.specs)+prefixLen)
-	strs[0], strs[1], strs[2], strs[3], strs[4] = StorageVersion, contents.vers, contents.lock.String(), contents.root.String(), contents.gcGen.String()
+	vers := StorageVersion
+	if fm5.extendedTables {
+		vers = extendedTablesStorageVersion
+	}
+
+	strs[0], strs[1], strs[2], strs[3], strs[4] = vers, contents.vers, contents.lock.String(), contents.root.String(), contents.gcGen.String()
 	tableInfo := strs[prefixLen:]
 	formatSpecs(contents.specs, tableInfo)
 	_, err := io.WriteString(temp, strings.Join(strs, ":"))
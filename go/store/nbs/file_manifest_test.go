@@ -139,7 +139,7 @@ func TestFileManifestUpdateEmpty(t *testing.T) {
 	assert.True(upstream.root.IsEmpty())
 	assert.Empty(upstream.specs)
 
-	fm2 := fileManifestV5{fm.dir} // Open existent, but empty manifest
+	fm2 := fileManifestV5{dir: fm.dir} // Open existent, but empty manifest
 	exists, upstream, err := fm2.ParseIfExists(context.Background(), stats, nil)
 	assert.NoError(err)
 	assert.True(exists)
@@ -24,13 +24,19 @@ package nbs
 import (
 	"bytes"
 	"context"
+	"crypto/rand"
 	"errors"
+	"fmt"
 	"io"
 	"io/ioutil"
+	"math"
 	"os"
 	"path"
 	"path/filepath"
+	"sort"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/dolthub/dolt/go/store/util/tempfiles"
 
@@ -39,23 +45,183 @@ import (
 
 const tempTablePrefix = "nbs_table_"
 
-func newFSTablePersister(dir string, fc *fdCache, indexCache *indexCache) tablePersister {
+// newFSTablePersister returns a tablePersister for the table files in |dir|, configured by |opts|.
+func newFSTablePersister(dir string, fc *fdCache, indexCache *indexCache, opts ...fsTablePersisterOption) tablePersister {
 	d.PanicIfTrue(fc == nil)
-	return &fsTablePersister{dir, fc, indexCache}
+	ftp := &fsTablePersister{dir: dir, fc: fc, indexCache: indexCache}
+	for _, opt := range opts {
+		opt(ftp)
+	}
+	return ftp
+}
+
+// fsTablePersisterOption configures a tablePersister returned by newFSTablePersister.
+type fsTablePersisterOption func(ftp *fsTablePersister)
+
+// withMmapPool makes the persister's table readers serve chunk reads out of memory mappings held in |mp|, which
+// bounds the number of files mapped at once. The persister takes ownership of |mp|, and Close drops it.
+func withMmapPool(mp *mmapPool) fsTablePersisterOption {
+	d.PanicIfTrue(mp == nil)
+	return func(ftp *fsTablePersister) {
+		ftp.mmapPool = mp
+	}
+}
+
+// withBatchedShrinks suits a persister for persisting many tables in quick succession. Rather than shrinking the fd
+// cache on every persist, it shrinks it once every |shrinkEvery| persists, or on the first persist after
+// |shrinkWindow| has passed since the last shrink.
+func withBatchedShrinks(shrinkEvery int, shrinkWindow time.Duration) fsTablePersisterOption {
+	return func(ftp *fsTablePersister) {
+		ftp.shrinker = &shrinkBatcher{fc: ftp.fc, every: shrinkEvery, window: shrinkWindow, last: time.Now()}
+	}
+}
+
+// withAutoConjoin makes the persister, after persisting a table, call |conjoin| in the background if there are more
+// than |threshold| tables in its directory. |conjoin| is responsible for conjoining tables and updating the manifest
+// to reference the result, and returns the number of tables it removed. Close cancels a running conjoin and returns
+// the error from the last one that failed.
+func withAutoConjoin(threshold int, conjoin func(ctx context.Context) (removed int, err error)) fsTablePersisterOption {
+	d.PanicIfTrue(conjoin == nil)
+	return func(ftp *fsTablePersister) {
+		ftp.autoConjoiner = newAutoConjoiner(threshold, conjoin)
+	}
+}
+
+// withVerifiedNames makes the persister check that the content address of each table it opens matches the name it
+// was opened by, returning an error wrapping ErrTableNameMismatch if it doesn't.
+func withVerifiedNames() fsTablePersisterOption {
+	return func(ftp *fsTablePersister) {
+		ftp.verifyNames = true
+	}
+}
+
+// withCopyBuffer makes the persister copy source data through a buffer of |copyBufferSize| bytes when conjoining
+// tables. A larger buffer means fewer reads of each source, which pays off when sources are on high-latency storage.
+func withCopyBuffer(copyBufferSize int) fsTablePersisterOption {
+	d.PanicIfTrue(copyBufferSize <= 0)
+	return func(ftp *fsTablePersister) {
+		ftp.copyBufferSize = copyBufferSize
+	}
+}
+
+// withAlignment makes the persister pad the tables it persists and conjoins so that their indices begin at a
+// multiple of |alignment| bytes, which can speed up reads on block storage. The padding is recorded in each table's
+// footer, so padded tables can be opened by any tablePersister of this version, but not by earlier versions, which
+// reject their footers; stores that may contain them must use a manifest of extendedTablesStorageVersion.
+func withAlignment(alignment uint64) fsTablePersisterOption {
+	d.PanicIfTrue(alignment == 0 || alignment > math.MaxUint32)
+	return func(ftp *fsTablePersister) {
+		ftp.alignment = alignment
+	}
+}
+
+// withReadAhead makes the persister's table readers advise the kernel to read |readAhead| bytes ahead of sequential
+// reads, such as streaming or extracting a table. A larger window speeds up large scans, while a smaller one wastes
+// less I/O on short ones.
+func withReadAhead(readAhead uint64) fsTablePersisterOption {
+	d.PanicIfTrue(readAhead == 0)
+	return func(ftp *fsTablePersister) {
+		ftp.readAhead = readAhead
+	}
+}
+
+// withReadRetries makes the persister's conjoins retry reading a source up to |retries| times after a transient
+// error, such as a timeout reading from network storage, rather than failing. Retries pick up where the failed read
+// left off. Other errors still fail the conjoin.
+func withReadRetries(retries int) fsTablePersisterOption {
+	d.PanicIfTrue(retries <= 0)
+	return func(ftp *fsTablePersister) {
+		ftp.readRetries = retries
+	}
+}
+
+// withTableHasher makes the persister name the tables it persists and conjoins with |hasher|. Tables named by any
+// tableHasher can be opened, whichever one the persister writes with. Versions that predate tableHashers can only read
+// tables named by sha512TableHasher, so stores that may contain other tables must use a manifest of
+// extendedTablesStorageVersion.
+func withTableHasher(hasher *tableHasher) fsTablePersisterOption {
+	d.PanicIfTrue(hasher == nil)
+	return func(ftp *fsTablePersister) {
+		ftp.hasher = hasher
+	}
 }
 
 type fsTablePersister struct {
 	dir        string
 	fc         *fdCache
 	indexCache *indexCache
+	mmapPool   *mmapPool
+	shrinker   *shrinkBatcher
+
+	// copyBufferSize is the size of the buffer conjoins copy source data through. If it is zero, io.CopyBuffer's
+	// default is used.
+	copyBufferSize int
+
+	autoConjoiner *autoConjoiner
+
+	// verifyNames says whether Open checks that tables' content addresses match their names
+	verifyNames bool
+
+	// hasher names the tables the persister writes. If it is nil, sha512TableHasher is used.
+	hasher *tableHasher
+
+	// alignment, if non-zero, is the multiple of bytes at which the indices of the tables the persister writes begin
+	alignment uint64
+
+	// readAhead, if non-zero, is the number of bytes the kernel is advised to read ahead of sequential reads of the
+	// tables the persister opens
+	readAhead uint64
+
+	// readRetries is the number of times conjoins retry reading a source after a transient error
+	readRetries int
+}
+
+// Close stops any automatic conjoin the persister is running, and returns the error from the last one that failed.
+// It also drops the persister's mmapPool, so it must only be called once the tables opened by the persister are
+// closed.
+func (ftp *fsTablePersister) Close() error {
+	var err error
+	if ftp.autoConjoiner != nil {
+		err = ftp.autoConjoiner.close()
+	}
+
+	if ftp.mmapPool != nil {
+		dropErr := ftp.mmapPool.Drop()
+
+		if err == nil {
+			err = dropErr
+		}
+	}
+
+	return err
+}
+
+func (ftp *fsTablePersister) tableHasher() *tableHasher {
+	if ftp.hasher == nil {
+		return sha512TableHasher
+	}
+	return ftp.hasher
 }
 
 func (ftp *fsTablePersister) Open(ctx context.Context, name addr, chunkCount uint32, stats *Stats) (chunkSource, error) {
-	return newMmapTableReader(ftp.dir, name, chunkCount, ftp.indexCache, ftp.fc)
+	cs, err := ftp.openInDir(ftp.dir, name, chunkCount)
+
+	if err != nil || !ftp.verifyNames {
+		return cs, err
+	}
+
+	err = verifyTableName(cs, name)
+
+	if err != nil {
+		cs.Close()
+		return nil, err
+	}
+
+	return cs, nil
 }
 
 func (ftp *fsTablePersister) Persist(ctx context.Context, mt *memTable, haver chunkReader, stats *Stats) (chunkSource, error) {
-	name, data, chunkCount, err := mt.write(haver, stats)
+	name, data, chunkCount, err := mt.writeHashed(haver, stats, ftp.tableHasher())
 
 	if err != nil {
 		return emptyChunkSource{}, err
@@ -64,11 +230,38 @@ func (ftp *fsTablePersister) Persist(ctx context.Context, mt *memTable, haver ch
 	return ftp.persistTable(ctx, name, data, chunkCount, stats)
 }
 
+// PersistWithAddrs is like Persist, but also returns the addresses of the chunks written to the new table, which
+// excludes the chunks |mt| shares with |haver|.
+func (ftp *fsTablePersister) PersistWithAddrs(ctx context.Context, mt *memTable, haver chunkReader, stats *Stats) (chunkSource, []addr, error) {
+	name, data, chunkCount, err := mt.writeHashed(haver, stats, ftp.tableHasher())
+
+	if err != nil {
+		return emptyChunkSource{}, nil, err
+	}
+
+	cs, err := ftp.persistTable(ctx, name, data, chunkCount, stats)
+
+	if err != nil {
+		return cs, nil, err
+	}
+
+	return cs, mt.writtenAddrs(), nil
+}
+
 func (ftp *fsTablePersister) persistTable(ctx context.Context, name addr, data []byte, chunkCount uint32, stats *Stats) (cs chunkSource, err error) {
 	if chunkCount == 0 {
 		return emptyChunkSource{}, nil
 	}
 
+	if ftp.alignment != 0 {
+		data, err = alignTable(data, ftp.alignment)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var index onHeapTableIndex
 	tempName, err := func() (tempName string, ferr error) {
 		var temp *os.File
 		temp, ferr = tempfiles.MovableTempFileProvider.NewFile(ftp.dir, tempTablePrefix)
@@ -83,20 +276,25 @@ func (ftp *fsTablePersister) persistTable(ctx context.Context, name addr, data [
 			if ferr == nil {
 				ferr = closeErr
 			}
+
+			if ferr != nil {
+				_ = os.Remove(temp.Name())
+			}
 		}()
 
-		_, ferr = io.Copy(temp, bytes.NewReader(data))
+		_, ferr = io.Copy(temp, &contextReader{ctx, bytes.NewReader(data)})
 
 		if ferr != nil {
 			return "", ferr
 		}
 
-		index, ferr := parseTableIndex(data)
+		index, ferr = parseTableIndex(data)
 
 		if ferr != nil {
 			return "", ferr
 		}
 
+		// the index is only cached if that doesn't evict indices in use, as it can be re-parsed if it's needed
 		if ftp.indexCache != nil {
 			ftp.indexCache.lockEntry(name)
 			defer func() {
@@ -106,7 +304,7 @@ func (ftp *fsTablePersister) persistTable(ctx context.Context, name addr, data [
 					ferr = unlockErr
 				}
 			}()
-			ftp.indexCache.put(name, index)
+			ftp.indexCache.putIfCheap(name, index)
 		}
 
 		return temp.Name(), nil
@@ -117,36 +315,125 @@ func (ftp *fsTablePersister) persistTable(ctx context.Context, name addr, data [
 	}
 
 	newName := filepath.Join(ftp.dir, name.String())
-	err = ftp.fc.ShrinkCache()
+	err = ftp.shrinkForPersist(newName)
 
-	if err != nil {
-		return nil, err
+	if err == nil {
+		err = ctx.Err()
 	}
 
-	err = os.Rename(tempName, newName)
+	if err == nil {
+		err = renameTableFile(tempName, newName)
+	}
 
 	if err != nil {
+		_ = os.Remove(tempName)
 		return nil, err
 	}
 
-	return ftp.Open(ctx, name, chunkCount, stats)
+	if ftp.autoConjoiner != nil {
+		ftp.autoConjoiner.maybeConjoin(ftp.dir)
+	}
+
+	if ftp.indexCache == nil {
+		return ftp.Open(ctx, name, chunkCount, stats)
+	}
+
+	// the new table is read with the index parsed above, rather than through the cache, so that the read doesn't
+	// count as a use of its cache entry
+	return newMmapTableReaderWithIndex(ftp.dir, name, index, ftp.fc, ftp.mmapPool, ftp.readAhead), nil
+}
+
+// shrinkForPersist shrinks the fd cache before a table is renamed to |path|.
+func (ftp *fsTablePersister) shrinkForPersist(path string) error {
+	if ftp.shrinker == nil {
+		return ftp.fc.ShrinkCache()
+	}
+	return ftp.shrinker.shrink(path)
+}
+
+// shrinkBatcher limits how often an fdCache is shrunk by a series of persists.
+type shrinkBatcher struct {
+	fc     *fdCache
+	every  int
+	window time.Duration
+
+	mu      sync.Mutex
+	pending int
+	last    time.Time
+	shrinks int
+}
+
+// shrink shrinks the cache if a shrink is due. Otherwise only the handle for |path|, which is about to be
+// replaced, is removed.
+func (sb *shrinkBatcher) shrink(path string) error {
+	sb.mu.Lock()
+	defer sb.mu.Unlock()
+
+	sb.pending++
+	due := sb.every <= 1 && sb.window <= 0
+	if sb.every > 0 && sb.pending >= sb.every {
+		due = true
+	}
+	if sb.window > 0 && time.Since(sb.last) >= sb.window {
+		due = true
+	}
+
+	if !due {
+		return sb.fc.ShrinkFile(path)
+	}
+
+	sb.pending = 0
+	sb.last = time.Now()
+	sb.shrinks++
+	return sb.fc.ShrinkCache()
 }
 
 func (ftp *fsTablePersister) ConjoinAll(ctx context.Context, sources chunkSources, stats *Stats) (chunkSource, error) {
-	plan, err := planConjoin(sources, stats)
+	return ftp.ConjoinAllInto(ctx, ftp.dir, sources, stats)
+}
+
+// ConjoinAllInto conjoins |sources| into a new table file in |dir|, which needn't be the persister's directory.
+// |sources| are read through their readers, so they may have been opened from anywhere. The returned chunkSource
+// reads the new table file from |dir|.
+func (ftp *fsTablePersister) ConjoinAllInto(ctx context.Context, dir string, sources chunkSources, stats *Stats) (chunkSource, error) {
+	cs, _, err := ftp.conjoinInto(ctx, dir, sources, time.Time{}, stats)
+	return cs, err
+}
+
+// ConjoinAllWithin is ConjoinAll, except that it stops starting to copy sources once |budget| has passed, and
+// conjoins only the sources it has copied by then. The source being copied when the budget runs out is finished,
+// and at least two sources are conjoined, as conjoining one source alone gets nothing done. The sources left out
+// are returned, so that a later call can carry on conjoining them. Sources are copied largest first.
+func (ftp *fsTablePersister) ConjoinAllWithin(ctx context.Context, sources chunkSources, budget time.Duration, stats *Stats) (chunkSource, chunkSources, error) {
+	return ftp.conjoinInto(ctx, ftp.dir, sources, time.Now().Add(budget), stats)
+}
+
+// conjoinInto conjoins |sources| into a new table file in |dir|. If |deadline| isn't zero, no more sources are
+// copied once it has passed, and the sources that weren't copied are returned rather than conjoined.
+func (ftp *fsTablePersister) conjoinInto(ctx context.Context, dir string, sources chunkSources, deadline time.Time, stats *Stats) (chunkSource, chunkSources, error) {
+	hasher := ftp.tableHasher()
+
+	// with a deadline, the table is only planned once it's known which sources it will hold
+	planStats := stats
+	if !deadline.IsZero() {
+		planStats = &Stats{}
+	}
+
+	plan, err := planConjoinHashed(sources, planStats, hasher)
 
 	if err != nil {
-		return emptyChunkSource{}, err
+		return emptyChunkSource{}, nil, err
 	}
 
 	if plan.chunkCount == 0 {
-		return emptyChunkSource{}, nil
+		return emptyChunkSource{}, nil, nil
 	}
 
-	name := nameFromSuffixes(plan.suffixes())
+	var name addr
+	var remainder chunkSources
 	tempName, err := func() (tempName string, ferr error) {
 		var temp *os.File
-		temp, ferr = tempfiles.MovableTempFileProvider.NewFile(ftp.dir, tempTablePrefix)
+		temp, ferr = tempfiles.MovableTempFileProvider.NewFile(dir, tempTablePrefix)
 
 		if ferr != nil {
 			return "", ferr
@@ -158,24 +445,57 @@ func (ftp *fsTablePersister) ConjoinAll(ctx context.Context, sources chunkSource
 			if ferr == nil {
 				ferr = closeErr
 			}
+
+			if ferr != nil {
+				_ = os.Remove(temp.Name())
+			}
 		}()
 
-		for _, sws := range plan.sources.sws {
-			var r io.Reader
-			r, ferr = sws.source.reader(ctx)
+		var buf []byte
+		if ftp.copyBufferSize > 0 {
+			buf = make([]byte, ftp.copyBufferSize)
+		}
+
+		copied := make(chunkSources, 0, len(plan.sources.sws))
+		for i, sws := range plan.sources.sws {
+			if i >= 2 && !deadline.IsZero() && !time.Now().Before(deadline) {
+				for _, rest := range plan.sources.sws[i:] {
+					remainder = append(remainder, rest.source)
+				}
+				break
+			}
+
+			ferr = ftp.copySourceData(ctx, temp, sws, buf)
 
 			if ferr != nil {
 				return "", ferr
 			}
 
-			n, ferr := io.CopyN(temp, r, int64(sws.dataLen))
+			copied = append(copied, sws.source)
+		}
+
+		if !deadline.IsZero() {
+			// sources keep their relative order in a plan of any subset of them, so the data copied matches
+			plan, ferr = planConjoinHashed(copied, stats, hasher)
+
+			if ferr != nil {
+				return "", ferr
+			}
+		}
+
+		name = hasher.name(plan.suffixes())
+
+		if padding := tablePadding(plan.totalCompressedData, ftp.alignment); padding > 0 {
+			_, ferr = temp.Write(make([]byte, padding))
 
 			if ferr != nil {
 				return "", ferr
 			}
 
-			if uint64(n) != sws.dataLen {
-				return "", errors.New("failed to copy all data")
+			ferr = setFooterPadding(plan.mergedIndex, padding)
+
+			if ferr != nil {
+				return "", ferr
 			}
 		}
 
@@ -199,17 +519,128 @@ func (ftp *fsTablePersister) ConjoinAll(ctx context.Context, sources chunkSource
 		return temp.Name(), nil
 	}()
 
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = renameTableFile(tempName, filepath.Join(dir, name.String()))
+
+	if err != nil {
+		_ = os.Remove(tempName)
+		return nil, nil, err
+	}
+
+	cs, err := ftp.openInDir(dir, name, plan.chunkCount)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return cs, remainder, nil
+}
+
+// openInDir opens the table file named |name| in |dir|, holding a shared lock on it while it does.
+func (ftp *fsTablePersister) openInDir(dir string, name addr, chunkCount uint32) (chunkSource, error) {
+	release, err := rLockTableFile(filepath.Join(dir, name.String()))
+
 	if err != nil {
 		return nil, err
 	}
 
-	err = os.Rename(tempName, filepath.Join(ftp.dir, name.String()))
+	cs, err := newMmapTableReader(dir, name, chunkCount, ftp.indexCache, ftp.fc, ftp.mmapPool, ftp.readAhead)
+	releaseErr := release()
 
 	if err != nil {
 		return nil, err
+	} else if releaseErr != nil {
+		cs.Close()
+		return nil, releaseErr
+	}
+
+	return cs, nil
+}
+
+// skippedSource is a source left out of a table conjoined by ConjoinAllSkippingFailures, and the error reading it.
+type skippedSource struct {
+	source chunkSource
+	err    error
+}
+
+// ConjoinAllSkippingFailures is ConjoinAll, except that sources whose index or chunk data can't be read are left out
+// of the conjoined table rather than failing the conjoin. The sources that were left out are returned along with
+// the error reading each of them, so that the caller can report them, and must keep them referenced if they are
+// still needed. Only the remaining sources are conjoined, so a source that fails partway through being copied
+// causes the conjoin to start over without it.
+func (ftp *fsTablePersister) ConjoinAllSkippingFailures(ctx context.Context, sources chunkSources, stats *Stats) (chunkSource, []skippedSource, error) {
+	var skipped []skippedSource
+	healthy := make(chunkSources, 0, len(sources))
+
+	for _, src := range sources {
+		if _, err := src.index(); err != nil {
+			skipped = append(skipped, skippedSource{src, err})
+			continue
+		}
+
+		healthy = append(healthy, src)
+	}
+
+	for {
+		cs, err := ftp.ConjoinAll(ctx, healthy, stats)
+
+		var sre *sourceReadError
+		if !errors.As(err, &sre) || ctx.Err() != nil {
+			return cs, skipped, err
+		}
+
+		skipped = append(skipped, skippedSource{sre.source, sre.err})
+
+		failed, err := sre.source.hash()
+		if err != nil {
+			return nil, skipped, err
+		}
+
+		remaining := make(chunkSources, 0, len(healthy)-1)
+		for _, src := range healthy {
+			h, err := src.hash()
+			if err != nil {
+				return nil, skipped, err
+			}
+
+			if h != failed {
+				remaining = append(remaining, src)
+			}
+		}
+		healthy = remaining
 	}
+}
+
+// sourceReadError is an error reading the chunk data of a source being conjoined.
+type sourceReadError struct {
+	source chunkSource
+	err    error
+}
+
+func (e *sourceReadError) Error() string {
+	return fmt.Sprintf("failed to read table file: %v", e.err)
+}
 
-	return ftp.Open(ctx, name, plan.chunkCount, stats)
+func (e *sourceReadError) Unwrap() error {
+	return e.err
+}
+
+// readErrRecorder is an io.Reader that records the first error from reading |r|, so that read errors can be told
+// apart from write errors when copying.
+type readErrRecorder struct {
+	r   io.Reader
+	err error
+}
+
+func (rr *readErrRecorder) Read(p []byte) (int, error) {
+	n, err := rr.r.Read(p)
+	if err != nil && err != io.EOF && rr.err == nil {
+		rr.err = err
+	}
+	return n, err
 }
 
 func (ftp *fsTablePersister) PruneTableFiles(ctx context.Context, contents manifestContents) error {
@@ -243,12 +674,8 @@ func (ftp *fsTablePersister) PruneTableFiles(ctx context.Context, contents manif
 			continue
 		}
 
-		if len(info.Name()) != 32 {
-			continue // not a table file
-		}
-
-		addy, err := parseAddr(info.Name())
-		if err != nil {
+		addy, ok := tableFileAddr(info.Name())
+		if !ok {
 			continue // not a table file
 		}
 
@@ -268,3 +695,172 @@ func (ftp *fsTablePersister) PruneTableFiles(ctx context.Context, contents manif
 
 	return nil
 }
+
+// selfTestChunkSize is the size of the random chunk written by SelfTest
+const selfTestChunkSize = 64
+
+// SelfTest checks that tables can be written to and read back from the persister's directory, for use as a health
+// probe. It persists a table holding a single random chunk, opens it, checks that the chunk reads back intact, and
+// removes the table, whether or not the checks succeed.
+func (ftp *fsTablePersister) SelfTest(ctx context.Context) (err error) {
+	data := make([]byte, selfTestChunkSize)
+	if _, err := rand.Read(data); err != nil {
+		return err
+	}
+
+	a := computeAddr(data)
+	mt := newMemTable(selfTestChunkSize * 2)
+	mt.addChunk(a, data)
+
+	name, tableData, chunkCount, err := mt.writeHashed(nil, &Stats{}, ftp.tableHasher())
+
+	if err != nil {
+		return err
+	}
+
+	// the probe table is never added to the manifest, so it mustn't trigger an automatic conjoin
+	probe := *ftp
+	probe.autoConjoiner = nil
+
+	tablePath := filepath.Join(ftp.dir, name.String())
+	defer func() {
+		_ = ftp.fc.ShrinkFile(tablePath)
+		removeErr := os.Remove(tablePath)
+
+		if err == nil && removeErr != nil && !os.IsNotExist(removeErr) {
+			err = fmt.Errorf("self test could not remove table file %s: %w", tablePath, removeErr)
+		}
+	}()
+
+	persisted, err := probe.persistTable(ctx, name, tableData, chunkCount, &Stats{})
+
+	if err != nil {
+		return fmt.Errorf("self test could not write a table file to %s: %w", ftp.dir, err)
+	}
+
+	err = persisted.Close()
+
+	if err != nil {
+		return err
+	}
+
+	opened, err := probe.Open(ctx, name, chunkCount, &Stats{})
+
+	if err != nil {
+		return fmt.Errorf("self test could not open table file %s: %w", tablePath, err)
+	}
+
+	defer func() {
+		closeErr := opened.Close()
+
+		if err == nil {
+			err = closeErr
+		}
+	}()
+
+	read, err := opened.get(ctx, a, &Stats{})
+
+	if err != nil {
+		return fmt.Errorf("self test could not read table file %s: %w", tablePath, err)
+	}
+
+	if !bytes.Equal(data, read) {
+		return fmt.Errorf("self test read corrupt data from table file %s", tablePath)
+	}
+
+	return nil
+}
+
+// tableFileAddr returns the address of the table file named |name|, and false if |name| does not name a table file.
+func tableFileAddr(name string) (addr, bool) {
+	if len(name) != 32 {
+		return addr{}, false
+	}
+
+	a, err := parseAddr(name)
+	if err != nil {
+		return addr{}, false
+	}
+
+	return a, true
+}
+
+// ListTables returns the addresses of the table files in |ftp|'s directory, in sorted order.
+func (ftp *fsTablePersister) ListTables() ([]addr, error) {
+	fileInfos, err := ioutil.ReadDir(ftp.dir)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var addrs []addr
+	for _, info := range fileInfos {
+		if info.IsDir() {
+			continue
+		}
+
+		if a, ok := tableFileAddr(info.Name()); ok {
+			addrs = append(addrs, a)
+		}
+	}
+
+	sort.Sort(addrSlice(addrs))
+	return addrs, nil
+}
+
+// tableFileReconciliation categorizes table files by whether a manifest references them and whether they are on
+// disk.
+type tableFileReconciliation struct {
+	// present are referenced and on disk.
+	present []addr
+	// missing are referenced but not on disk.
+	missing []addr
+	// orphaned are on disk but not referenced, and are candidates for garbage collection.
+	orphaned []addr
+}
+
+// ReconcileTableFiles compares the table files in |ftp|'s directory against |referenced|, the set of addresses
+// referenced by a manifest. Each list in the result is sorted.
+func (ftp *fsTablePersister) ReconcileTableFiles(referenced map[addr]struct{}) (tableFileReconciliation, error) {
+	onDisk, err := ftp.ListTables()
+
+	if err != nil {
+		return tableFileReconciliation{}, err
+	}
+
+	var rec tableFileReconciliation
+	seen := make(map[addr]struct{}, len(onDisk))
+	for _, a := range onDisk {
+		seen[a] = struct{}{}
+
+		if _, ok := referenced[a]; ok {
+			rec.present = append(rec.present, a)
+		} else {
+			rec.orphaned = append(rec.orphaned, a)
+		}
+	}
+
+	for a := range referenced {
+		if _, ok := seen[a]; !ok {
+			rec.missing = append(rec.missing, a)
+		}
+	}
+
+	sort.Sort(addrSlice(rec.missing))
+	return rec, nil
+}
+
+// contextReader is an io.Reader that stops reading from |rd| once |ctx| is done, so that copies from it can be
+// cancelled.
+type contextReader struct {
+	ctx context.Context
+	rd  io.Reader
+}
+
+func (cr *contextReader) Read(p []byte) (int, error) {
+	if err := cr.ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	return cr.rd.Read(p)
+}
@@ -24,13 +24,19 @@ package nbs
 import (
 	"context"
 	"crypto/rand"
+	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"runtime"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestFSTableCacheOnOpen(t *testing.T) {
@@ -81,7 +87,7 @@ func TestFSTableCacheOnOpen(t *testing.T) {
 	assert.NoError(err)
 }
 
-func makeTempDir(t *testing.T) string {
+func makeTempDir(t testing.TB) string {
 	dir, err := ioutil.TempDir("", "")
 	assert.NoError(t, err)
 	return dir
@@ -103,6 +109,176 @@ func writeTableData(dir string, chunx ...[]byte) (addr, error) {
 	return name, nil
 }
 
+func TestVerifyingFSTablePersisterOpen(t *testing.T) {
+	ctx := context.Background()
+	dir := makeTempDir(t)
+	defer os.RemoveAll(dir)
+	fc := newFDCache(defaultMaxTables)
+	defer fc.Drop()
+	fts := newFSTablePersister(dir, fc, nil, withVerifiedNames())
+
+	name, err := writeTableData(dir, testChunks...)
+	require.NoError(t, err)
+	src, err := fts.Open(ctx, name, uint32(len(testChunks)), &Stats{})
+	require.NoError(t, err)
+	require.NoError(t, src.Close())
+
+	// tables written by Persist and ConjoinAll are named by the same rule
+	persisted, err := persistTableData(fts, []byte("persisted"))
+	require.NoError(t, err)
+	conjoined, err := fts.ConjoinAll(ctx, chunkSources{src, persisted}, &Stats{})
+	require.NoError(t, err)
+	src, err = fts.Open(ctx, mustAddr(conjoined.hash()), mustUint32(conjoined.count()), &Stats{})
+	require.NoError(t, err)
+	require.NoError(t, src.Close())
+
+	// a table file that was swapped for another
+	swapped := computeAddr([]byte("swapped"))
+	data, err := ioutil.ReadFile(filepath.Join(dir, name.String()))
+	require.NoError(t, err)
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, swapped.String()), data, 0666))
+
+	_, err = fts.Open(ctx, swapped, uint32(len(testChunks)), &Stats{})
+	assert.True(t, errors.Is(err, ErrTableNameMismatch), "unexpected error: %v", err)
+
+	src, err = newFSTablePersister(dir, fc, nil).Open(ctx, swapped, uint32(len(testChunks)), &Stats{})
+	require.NoError(t, err, "only the verifying persister checks names")
+	require.NoError(t, src.Close())
+}
+
+func TestFSTablePersisterWithHasher(t *testing.T) {
+	ctx := context.Background()
+	names := make(map[*tableHasher]addr)
+
+	for _, hasher := range tableHashers {
+		dir := makeTempDir(t)
+		defer os.RemoveAll(dir)
+		fc := newFDCache(defaultMaxTables)
+		defer fc.Drop()
+		fts := newFSTablePersister(dir, fc, nil, withTableHasher(hasher))
+		verifying := newFSTablePersister(dir, fc, nil, withVerifiedNames())
+
+		src, err := persistTableData(fts, testChunks...)
+		require.NoError(t, err)
+		names[hasher] = mustAddr(src.hash())
+
+		// tables are opened by whichever hasher named them, so the default persister can verify them
+		opened, err := verifying.Open(ctx, mustAddr(src.hash()), mustUint32(src.count()), &Stats{})
+		require.NoError(t, err)
+		ti, err := opened.index()
+		require.NoError(t, err)
+		assert.Equal(t, hasher, ti.Hasher())
+		require.NoError(t, opened.Close())
+
+		// sources named by other hashers are renamed by the conjoin
+		other, err := persistTableData(newFSTablePersister(dir, fc, nil), []byte("other"))
+		require.NoError(t, err)
+		conjoined, err := fts.ConjoinAll(ctx, chunkSources{src, other}, &Stats{})
+		require.NoError(t, err)
+
+		opened, err = verifying.Open(ctx, mustAddr(conjoined.hash()), mustUint32(conjoined.count()), &Stats{})
+		require.NoError(t, err)
+		ti, err = opened.index()
+		require.NoError(t, err)
+		assert.Equal(t, hasher, ti.Hasher())
+		assertChunksInReader(append(testChunks, []byte("other")), opened, assert.New(t))
+		require.NoError(t, opened.Close())
+	}
+
+	assert.NotEqual(t, names[sha512TableHasher], names[sha256TableHasher])
+}
+
+func TestFSTablePersisterCombinedOptions(t *testing.T) {
+	ctx := context.Background()
+	dir := makeTempDir(t)
+	defer os.RemoveAll(dir)
+	fc := newFDCache(defaultMaxTables)
+	defer fc.Drop()
+
+	// options configure independent parts of the persister, so any of them can be combined
+	fts := newFSTablePersister(dir, fc, nil, withTableHasher(sha256TableHasher), withAlignment(4096), withVerifiedNames(), withCopyBuffer(1024))
+
+	src, err := persistTableData(fts, testChunks...)
+	require.NoError(t, err)
+	other, err := persistTableData(fts, []byte("other"))
+	require.NoError(t, err)
+	conjoined, err := fts.ConjoinAll(ctx, chunkSources{src, other}, &Stats{})
+	require.NoError(t, err)
+
+	opened, err := fts.Open(ctx, mustAddr(conjoined.hash()), mustUint32(conjoined.count()), &Stats{})
+	require.NoError(t, err)
+	defer opened.Close()
+
+	ti, err := opened.index()
+	require.NoError(t, err)
+	assert.Equal(t, sha256TableHasher, ti.Hasher())
+	assert.NotZero(t, ti.Padding())
+	assertChunksInReader(append(testChunks, []byte("other")), opened, assert.New(t))
+}
+
+func TestFSTablePersisterWithAlignment(t *testing.T) {
+	ctx := context.Background()
+
+	for _, alignment := range []uint64{100, 4096} {
+		dir := makeTempDir(t)
+		defer os.RemoveAll(dir)
+		fc := newFDCache(defaultMaxTables)
+		defer fc.Drop()
+		fts := newFSTablePersister(dir, fc, nil, withAlignment(alignment))
+		verifying := newFSTablePersister(dir, fc, nil, withVerifiedNames())
+
+		assertAligned := func(cs chunkSource, chunks [][]byte) {
+			name := mustAddr(cs.hash())
+			fi, err := os.Stat(filepath.Join(dir, name.String()))
+			require.NoError(t, err)
+
+			// the table is named by its chunks, not its padding, and can be opened by any persister
+			opened, err := verifying.Open(ctx, name, mustUint32(cs.count()), &Stats{})
+			require.NoError(t, err)
+			defer opened.Close()
+
+			ti, err := opened.index()
+			require.NoError(t, err)
+			indexOffset := uint64(fi.Size()) - indexSize(ti.ChunkCount()) - footerSize
+			assert.Zero(t, indexOffset%alignment, "index at %d isn't aligned to %d", indexOffset, alignment)
+			assert.Equal(t, uint64(fi.Size()), ti.TableFileSize())
+			assert.Equal(t, indexOffset, calcChunkDataLen(ti)+ti.Padding())
+			assertChunksInReader(chunks, opened, assert.New(t))
+
+			r, err := opened.reader(ctx)
+			require.NoError(t, err)
+			data, err := ioutil.ReadAll(r)
+			require.NoError(t, err)
+			assert.Len(t, data, int(fi.Size()))
+		}
+
+		padded, err := persistTableData(fts, testChunks...)
+		require.NoError(t, err)
+		assertAligned(padded, testChunks)
+
+		unpadded, err := persistTableData(newFSTablePersister(dir, fc, nil), []byte("unpadded"))
+		require.NoError(t, err)
+		unpaddedData, err := ioutil.ReadFile(filepath.Join(dir, mustAddr(unpadded.hash()).String()))
+		require.NoError(t, err)
+
+		// padding doesn't change a table's name
+		alignedData, err := alignTable(unpaddedData, alignment)
+		require.NoError(t, err)
+		ti, err := parseTableIndex(alignedData)
+		require.NoError(t, err)
+		assert.Equal(t, mustAddr(unpadded.hash()), tableNameFromIndex(ti))
+
+		// padded sources are conjoined without their padding
+		conjoined, err := fts.ConjoinAll(ctx, chunkSources{padded, unpadded}, &Stats{})
+		require.NoError(t, err)
+		assertAligned(conjoined, append(testChunks, []byte("unpadded")))
+
+		conjoined, err = newFSTablePersister(dir, fc, nil).ConjoinAll(ctx, chunkSources{padded, unpadded}, &Stats{})
+		require.NoError(t, err)
+		assertChunksInReader(append(testChunks, []byte("unpadded")), conjoined, assert.New(t))
+	}
+}
+
 func removeTables(dir string, names ...addr) error {
 	for _, name := range names {
 		if err := os.Remove(filepath.Join(dir, name.String())); err != nil {
@@ -112,6 +288,90 @@ func removeTables(dir string, names ...addr) error {
 	return nil
 }
 
+func TestFSTablePersisterWithReadAhead(t *testing.T) {
+	ctx := context.Background()
+	dir := makeTempDir(t)
+	defer os.RemoveAll(dir)
+	fc := newFDCache(defaultMaxTables)
+	defer fc.Drop()
+	fts := newFSTablePersister(dir, fc, nil, withReadAhead(1<<20))
+
+	persisted, err := persistTableData(fts, testChunks...)
+	require.NoError(t, err)
+	opened, err := fts.Open(ctx, mustAddr(persisted.hash()), mustUint32(persisted.count()), &Stats{})
+	require.NoError(t, err)
+	defer opened.Close()
+
+	for _, src := range []chunkSource{persisted, opened} {
+		for _, c := range testChunks {
+			data, err := src.get(ctx, computeAddr(c), &Stats{})
+			require.NoError(t, err)
+			assert.Equal(t, c, data)
+		}
+
+		buff, err := ioutil.ReadFile(filepath.Join(dir, mustAddr(src.hash()).String()))
+		require.NoError(t, err)
+		r, err := src.reader(ctx)
+		require.NoError(t, err)
+		read, err := ioutil.ReadAll(r)
+		require.NoError(t, err)
+		assert.Equal(t, buff, read)
+	}
+}
+
+func TestFSTablePersisterSelfTest(t *testing.T) {
+	ctx := context.Background()
+	fc := newFDCache(defaultMaxTables)
+	defer fc.Drop()
+
+	t.Run("healthy", func(t *testing.T) {
+		dir := makeTempDir(t)
+		defer os.RemoveAll(dir)
+
+		fts := newFSTablePersister(dir, fc, nil).(*fsTablePersister)
+		require.NoError(t, fts.SelfTest(ctx))
+		require.NoError(t, fts.SelfTest(ctx))
+
+		// the probe table is removed
+		count, err := countTableFiles(dir)
+		require.NoError(t, err)
+		assert.Zero(t, count)
+	})
+
+	t.Run("missing dir", func(t *testing.T) {
+		dir := makeTempDir(t)
+		require.NoError(t, os.RemoveAll(dir))
+
+		err := newFSTablePersister(dir, fc, nil).(*fsTablePersister).SelfTest(ctx)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "could not write a table file")
+	})
+
+	t.Run("read only dir", func(t *testing.T) {
+		if runtime.GOOS == "windows" {
+			t.Skip("directory permissions don't prevent writes on windows")
+		}
+
+		dir := makeTempDir(t)
+		defer os.RemoveAll(dir)
+		require.NoError(t, os.Chmod(dir, 0555))
+		defer os.Chmod(dir, 0755)
+
+		if f, err := os.Create(filepath.Join(dir, "writable")); err == nil {
+			f.Close()
+			t.Skip("read only directories are writable by this user")
+		}
+
+		err := newFSTablePersister(dir, fc, nil).(*fsTablePersister).SelfTest(ctx)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "could not write a table file")
+
+		count, err := countTableFiles(dir)
+		require.NoError(t, err)
+		assert.Zero(t, count)
+	})
+}
+
 func TestFSTablePersisterPersist(t *testing.T) {
 	assert := assert.New(t)
 	dir := makeTempDir(t)
@@ -132,6 +392,134 @@ func TestFSTablePersisterPersist(t *testing.T) {
 	}
 }
 
+func TestFSTablePersisterPersistDedupStats(t *testing.T) {
+	dir := makeTempDir(t)
+	defer os.RemoveAll(dir)
+	fc := newFDCache(defaultMaxTables)
+	defer fc.Drop()
+	fts := newFSTablePersister(dir, fc, nil)
+
+	existing, err := persistTableData(fts, testChunks[:2]...)
+	require.NoError(t, err)
+	defer existing.Close()
+
+	// a batch overlapping the existing table in all but one chunk
+	mt := newMemTable(testMemTableSize)
+	for _, c := range testChunks {
+		require.True(t, mt.addChunk(computeAddr(c), c))
+	}
+
+	stats := &Stats{}
+	src, err := fts.Persist(context.Background(), mt, existing, stats)
+	require.NoError(t, err)
+	defer src.Close()
+
+	assert.Equal(t, uint64(1), stats.DedupedChunksPerPersist.Samples())
+	assert.Equal(t, uint64(2), stats.DedupedChunksPerPersist.Sum())
+	assert.Equal(t, uint64(1), stats.ChunksPerPersist.Sum())
+	assert.Equal(t, uint32(1), mustUint32(src.count()))
+	assertChunksInReader(testChunks[2:], src, assert.New(t))
+}
+
+func TestFSTablePersisterPersistWithAddrs(t *testing.T) {
+	dir := makeTempDir(t)
+	defer os.RemoveAll(dir)
+	fc := newFDCache(defaultMaxTables)
+	defer fc.Drop()
+	fts := newFSTablePersister(dir, fc, nil).(*fsTablePersister)
+
+	existing, err := persistTableData(fts, testChunks[0], testChunks[2])
+	require.NoError(t, err)
+	defer existing.Close()
+
+	// a batch overlapping the existing table in all but one chunk
+	mt := newMemTable(testMemTableSize)
+	for _, c := range testChunks {
+		require.True(t, mt.addChunk(computeAddr(c), c))
+	}
+
+	src, written, err := fts.PersistWithAddrs(context.Background(), mt, existing, &Stats{})
+	require.NoError(t, err)
+	defer src.Close()
+
+	assert.Equal(t, []addr{computeAddr(testChunks[1])}, written)
+	assertChunksInReader(testChunks[1:2], src, assert.New(t))
+
+	// without a haver every chunk is new
+	mt = newMemTable(testMemTableSize)
+	for _, c := range testChunks {
+		require.True(t, mt.addChunk(computeAddr(c), c))
+	}
+
+	src, written, err = fts.PersistWithAddrs(context.Background(), mt, nil, &Stats{})
+	require.NoError(t, err)
+	defer src.Close()
+
+	assert.Equal(t, []addr{computeAddr(testChunks[0]), computeAddr(testChunks[1]), computeAddr(testChunks[2])}, written)
+}
+
+func TestBulkFSTablePersisterPersist(t *testing.T) {
+	assert := assert.New(t)
+	dir := makeTempDir(t)
+	defer os.RemoveAll(dir)
+	fc := newFDCache(defaultMaxTables)
+	defer fc.Drop()
+	fts := newFSTablePersister(dir, fc, nil, withBatchedShrinks(8, time.Hour))
+
+	const numTables = 20
+	srcs := make([]chunkSource, 0, numTables+1)
+	for i := 0; i < numTables; i++ {
+		src, err := persistTableData(fts, []byte{byte(i)})
+		assert.NoError(err)
+		srcs = append(srcs, src)
+	}
+
+	// persisting an existing table replaces its file, which may still be cached
+	src, err := persistTableData(fts, []byte{0})
+	assert.NoError(err)
+	srcs = append(srcs, src)
+
+	for i, src := range srcs {
+		assert.True(src.has(computeAddr([]byte{byte(i % numTables)})))
+	}
+	assert.Equal(numTables/8, fts.(*fsTablePersister).shrinker.shrinks)
+}
+
+func BenchmarkBulkFSTablePersisterPersist(b *testing.B) {
+	benchmarks := []struct {
+		name        string
+		shrinkEvery int
+	}{
+		{"every persist", 1},
+		{"every 64 persists", 64},
+	}
+
+	for _, bm := range benchmarks {
+		b.Run(bm.name, func(b *testing.B) {
+			dir, err := ioutil.TempDir("", "")
+			if err != nil {
+				b.Fatal(err)
+			}
+			defer os.RemoveAll(dir)
+			fc := newFDCache(defaultMaxTables)
+			defer fc.Drop()
+			fts := newFSTablePersister(dir, fc, nil, withBatchedShrinks(bm.shrinkEvery, 0))
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				c := []byte(fmt.Sprintf("chunk %d", i))
+				if _, err := persistTableData(fts, c); err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.StopTimer()
+
+			shrinks := fts.(*fsTablePersister).shrinker.shrinks
+			b.ReportMetric(float64(shrinks)/float64(b.N), "shrinks/op")
+		})
+	}
+}
+
 func persistTableData(p tablePersister, chunx ...[]byte) (src chunkSource, err error) {
 	mt := newMemTable(testMemTableSize)
 	for _, c := range chunx {
@@ -198,6 +586,61 @@ func TestFSTablePersisterCacheOnPersist(t *testing.T) {
 	assert.NoError(err)
 }
 
+func TestFSTablePersisterPersistKeepsHotIndices(t *testing.T) {
+	ctx := context.Background()
+	dir := makeTempDir(t)
+	defer os.RemoveAll(dir)
+	fc := newFDCache(defaultMaxTables)
+	defer fc.Drop()
+
+	// room for two single chunk indices
+	ic := newIndexCache(2 * indexCacheSize(onHeapTableIndex{chunkCount: 1}))
+	fts := newFSTablePersister(dir, fc, ic)
+
+	var hot []addr
+	for _, c := range [][]byte{[]byte("hot1"), []byte("hot2")} {
+		src, err := persistTableData(fts, c)
+		require.NoError(t, err)
+		hot = append(hot, mustAddr(src.hash()))
+
+		src, err = fts.Open(ctx, mustAddr(src.hash()), 1, &Stats{})
+		require.NoError(t, err)
+		assertChunksInReader([][]byte{c}, src, assert.New(t))
+	}
+
+	cold, err := persistTableData(fts, []byte("cold"))
+	require.NoError(t, err)
+	assertChunksInReader([][]byte{[]byte("cold")}, cold, assert.New(t))
+
+	for _, name := range hot {
+		_, ok := ic.get(name)
+		assert.True(t, ok, "hot index was evicted")
+	}
+	_, ok := ic.get(mustAddr(cold.hash()))
+	assert.False(t, ok, "just written index was cached")
+
+	// once it's been opened, the new table's index is cached as usual
+	src, err := fts.Open(ctx, mustAddr(cold.hash()), 1, &Stats{})
+	require.NoError(t, err)
+	assertChunksInReader([][]byte{[]byte("cold")}, src, assert.New(t))
+	_, ok = ic.get(mustAddr(cold.hash()))
+	assert.True(t, ok)
+
+	// just written indices do evict indices that haven't been used
+	ic = newIndexCache(2 * indexCacheSize(onHeapTableIndex{chunkCount: 1}))
+	fts = newFSTablePersister(dir, fc, ic)
+	var unused []addr
+	for _, c := range [][]byte{[]byte("unused1"), []byte("unused2"), []byte("unused3")} {
+		src, err := persistTableData(fts, c)
+		require.NoError(t, err)
+		unused = append(unused, mustAddr(src.hash()))
+	}
+	_, ok = ic.get(unused[0])
+	assert.False(t, ok)
+	_, ok = ic.get(unused[2])
+	assert.True(t, ok)
+}
+
 func TestFSTablePersisterConjoinAll(t *testing.T) {
 	assert := assert.New(t)
 	assert.True(len(testChunks) > 1, "Whoops, this test isn't meaningful")
@@ -236,6 +679,335 @@ func TestFSTablePersisterConjoinAll(t *testing.T) {
 	assert.Len(present, len(sources))
 }
 
+func TestFSTablePersisterConjoinAllInto(t *testing.T) {
+	dirA, dirB, outDir := makeTempDir(t), makeTempDir(t), makeTempDir(t)
+	defer os.RemoveAll(dirA)
+	defer os.RemoveAll(dirB)
+	defer os.RemoveAll(outDir)
+	fc := newFDCache(defaultMaxTables)
+	defer fc.Drop()
+	ftsA := newFSTablePersister(dirA, fc, nil)
+	ftsB := newFSTablePersister(dirB, fc, nil)
+
+	srcA, err := persistTableData(ftsA, testChunks[0])
+	require.NoError(t, err)
+	srcB, err := persistTableData(ftsB, testChunks[1:]...)
+	require.NoError(t, err)
+
+	src, err := ftsA.(*fsTablePersister).ConjoinAllInto(context.Background(), outDir, chunkSources{srcA, srcB}, &Stats{})
+	require.NoError(t, err)
+	defer src.Close()
+	assert.Equal(t, uint32(len(testChunks)), mustUint32(src.count()))
+	assertChunksInReader(testChunks, src, assert.New(t))
+
+	_, err = os.Stat(filepath.Join(outDir, mustAddr(src.hash()).String()))
+	assert.NoError(t, err)
+	for _, dir := range []string{dirA, dirB} {
+		n, err := countTableFiles(dir)
+		require.NoError(t, err)
+		assert.Equal(t, 1, n)
+	}
+}
+
+func TestFSTablePersisterConjoinAllWithin(t *testing.T) {
+	dir := makeTempDir(t)
+	defer os.RemoveAll(dir)
+	fc := newFDCache(defaultMaxTables)
+	defer fc.Drop()
+	fts := newFSTablePersister(dir, fc, nil).(*fsTablePersister)
+
+	sources, chunks := openRandomTables(t, fts, dir, 5, 64)
+	defer func() {
+		for _, src := range sources {
+			src.Close()
+		}
+	}()
+
+	// the budget runs out before the third source is started
+	src, remainder, err := fts.ConjoinAllWithin(context.Background(), sources, time.Nanosecond, &Stats{})
+	require.NoError(t, err)
+	defer src.Close()
+	assert.Equal(t, uint32(2), mustUint32(src.count()))
+	require.Len(t, remainder, 3)
+
+	var conjoined, left [][]byte
+	for _, c := range chunks {
+		if ok, err := src.has(computeAddr(c)); assert.NoError(t, err) && ok {
+			conjoined = append(conjoined, c)
+		} else {
+			left = append(left, c)
+		}
+	}
+	assert.Len(t, conjoined, 2)
+	assertChunksInReader(conjoined, src, assert.New(t))
+	for _, rem := range remainder {
+		assertChunksNotInReader(conjoined, rem, assert.New(t))
+	}
+
+	_, err = os.Stat(filepath.Join(dir, mustAddr(src.hash()).String()))
+	assert.NoError(t, err)
+
+	// the remainder is conjoined by a later call with enough time
+	rest, remainder, err := fts.ConjoinAllWithin(context.Background(), remainder, time.Hour, &Stats{})
+	require.NoError(t, err)
+	defer rest.Close()
+	assert.Empty(t, remainder)
+	assert.Equal(t, uint32(3), mustUint32(rest.count()))
+	assertChunksInReader(left, rest, assert.New(t))
+}
+
+// openRandomTables writes |n| tables to |dir|, each holding a single chunk of |size| random bytes, and opens them
+// with |p|.
+func openRandomTables(tb testing.TB, p tablePersister, dir string, n, size int) (chunkSources, [][]byte) {
+	sources := make(chunkSources, n)
+	chunks := make([][]byte, n)
+	for i := range sources {
+		chunks[i] = make([]byte, size)
+		_, err := rand.Read(chunks[i])
+		require.NoError(tb, err)
+		name, err := writeTableData(dir, chunks[i])
+		require.NoError(tb, err)
+		sources[i], err = p.Open(context.Background(), name, 1, nil)
+		require.NoError(tb, err)
+	}
+	return sources, chunks
+}
+
+func TestFSTablePersisterConjoinAllCopyBuffer(t *testing.T) {
+	dir, outDir := makeTempDir(t), makeTempDir(t)
+	defer os.RemoveAll(dir)
+	defer os.RemoveAll(outDir)
+	fc := newFDCache(defaultMaxTables)
+	defer fc.Drop()
+	fts := newFSTablePersister(dir, fc, nil).(*fsTablePersister)
+	sources, chunks := openRandomTables(t, fts, dir, 4, 10000)
+
+	src, err := fts.ConjoinAll(context.Background(), sources, &Stats{})
+	require.NoError(t, err)
+	defer src.Close()
+	expected, err := ioutil.ReadFile(filepath.Join(dir, mustAddr(src.hash()).String()))
+	require.NoError(t, err)
+
+	// an odd buffer size doesn't divide any source's data evenly
+	for _, size := range []int{7, 4096, 1 << 20} {
+		t.Run(fmt.Sprintf("%d byte buffer", size), func(t *testing.T) {
+			buffered := newFSTablePersister(dir, fc, nil, withCopyBuffer(size)).(*fsTablePersister)
+			src, err := buffered.ConjoinAllInto(context.Background(), outDir, sources, &Stats{})
+			require.NoError(t, err)
+			defer src.Close()
+
+			actual, err := ioutil.ReadFile(filepath.Join(outDir, mustAddr(src.hash()).String()))
+			require.NoError(t, err)
+			assert.Equal(t, expected, actual)
+			assertChunksInReader(chunks, src, assert.New(t))
+		})
+	}
+}
+
+// slowChunkSource is a chunkSource whose reader waits |latency| on every read, like a reader of remote storage.
+type slowChunkSource struct {
+	chunkSource
+	latency time.Duration
+}
+
+func (scs slowChunkSource) reader(ctx context.Context) (io.Reader, error) {
+	r, err := scs.chunkSource.reader(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &slowReader{r, scs.latency}, nil
+}
+
+type slowReader struct {
+	r       io.Reader
+	latency time.Duration
+}
+
+func (sr *slowReader) Read(p []byte) (int, error) {
+	time.Sleep(sr.latency)
+	return sr.r.Read(p)
+}
+
+func BenchmarkFSTablePersisterConjoinAllCopyBuffer(b *testing.B) {
+	const tableSize = 1 << 20
+
+	benchmarks := []struct {
+		name       string
+		bufferSize int
+	}{
+		{"default buffer", 0},
+		{"256KB buffer", 256 << 10},
+		{"1MB buffer", 1 << 20},
+	}
+
+	dir, err := ioutil.TempDir("", "")
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	fc := newFDCache(defaultMaxTables)
+	defer fc.Drop()
+
+	sources, _ := openRandomTables(b, newFSTablePersister(dir, fc, nil), dir, 4, tableSize)
+	for i := range sources {
+		sources[i] = slowChunkSource{sources[i], 100 * time.Microsecond}
+	}
+
+	for _, bm := range benchmarks {
+		b.Run(bm.name, func(b *testing.B) {
+			fts := &fsTablePersister{dir: dir, fc: fc, copyBufferSize: bm.bufferSize}
+			b.SetBytes(int64(len(sources) * tableSize))
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				src, err := fts.ConjoinAll(context.Background(), sources, &Stats{})
+				if err != nil {
+					b.Fatal(err)
+				}
+				src.Close()
+			}
+		})
+	}
+}
+
+func TestFSTablePersisterOpenWaitsForRename(t *testing.T) {
+	dir := makeTempDir(t)
+	defer os.RemoveAll(dir)
+	fc := newFDCache(defaultMaxTables)
+	defer fc.Drop()
+	fts := newFSTablePersister(dir, fc, nil)
+
+	src, err := persistTableData(fts, testChunks...)
+	require.NoError(t, err)
+	name, count := mustAddr(src.hash()), mustUint32(src.count())
+	require.NoError(t, src.Close())
+
+	// flock locks belong to open files rather than processes, so this stands in for a writer in another process
+	f, err := os.Open(filepath.Join(dir, name.String()))
+	require.NoError(t, err)
+	release, err := lockTableFile(f, true)
+	require.NoError(t, err)
+
+	opened := make(chan error, 1)
+	go func() {
+		src, err := fts.Open(context.Background(), name, count, nil)
+		if err == nil {
+			err = src.Close()
+		}
+		opened <- err
+	}()
+
+	select {
+	case err := <-opened:
+		t.Fatalf("Open returned while a rename was in progress: %v", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	require.NoError(t, release())
+	require.NoError(t, <-opened)
+}
+
+func TestFSTablePersisterConcurrentOpenAndRename(t *testing.T) {
+	dir := makeTempDir(t)
+	defer os.RemoveAll(dir)
+	fc := newFDCache(defaultMaxTables)
+	defer fc.Drop()
+	fts := newFSTablePersister(dir, fc, nil)
+
+	src, err := persistTableData(fts, testChunks...)
+	require.NoError(t, err)
+	name, count := mustAddr(src.hash()), mustUint32(src.count())
+	require.NoError(t, src.Close())
+
+	const rounds = 50
+	errs := make(chan error, 2)
+
+	// writer: keeps replacing the table file with a freshly written copy of itself
+	go func() {
+		for i := 0; i < rounds; i++ {
+			src, err := persistTableData(fts, testChunks...)
+			if err == nil {
+				err = src.Close()
+			}
+			if err != nil {
+				errs <- err
+				return
+			}
+		}
+		errs <- nil
+	}()
+
+	// reader: keeps opening the table file and reading from it
+	go func() {
+		for i := 0; i < rounds; i++ {
+			src, err := fts.Open(context.Background(), name, count, nil)
+			if err != nil {
+				errs <- err
+				return
+			}
+
+			for _, c := range testChunks {
+				data, err := src.get(context.Background(), computeAddr(c), &Stats{})
+				if err != nil {
+					errs <- err
+					return
+				} else if string(data) != string(c) {
+					errs <- fmt.Errorf("read %q, expected %q", data, c)
+					return
+				}
+			}
+
+			if err := src.Close(); err != nil {
+				errs <- err
+				return
+			}
+		}
+		errs <- nil
+	}()
+
+	require.NoError(t, <-errs)
+	require.NoError(t, <-errs)
+}
+
+// unreadableChunkSource is a chunkSource whose chunk data can't be read.
+type unreadableChunkSource struct {
+	chunkSource
+}
+
+func (ucs unreadableChunkSource) reader(ctx context.Context) (io.Reader, error) {
+	return nil, errors.New("unreadable")
+}
+
+func TestFSTablePersisterConjoinAllSkippingFailures(t *testing.T) {
+	dir := makeTempDir(t)
+	defer os.RemoveAll(dir)
+	fc := newFDCache(defaultMaxTables)
+	defer fc.Drop()
+	fts := newFSTablePersister(dir, fc, nil).(*fsTablePersister)
+
+	sources := make(chunkSources, len(testChunks))
+	for i, c := range testChunks {
+		var err error
+		sources[i], err = persistTableData(fts, c)
+		require.NoError(t, err)
+	}
+	sources[1] = unreadableChunkSource{sources[1]}
+
+	_, err := fts.ConjoinAll(context.Background(), sources, &Stats{})
+	require.Error(t, err)
+
+	src, skipped, err := fts.ConjoinAllSkippingFailures(context.Background(), sources, &Stats{})
+	require.NoError(t, err)
+	require.Len(t, skipped, 1)
+	assert.Equal(t, mustAddr(sources[1].hash()), mustAddr(skipped[0].source.hash()))
+	assert.EqualError(t, skipped[0].err, "unreadable")
+
+	healthy := [][]byte{testChunks[0]}
+	healthy = append(healthy, testChunks[2:]...)
+	assert.Equal(t, uint32(len(healthy)), mustUint32(src.count()))
+	assertChunksInReader(healthy, src, assert.New(t))
+	assertChunksNotInReader(testChunks[1:2], src, assert.New(t))
+}
+
 func TestFSTablePersisterConjoinAllDups(t *testing.T) {
 	assert := assert.New(t)
 	dir := makeTempDir(t)
@@ -270,3 +1042,71 @@ func TestFSTablePersisterConjoinAllDups(t *testing.T) {
 		assert.EqualValues(reps*len(testChunks), mustUint32(tr.count()))
 	}
 }
+
+// cancelAfterChecks is a context that reports being cancelled after its Err method has been called |checks| times.
+type cancelAfterChecks struct {
+	context.Context
+	checks int
+}
+
+func (c *cancelAfterChecks) Err() error {
+	if c.checks <= 0 {
+		return context.Canceled
+	}
+	c.checks--
+	return nil
+}
+
+func TestFSTablePersisterPersistCancelled(t *testing.T) {
+	dir := makeTempDir(t)
+	defer os.RemoveAll(dir)
+	fc := newFDCache(defaultMaxTables)
+	defer fc.Drop()
+	fts := newFSTablePersister(dir, fc, nil)
+
+	// the copy reads in 32KB blocks, so this is cancelled partway through writing the temp file
+	data := make([]byte, 1<<20)
+	ctx := &cancelAfterChecks{Context: context.Background(), checks: 2}
+
+	_, err := fts.(*fsTablePersister).persistTable(ctx, computeAddr(data), data, 1, &Stats{})
+	require.Equal(t, context.Canceled, err)
+
+	files, err := ioutil.ReadDir(dir)
+	require.NoError(t, err)
+	for _, f := range files {
+		assert.False(t, strings.HasPrefix(f.Name(), tempTablePrefix), "temp file %s was left behind", f.Name())
+	}
+	assert.Empty(t, files)
+}
+
+func TestFSTablePersisterReconcileTableFiles(t *testing.T) {
+	dir := makeTempDir(t)
+	defer os.RemoveAll(dir)
+	fc := newFDCache(defaultMaxTables)
+	defer fc.Drop()
+	fts := newFSTablePersister(dir, fc, nil).(*fsTablePersister)
+
+	var names []addr
+	for _, c := range testChunks {
+		src, err := persistTableData(fts, c)
+		require.NoError(t, err)
+		names = append(names, mustAddr(src.hash()))
+	}
+
+	// not table files
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "manifest"), []byte("manifest"), 0666))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, tempTablePrefix+"123"), []byte("temp"), 0666))
+
+	listed, err := fts.ListTables()
+	require.NoError(t, err)
+	assert.ElementsMatch(t, names, listed)
+
+	missing := computeAddr([]byte("missing"))
+	referenced := map[addr]struct{}{names[0]: {}, names[1]: {}, missing: {}}
+
+	rec, err := fts.ReconcileTableFiles(referenced)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, names[:2], rec.present)
+	assert.Equal(t, []addr{missing}, rec.missing)
+	assert.Equal(t, names[2:], rec.orphaned)
+}
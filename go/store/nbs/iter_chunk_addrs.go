@@ -0,0 +1,44 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nbs
+
+import (
+	"context"
+)
+
+// IterChunkAddrs calls |fn| with the address of each chunk in |source|, in the order of its index, without reading
+// any chunk data. It stops at the first error returned by |fn|, and returns it.
+func IterChunkAddrs(ctx context.Context, source chunkSource, fn func(addr) error) error {
+	index, err := source.index()
+
+	if err != nil {
+		return err
+	}
+
+	var a addr
+	for i := uint32(0); i < index.ChunkCount(); i++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		index.IndexEntry(i, &a)
+
+		if err := fn(a); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
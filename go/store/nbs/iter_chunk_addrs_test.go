@@ -0,0 +1,69 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nbs
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIterChunkAddrs(t *testing.T) {
+	ctx := context.Background()
+
+	tableData, name, err := buildTable(testChunks)
+	require.NoError(t, err)
+	src, err := newReaderFromIndexData(nil, tableData, name, tableReaderAtFromBytes(tableData), fileBlockSize)
+	require.NoError(t, err)
+	defer src.Close()
+
+	visited := make(map[addr]int)
+	var order addrSlice
+	err = IterChunkAddrs(ctx, src, func(a addr) error {
+		visited[a]++
+		order = append(order, a)
+		return nil
+	})
+	require.NoError(t, err)
+
+	require.Len(t, visited, len(testChunks))
+	for _, c := range testChunks {
+		assert.Equal(t, 1, visited[computeAddr(c)])
+	}
+
+	// index order is sorted by address
+	sorted, err := sortedIndexAddrs(src)
+	require.NoError(t, err)
+	assert.Equal(t, sorted, order)
+
+	errStop := errors.New("stop")
+	calls := 0
+	err = IterChunkAddrs(ctx, src, func(a addr) error {
+		calls++
+		return errStop
+	})
+	assert.Equal(t, errStop, err)
+	assert.Equal(t, 1, calls)
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	err = IterChunkAddrs(cancelled, src, func(a addr) error {
+		return nil
+	})
+	assert.Equal(t, context.Canceled, err)
+}
@@ -0,0 +1,143 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nbs
+
+import (
+	"context"
+	"time"
+
+	"github.com/dolthub/dolt/go/store/d"
+)
+
+// LocalStoreOption configures a NomsBlockStore created by NewLocalStore.
+type LocalStoreOption func(o *localStoreOptions)
+
+type localStoreOptions struct {
+	persister []fsTablePersisterOption
+
+	// autoConjoinThreshold, if non-zero, is the number of tables above which the store conjoins in the background
+	autoConjoinThreshold int
+
+	// extendedTables is set when the store may write table files in the extended format
+	extendedTables bool
+}
+
+// WithMappedTables makes the store read chunks out of memory mapped table files, with at most |maxMapped| files
+// mapped at once. The mappings are released when the store is closed.
+func WithMappedTables(maxMapped int) LocalStoreOption {
+	d.PanicIfTrue(maxMapped <= 0)
+	return func(o *localStoreOptions) {
+		o.persister = append(o.persister, withMmapPool(newMmapPool(maxMapped)))
+	}
+}
+
+// WithBatchedShrinks suits the store to persisting many tables in quick succession. Rather than shrinking the
+// process's cache of open table files after every persist, it shrinks it once every |shrinkEvery| persists, or on the
+// first persist after |shrinkWindow| has passed since the last shrink.
+func WithBatchedShrinks(shrinkEvery int, shrinkWindow time.Duration) LocalStoreOption {
+	return func(o *localStoreOptions) {
+		o.persister = append(o.persister, withBatchedShrinks(shrinkEvery, shrinkWindow))
+	}
+}
+
+// WithAutoConjoin makes the store conjoin its tables in the background once it has more than |threshold| of them,
+// rather than waiting until a commit finds too many. The store picks up the conjoined table the next time it updates
+// its manifest. Close stops a running conjoin, and returns the error from the last one that failed.
+func WithAutoConjoin(threshold int) LocalStoreOption {
+	d.PanicIfTrue(threshold <= 0)
+	return func(o *localStoreOptions) {
+		o.autoConjoinThreshold = threshold
+	}
+}
+
+// WithVerifiedTableNames makes the store check that the content address of each table file it opens matches the
+// name it is referenced by, failing with an error wrapping ErrTableNameMismatch if it doesn't.
+func WithVerifiedTableNames() LocalStoreOption {
+	return func(o *localStoreOptions) {
+		o.persister = append(o.persister, withVerifiedNames())
+	}
+}
+
+// WithConjoinCopyBuffer makes the store copy table data through a buffer of |size| bytes when conjoining tables.
+func WithConjoinCopyBuffer(size int) LocalStoreOption {
+	return func(o *localStoreOptions) {
+		o.persister = append(o.persister, withCopyBuffer(size))
+	}
+}
+
+// WithSHA256TableNames makes the store name the table files it writes by the SHA-256 hash of their contents rather
+// than the SHA-512 one. The table files are in the extended format, which versions of Dolt that predate it can't read,
+// so the store's manifest is marked as requiring it, and those versions refuse to open the store.
+func WithSHA256TableNames() LocalStoreOption {
+	return func(o *localStoreOptions) {
+		o.persister = append(o.persister, withTableHasher(sha256TableHasher))
+		o.extendedTables = true
+	}
+}
+
+// WithTableAlignment makes the store pad the table files it writes so that their indices begin at a multiple of
+// |alignment| bytes, which can speed up reads on block storage. Padded table files are in the extended format, which
+// versions of Dolt that predate it can't read, so the store's manifest is marked as requiring it, and those versions
+// refuse to open the store.
+func WithTableAlignment(alignment uint64) LocalStoreOption {
+	return func(o *localStoreOptions) {
+		o.persister = append(o.persister, withAlignment(alignment))
+		o.extendedTables = true
+	}
+}
+
+// WithReadAhead makes the store advise the kernel to read |readAhead| bytes ahead of sequential reads of its table
+// files, such as streaming or extracting a table.
+func WithReadAhead(readAhead uint64) LocalStoreOption {
+	return func(o *localStoreOptions) {
+		o.persister = append(o.persister, withReadAhead(readAhead))
+	}
+}
+
+// WithConjoinReadRetries makes the store's conjoins retry reading a table up to |retries| times after a transient
+// error, such as a timeout reading from network storage, rather than failing.
+func WithConjoinReadRetries(retries int) LocalStoreOption {
+	return func(o *localStoreOptions) {
+		o.persister = append(o.persister, withReadRetries(retries))
+	}
+}
+
+// conjoinUpstream conjoins the tables referenced by the manifest managed by |mm|, and returns the number of tables
+// removed from it.
+func conjoinUpstream(ctx context.Context, mm manifestManager, p tablePersister) (removed int, err error) {
+	mm.LockForUpdate()
+	defer func() {
+		unlockErr := mm.UnlockForUpdate()
+
+		if err == nil {
+			err = unlockErr
+		}
+	}()
+
+	stats := NewStats()
+	exists, upstream, err := mm.Fetch(ctx, stats)
+
+	if err != nil || !exists || len(upstream.specs) < 2 {
+		return 0, err
+	}
+
+	conjoined, err := conjoin(ctx, upstream, mm, p, stats)
+
+	if err != nil {
+		return 0, err
+	}
+
+	return len(upstream.specs) - len(conjoined.specs), nil
+}
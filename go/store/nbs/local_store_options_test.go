@@ -0,0 +1,214 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nbs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/store/chunks"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// commitTables commits |numTables| tables of a few chunks each to |st|, and returns the chunks.
+func commitTables(t *testing.T, st *NomsBlockStore, numTables int) []chunks.Chunk {
+	ctx := context.Background()
+
+	var all []chunks.Chunk
+	for i := 0; i < numTables; i++ {
+		for j := 0; j < 3; j++ {
+			c := chunks.NewChunk([]byte(fmt.Sprintf("table %d chunk %d", i, j)))
+			require.NoError(t, st.Put(ctx, c))
+			all = append(all, c)
+		}
+
+		root, err := st.Root(ctx)
+		require.NoError(t, err)
+		ok, err := st.Commit(ctx, all[len(all)-1].Hash(), root)
+		require.NoError(t, err)
+		require.True(t, ok)
+	}
+
+	return all
+}
+
+func assertChunksInStore(t *testing.T, st *NomsBlockStore, expected []chunks.Chunk) {
+	ctx := context.Background()
+	for _, c := range expected {
+		actual, err := st.Get(ctx, c.Hash())
+		require.NoError(t, err)
+		assert.Equal(t, c.Data(), actual.Data())
+	}
+}
+
+// newTestLocalStore returns a store in a new directory created with |opts|, which the caller must remove.
+func newTestLocalStore(t *testing.T, opts ...LocalStoreOption) (*NomsBlockStore, string) {
+	dir := makeTempDir(t)
+	st, err := NewLocalStore(context.Background(), types.Format_Default.VersionString(), dir, 0, opts...)
+	require.NoError(t, err)
+	return st, dir
+}
+
+func testPersister(st *NomsBlockStore) *fsTablePersister {
+	return st.p.(*fsTablePersister)
+}
+
+func TestLocalStoreWithMappedTables(t *testing.T) {
+	st, dir := newTestLocalStore(t, WithMappedTables(2))
+	defer os.RemoveAll(dir)
+
+	expected := commitTables(t, st, 4)
+	assertChunksInStore(t, st, expected)
+
+	mp := testPersister(st).mmapPool
+	require.NotNil(t, mp)
+	assert.True(t, mp.activeMaps() <= 2)
+
+	// the store's mappings are released when it is closed
+	require.NoError(t, st.Close())
+	assert.Equal(t, 0, mp.activeMaps())
+}
+
+func TestLocalStoreWithBatchedShrinks(t *testing.T) {
+	st, dir := newTestLocalStore(t, WithBatchedShrinks(4, time.Minute))
+	defer os.RemoveAll(dir)
+
+	require.NotNil(t, testPersister(st).shrinker)
+	assert.Equal(t, 4, testPersister(st).shrinker.every)
+
+	expected := commitTables(t, st, 6)
+	assertChunksInStore(t, st, expected)
+	require.NoError(t, st.Close())
+}
+
+func TestLocalStoreWithAutoConjoin(t *testing.T) {
+	ctx := context.Background()
+	st, dir := newTestLocalStore(t, WithAutoConjoin(2))
+	defer os.RemoveAll(dir)
+
+	expected := commitTables(t, st, 3)
+	require.NoError(t, testPersister(st).autoConjoiner.wait())
+
+	// the conjoin landed in the manifest without the store committing again
+	exists, contents, err := st.mm.Fetch(ctx, &Stats{})
+	require.NoError(t, err)
+	require.True(t, exists)
+	assert.Len(t, contents.specs, 1)
+
+	// and the store picks it up when it next commits
+	expected = append(expected, commitTables(t, st, 1)...)
+	assertChunksInStore(t, st, expected)
+	require.NoError(t, st.Close())
+
+	st, err = NewLocalStore(ctx, types.Format_Default.VersionString(), dir, 0)
+	require.NoError(t, err)
+	defer st.Close()
+	assertChunksInStore(t, st, expected)
+}
+
+func TestLocalStoreWithVerifiedTableNames(t *testing.T) {
+	ctx := context.Background()
+	st, dir := newTestLocalStore(t)
+	defer os.RemoveAll(dir)
+
+	expected := commitTables(t, st, 1)
+	require.NoError(t, st.Close())
+
+	st, err := NewLocalStore(ctx, types.Format_Default.VersionString(), dir, 0, WithVerifiedTableNames())
+	require.NoError(t, err)
+	assert.True(t, testPersister(st).verifyNames)
+	assertChunksInStore(t, st, expected)
+	require.NoError(t, st.Close())
+
+	// a table file that is referenced by a name other than its content address can't be opened
+	_, contents, err := st.mm.Fetch(ctx, &Stats{})
+	require.NoError(t, err)
+	require.Len(t, contents.specs, 1)
+	name, other := contents.specs[0].name.String(), computeAddr([]byte("other table")).String()
+	require.NoError(t, os.Rename(filepath.Join(dir, name), filepath.Join(dir, other)))
+	manifest, err := ioutil.ReadFile(filepath.Join(dir, manifestFileName))
+	require.NoError(t, err)
+	manifest = []byte(strings.Replace(string(manifest), name, other, 1))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, manifestFileName), manifest, 0666))
+
+	_, err = NewLocalStore(ctx, types.Format_Default.VersionString(), dir, 0, WithVerifiedTableNames())
+	assert.True(t, errors.Is(err, ErrTableNameMismatch), "unexpected error: %v", err)
+}
+
+func TestLocalStoreWithConjoinOptions(t *testing.T) {
+	st, dir := newTestLocalStore(t, WithConjoinCopyBuffer(512), WithConjoinReadRetries(3))
+	defer os.RemoveAll(dir)
+
+	assert.Equal(t, 512, testPersister(st).copyBufferSize)
+	assert.Equal(t, 3, testPersister(st).readRetries)
+
+	expected := commitTables(t, st, 3)
+	_, err := conjoinUpstream(context.Background(), st.mm, st.p)
+	require.NoError(t, err)
+	expected = append(expected, commitTables(t, st, 1)...)
+	assertChunksInStore(t, st, expected)
+	require.NoError(t, st.Close())
+}
+
+func TestLocalStoreWithReadAhead(t *testing.T) {
+	st, dir := newTestLocalStore(t, WithReadAhead(1<<20))
+	defer os.RemoveAll(dir)
+
+	assert.Equal(t, uint64(1<<20), testPersister(st).readAhead)
+	expected := commitTables(t, st, 2)
+	assertChunksInStore(t, st, expected)
+	require.NoError(t, st.Close())
+}
+
+func TestLocalStoreWithExtendedTables(t *testing.T) {
+	ctx := context.Background()
+
+	for _, opt := range []LocalStoreOption{WithSHA256TableNames(), WithTableAlignment(4096)} {
+		st, dir := newTestLocalStore(t, opt)
+
+		expected := commitTables(t, st, 2)
+		assertChunksInStore(t, st, expected)
+		require.NoError(t, st.Close())
+
+		// the manifest records that the store may contain extended table files, which versions that can't read them
+		// refuse to open
+		manifest, err := ioutil.ReadFile(filepath.Join(dir, manifestFileName))
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(string(manifest), extendedTablesStorageVersion+":"))
+
+		// and it keeps doing so when the store is opened without the option
+		st, err = NewLocalStore(ctx, types.Format_Default.VersionString(), dir, 0)
+		require.NoError(t, err)
+		assertChunksInStore(t, st, expected)
+		expected = append(expected, commitTables(t, st, 1)...)
+		require.NoError(t, st.Close())
+
+		manifest, err = ioutil.ReadFile(filepath.Join(dir, manifestFileName))
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(string(manifest), extendedTablesStorageVersion+":"))
+
+		os.RemoveAll(dir)
+	}
+}
@@ -176,13 +176,18 @@ func (mt *memTable) extract(ctx context.Context, chunks chan<- extractRecord) er
 }
 
 func (mt *memTable) write(haver chunkReader, stats *Stats) (name addr, data []byte, count uint32, err error) {
+	return mt.writeHashed(haver, stats, sha512TableHasher)
+}
+
+// writeHashed is like write, but the table written is named by |hasher|.
+func (mt *memTable) writeHashed(haver chunkReader, stats *Stats, hasher *tableHasher) (name addr, data []byte, count uint32, err error) {
 	numChunks := uint64(len(mt.order))
 	if numChunks == 0 {
 		return addr{}, nil, 0, fmt.Errorf("mem table cannot write with zero chunks")
 	}
 	maxSize := maxTableSize(uint64(len(mt.order)), mt.totalData)
 	buff := make([]byte, maxSize)
-	tw := newTableWriter(buff, mt.snapper)
+	tw := newHashedTableWriter(buff, mt.snapper, hasher)
 
 	if haver != nil {
 		sort.Sort(hasRecordByPrefix(mt.order)) // hasMany() requires addresses to be sorted.
@@ -208,6 +213,10 @@ func (mt *memTable) write(haver chunkReader, stats *Stats) (name addr, data []by
 		return addr{}, nil, 0, err
 	}
 
+	if deduped := numChunks - uint64(count); deduped > 0 {
+		stats.DedupedChunksPerPersist.Sample(deduped)
+	}
+
 	if count > 0 {
 		stats.BytesPerPersist.Sample(uint64(tableSize))
 		stats.CompressedChunkBytesPerPersist.Sample(uint64(tw.totalCompressedData))
@@ -218,6 +227,18 @@ func (mt *memTable) write(haver chunkReader, stats *Stats) (name addr, data []by
 	return name, buff[:tableSize], count, nil
 }
 
+// writtenAddrs returns the addresses of the chunks written to the table by the last call to write, in the order they
+// were added. Chunks that write found in its haver are omitted.
+func (mt *memTable) writtenAddrs() []addr {
+	addrs := make([]addr, 0, len(mt.order))
+	for _, rec := range mt.order {
+		if !rec.has {
+			addrs = append(addrs, *rec.a)
+		}
+	}
+	return addrs
+}
+
 func (mt *memTable) Close() error {
 	return nil
 }
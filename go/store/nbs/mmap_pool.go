@@ -0,0 +1,224 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nbs
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/dolthub/mmap-go"
+)
+
+func newMmapPool(maxMaps int) *mmapPool {
+	return &mmapPool{maxMaps: maxMaps, lru: list.New(), maps: map[string]*list.Element{}}
+}
+
+// mmapPool ref-counts memory mapped table files and bounds the number of
+// mappings that are held at any one time. Once the pool is over |maxMaps|,
+// mappings with zero refs are unmapped in least-recently-used order. An
+// unmapped file is transparently remapped the next time it is acquired. Like
+// fdCache, the cap is a target: mappings with in-flight reads are never
+// unmapped, so the pool can temporarily exceed |maxMaps| under heavy
+// concurrency.
+type mmapPool struct {
+	maxMaps int
+	mu      sync.Mutex
+	lru     *list.List
+	maps    map[string]*list.Element
+}
+
+type mmapPoolEntry struct {
+	path     string
+	mm       mmap.MMap
+	refCount uint32
+}
+
+// acquire returns the mapping of the file at |path|, mapping it if it is not
+// currently mapped. Each successful call to acquire must be paired with a call
+// to release.
+func (mp *mmapPool) acquire(path string) (mmap.MMap, error) {
+	mp.mu.Lock()
+	defer mp.mu.Unlock()
+
+	if el, ok := mp.maps[path]; ok {
+		ent := el.Value.(*mmapPoolEntry)
+		ent.refCount++
+		mp.lru.MoveToFront(el)
+		return ent.mm, nil
+	}
+
+	mm, err := mapFile(path)
+
+	if err != nil {
+		return nil, err
+	}
+
+	mp.maps[path] = mp.lru.PushFront(&mmapPoolEntry{path: path, mm: mm, refCount: 1})
+
+	err = mp.shrink()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return mm, nil
+}
+
+// release decrements the refcount of the mapping for |path|, and unmaps idle
+// mappings if the pool is over its target size.
+func (mp *mmapPool) release(path string) error {
+	mp.mu.Lock()
+	defer mp.mu.Unlock()
+
+	if el, ok := mp.maps[path]; ok {
+		el.Value.(*mmapPoolEntry).refCount--
+	}
+
+	return mp.shrink()
+}
+
+// shrink unmaps zero refcount mappings, oldest first, until the pool is back
+// at |maxMaps|. Callers must hold |mp.mu|.
+func (mp *mmapPool) shrink() error {
+	for el := mp.lru.Back(); el != nil && len(mp.maps) > mp.maxMaps; {
+		prev := el.Prev()
+		ent := el.Value.(*mmapPoolEntry)
+
+		if ent.refCount == 0 {
+			err := ent.mm.Unmap()
+
+			if err != nil {
+				return err
+			}
+
+			mp.lru.Remove(el)
+			delete(mp.maps, ent.path)
+		}
+
+		el = prev
+	}
+
+	return nil
+}
+
+// Drop unmaps every mapping held by the pool, regardless of refcount.
+func (mp *mmapPool) Drop() error {
+	mp.mu.Lock()
+	defer mp.mu.Unlock()
+
+	var firstErr error
+	for _, el := range mp.maps {
+		err := el.Value.(*mmapPoolEntry).mm.Unmap()
+
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	mp.lru.Init()
+	mp.maps = map[string]*list.Element{}
+
+	return firstErr
+}
+
+// activeMaps is meant for testing.
+func (mp *mmapPool) activeMaps() int {
+	mp.mu.Lock()
+	defer mp.mu.Unlock()
+	return len(mp.maps)
+}
+
+func mapFile(path string) (mm mmap.MMap, err error) {
+	f, err := os.Open(path)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// the mapping remains valid after the file is closed
+	defer func() {
+		closeErr := f.Close()
+
+		if err == nil {
+			err = closeErr
+		}
+	}()
+
+	fi, err := f.Stat()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if fi.Size() <= 0 || fi.Size() > maxInt {
+		return nil, fmt.Errorf("%s has invalid size for mmap: %d", path, fi.Size())
+	}
+
+	return mmap.Map(f, mmap.RDONLY, 0)
+}
+
+// mmapReaderAt serves table file reads out of a mapping acquired from an
+// mmapPool.
+type mmapReaderAt struct {
+	path string
+	mp   *mmapPool
+
+	// readAhead, if non-nil, is used to advise the kernel how the mapping is being read
+	readAhead *readAheadWindow
+}
+
+func (mra *mmapReaderAt) ReadAtWithStats(ctx context.Context, p []byte, off int64, stats *Stats) (n int, err error) {
+	t1 := time.Now()
+
+	mm, err := mra.mp.acquire(mra.path)
+
+	if err != nil {
+		return 0, err
+	}
+
+	defer func() {
+		stats.FileBytesPerRead.Sample(uint64(len(p)))
+		stats.FileReadLatency.SampleTimeSince(t1)
+	}()
+
+	defer func() {
+		releaseErr := mra.mp.release(mra.path)
+
+		if err == nil {
+			err = releaseErr
+		}
+	}()
+
+	if off < 0 || off >= int64(len(mm)) {
+		return 0, io.EOF
+	}
+
+	n = copy(p, mm[off:])
+
+	if mra.readAhead != nil {
+		adviseMappingRead(mm, mra.readAhead, off, n)
+	}
+
+	if n < len(p) {
+		return n, io.EOF
+	}
+
+	return n, nil
+}
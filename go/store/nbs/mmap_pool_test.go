@@ -0,0 +1,87 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nbs
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMmapPoolBoundsMappings(t *testing.T) {
+	dir := makeTempDir(t)
+	defer os.RemoveAll(dir)
+
+	maxMaps := 2
+	mp := newMmapPool(maxMaps)
+	defer mp.Drop()
+	fc := newFDCache(defaultMaxTables)
+	defer fc.Drop()
+	fts := newFSTablePersister(dir, fc, nil, withMmapPool(mp))
+
+	numTables := 5
+	chunks := make([][]byte, numTables)
+	sources := make(chunkSources, numTables)
+	for i := range sources {
+		chunks[i] = []byte{byte(i), 0xa, 0xb}
+		name, err := writeTableData(dir, chunks[i])
+		require.NoError(t, err)
+		sources[i], err = fts.Open(context.Background(), name, 1, nil)
+		require.NoError(t, err)
+	}
+
+	// Read every table twice, in order, so that every read past the cap forces an eviction and every
+	// table has to be remapped on the second pass.
+	for pass := 0; pass < 2; pass++ {
+		for i, src := range sources {
+			data, err := src.get(context.Background(), computeAddr(chunks[i]), &Stats{})
+			require.NoError(t, err)
+			assert.Equal(t, chunks[i], data)
+			assert.True(t, mp.activeMaps() <= maxMaps)
+		}
+	}
+}
+
+func TestMmapPoolEvictsLeastRecentlyUsed(t *testing.T) {
+	dir := makeTempDir(t)
+	defer os.RemoveAll(dir)
+
+	mp := newMmapPool(1)
+	defer mp.Drop()
+
+	var paths []string
+	for i := 0; i < 2; i++ {
+		name, err := writeTableData(dir, []byte{byte(i)})
+		require.NoError(t, err)
+		paths = append(paths, filepath.Join(dir, name.String()))
+	}
+
+	_, err := mp.acquire(paths[0])
+	require.NoError(t, err)
+
+	// |paths[0]| is still referenced, so it can't be unmapped and the pool goes over its cap
+	_, err = mp.acquire(paths[1])
+	require.NoError(t, err)
+	assert.Equal(t, 2, mp.activeMaps())
+
+	require.NoError(t, mp.release(paths[0]))
+	assert.Equal(t, 1, mp.activeMaps())
+	require.NoError(t, mp.release(paths[1]))
+	assert.Equal(t, 1, mp.activeMaps())
+}
@@ -25,7 +25,6 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"io"
 	"math"
 	"os"
 	"path/filepath"
@@ -55,7 +54,10 @@ func init() {
 	}
 }
 
-func newMmapTableReader(dir string, h addr, chunkCount uint32, indexCache *indexCache, fc *fdCache) (cs chunkSource, err error) {
+// newMmapTableReader opens the table file named |h| in |dir|. If |mp| is non-nil, chunk reads are served from
+// mappings acquired from |mp|, otherwise they are read through file descriptors from |fc|. If |readAhead| is
+// non-zero, the kernel is advised to read |readAhead| bytes ahead of sequential reads.
+func newMmapTableReader(dir string, h addr, chunkCount uint32, indexCache *indexCache, fc *fdCache, mp *mmapPool, readAhead uint64) (cs chunkSource, err error) {
 	path := filepath.Join(dir, h.String())
 
 	var index onHeapTableIndex
@@ -152,11 +154,29 @@ func newMmapTableReader(dir string, h addr, chunkCount uint32, indexCache *index
 		return nil, errors.New("unexpected chunk count")
 	}
 
+	return newMmapTableReaderWithIndex(dir, h, index, fc, mp, readAhead), nil
+}
+
+// newMmapTableReaderWithIndex returns a reader of the table file named |h| in |dir| whose index, already parsed, is
+// |index|.
+func newMmapTableReaderWithIndex(dir string, h addr, index onHeapTableIndex, fc *fdCache, mp *mmapPool, readAhead uint64) chunkSource {
+	path := filepath.Join(dir, h.String())
+
+	var window *readAheadWindow
+	if readAhead != 0 {
+		window = newReadAheadWindow(int64(readAhead))
+	}
+
+	var tra tableReaderAt = &cacheReaderAt{path, fc, window}
+	if mp != nil {
+		tra = &mmapReaderAt{path, mp, window}
+	}
+
 	return &mmapTableReader{
-		newTableReader(index, &cacheReaderAt{path, fc}, fileBlockSize),
+		newTableReader(index, tra, fileBlockSize),
 		fc,
 		h,
-	}, nil
+	}
 }
 
 func (mmtr *mmapTableReader) hash() (addr, error) {
@@ -174,13 +194,16 @@ func (mmtr *mmapTableReader) Clone() chunkSource {
 type cacheReaderAt struct {
 	path string
 	fc   *fdCache
+
+	// readAhead, if non-nil, is used to advise the kernel how the file is being read
+	readAhead *readAheadWindow
 }
 
 func (cra *cacheReaderAt) ReadAtWithStats(ctx context.Context, p []byte, off int64, stats *Stats) (n int, err error) {
-	var r io.ReaderAt
+	var f *os.File
 	t1 := time.Now()
 
-	if r, err = cra.fc.RefFile(cra.path); err != nil {
+	if f, err = cra.fc.RefFile(cra.path); err != nil {
 		return
 	}
 
@@ -197,5 +220,11 @@ func (cra *cacheReaderAt) ReadAtWithStats(ctx context.Context, p []byte, off int
 		}
 	}()
 
-	return r.ReadAt(p, off)
+	n, err = f.ReadAt(p, off)
+
+	if cra.readAhead != nil {
+		adviseFileRead(f, cra.readAhead, off, n)
+	}
+
+	return n, err
 }
@@ -22,12 +22,18 @@
 package nbs
 
 import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
 	"io/ioutil"
+	"math/rand"
 	"os"
 	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestMmapTableReader(t *testing.T) {
@@ -50,7 +56,120 @@ func TestMmapTableReader(t *testing.T) {
 	err = ioutil.WriteFile(filepath.Join(dir, h.String()), tableData, 0666)
 	assert.NoError(err)
 
-	trc, err := newMmapTableReader(dir, h, uint32(len(chunks)), nil, fc)
+	trc, err := newMmapTableReader(dir, h, uint32(len(chunks)), nil, fc, nil, 0)
 	assert.NoError(err)
 	assertChunksInReader(chunks, trc, assert)
 }
+
+func TestReadAheadWindow(t *testing.T) {
+	assertRead := func(w *readAheadWindow, off int64, n int, sequential bool, adviseOff, adviseLen int64) {
+		s, o, l := w.read(off, n)
+		assert.Equal(t, sequential, s)
+		assert.Equal(t, adviseOff, o)
+		assert.Equal(t, adviseLen, l)
+	}
+
+	w := newReadAheadWindow(100)
+	assertRead(w, 500, 10, false, 0, 0)
+	assertRead(w, 510, 10, true, 520, 100)
+	// reads within the first half of the advised range don't advise again
+	assertRead(w, 520, 40, true, 0, 0)
+	assertRead(w, 560, 20, true, 620, 60)
+	assertRead(w, 580, 0, true, 0, 0)
+
+	// a random read resets the window
+	assertRead(w, 0, 10, false, 0, 0)
+	assertRead(w, 10, 200, true, 210, 100)
+}
+
+func TestMmapTableReaderReadAhead(t *testing.T) {
+	ctx := context.Background()
+	dir := makeTempDir(t)
+	defer os.RemoveAll(dir)
+
+	chunks := make([][]byte, 256)
+	for i := range chunks {
+		chunks[i] = make([]byte, 1+rand.Intn(2*fileBlockSize))
+		rand.Read(chunks[i])
+	}
+
+	tableData, h, err := buildTable(chunks)
+	require.NoError(t, err)
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, h.String()), tableData, 0666))
+
+	for _, mapped := range []bool{false, true} {
+		for _, readAhead := range []uint64{1, fileBlockSize, 1 << 20} {
+			t.Run(fmt.Sprintf("mapped=%t readAhead=%d", mapped, readAhead), func(t *testing.T) {
+				fc := newFDCache(1)
+				defer fc.Drop()
+
+				var mp *mmapPool
+				if mapped {
+					mp = newMmapPool(1)
+					defer mp.Drop()
+				}
+
+				cs, err := newMmapTableReader(dir, h, uint32(len(chunks)), nil, fc, mp, readAhead)
+				require.NoError(t, err)
+				defer cs.Close()
+
+				assertGets := func() {
+					for _, i := range rand.Perm(len(chunks)) {
+						data, err := cs.get(ctx, computeAddr(chunks[i]), &Stats{})
+						require.NoError(t, err)
+						require.True(t, bytes.Equal(chunks[i], data))
+					}
+				}
+
+				assertGets()
+
+				r, err := cs.reader(ctx)
+				require.NoError(t, err)
+				read, err := ioutil.ReadAll(r)
+				require.NoError(t, err)
+				assert.True(t, bytes.Equal(tableData, read))
+
+				assertGets()
+			})
+		}
+	}
+}
+
+// BenchmarkMmapTableReaderSequentialScan streams a table file with different read ahead windows. The effect of the
+// window is largest when the file isn't in the page cache, so drop it between runs when comparing windows.
+func BenchmarkMmapTableReaderSequentialScan(b *testing.B) {
+	ctx := context.Background()
+	dir := makeTempDir(b)
+	defer os.RemoveAll(dir)
+
+	chunks := make([][]byte, 4096)
+	for i := range chunks {
+		chunks[i] = make([]byte, fileBlockSize)
+		rand.Read(chunks[i])
+	}
+
+	tableData, h, err := buildTable(chunks)
+	require.NoError(b, err)
+	require.NoError(b, ioutil.WriteFile(filepath.Join(dir, h.String()), tableData, 0666))
+
+	for _, readAhead := range []uint64{0, fileBlockSize, 1 << 20, 8 << 20} {
+		b.Run(fmt.Sprintf("readAhead=%d", readAhead), func(b *testing.B) {
+			fc := newFDCache(1)
+			defer fc.Drop()
+
+			b.SetBytes(int64(len(tableData)))
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				cs, err := newMmapTableReader(dir, h, uint32(len(chunks)), nil, fc, nil, readAhead)
+				require.NoError(b, err)
+
+				r, err := cs.reader(ctx)
+				require.NoError(b, err)
+				_, err = io.Copy(ioutil.Discard, r)
+				require.NoError(b, err)
+				require.NoError(b, cs.Close())
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nbs
+
+import "sync"
+
+// readAheadWindow tracks the reads of a table file to tell sequential reads, each starting where the last one ended,
+// from random ones. Sequential reads advise the kernel to read up to |size| bytes ahead, while random reads are left
+// to the kernel's own read ahead.
+type readAheadWindow struct {
+	size int64
+
+	mu sync.Mutex
+	// next is the offset a read must start at to be sequential
+	next int64
+	// advised is the end of the range the kernel has been advised to read ahead
+	advised int64
+}
+
+func newReadAheadWindow(size int64) *readAheadWindow {
+	return &readAheadWindow{size: size, next: -1}
+}
+
+// read records a read of |n| bytes at |off|, and returns whether it continues the previous read. If it does, it also
+// returns the range the kernel should next be advised to read ahead, which is empty until the reads have passed
+// halfway through the range last advised, so that a scan advises once for every half window it reads.
+func (w *readAheadWindow) read(off int64, n int) (sequential bool, adviseOff, adviseLen int64) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	end := off + int64(n)
+	sequential = off == w.next
+	w.next = end
+
+	if !sequential {
+		w.advised = end
+		return false, 0, 0
+	}
+
+	if w.advised < end {
+		w.advised = end
+	}
+
+	if w.advised-end > w.size/2 {
+		return true, 0, 0
+	}
+
+	adviseOff, adviseLen = w.advised, end+w.size-w.advised
+	w.advised = end + w.size
+	return true, adviseOff, adviseLen
+}
@@ -0,0 +1,57 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build linux
+
+package nbs
+
+import (
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// fadvise and madvise give the kernel advice. They are variables so that tests can record the advice given.
+var (
+	fadvise = unix.Fadvise
+	madvise = unix.Madvise
+)
+
+// adviseFileRead advises the kernel to read ahead of |f| if a read of |n| bytes at |off| continues a sequential run
+// of reads. Advice is best effort, so failures are ignored. Only the range to read ahead is ever advised: |f| comes
+// from an fdCache and is shared by every reader of the table file, so advice about the whole file, such as
+// FADV_RANDOM, would change how the kernel handles their reads too.
+func adviseFileRead(f *os.File, w *readAheadWindow, off int64, n int) {
+	if _, adviseOff, adviseLen := w.read(off, n); adviseLen > 0 {
+		_ = fadvise(int(f.Fd()), adviseOff, adviseLen, unix.FADV_WILLNEED)
+	}
+}
+
+// adviseMappingRead advises the kernel to read ahead of the mapping |mm| if a read of |n| bytes at |off| continues
+// a sequential run of reads. Advice is best effort, so failures are ignored. As with adviseFileRead, only the range to
+// read ahead is ever advised, as mappings are shared through an mmapPool.
+func adviseMappingRead(mm []byte, w *readAheadWindow, off int64, n int) {
+	_, adviseOff, adviseLen := w.read(off, n)
+
+	// madvise requires a page aligned address
+	start := adviseOff / mmapAlignment * mmapAlignment
+	end := adviseOff + adviseLen
+	if end > int64(len(mm)) {
+		end = int64(len(mm))
+	}
+
+	if start < end {
+		_ = madvise(mm[start:end], unix.MADV_WILLNEED)
+	}
+}
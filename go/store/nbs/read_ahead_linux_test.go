@@ -0,0 +1,124 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build linux
+
+package nbs
+
+import (
+	"context"
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sys/unix"
+)
+
+type adviceRecord struct {
+	off, len int64
+	advice   int
+}
+
+// recordAdvice replaces fadvise and madvise with functions that record the advice given, until the returned function
+// is called.
+func recordAdvice() (advice func() []adviceRecord, restore func()) {
+	var mu sync.Mutex
+	var records []adviceRecord
+
+	origFadvise, origMadvise := fadvise, madvise
+	fadvise = func(fd int, off int64, len int64, advice int) error {
+		mu.Lock()
+		defer mu.Unlock()
+		records = append(records, adviceRecord{off, len, advice})
+		return origFadvise(fd, off, len, advice)
+	}
+	madvise = func(b []byte, advice int) error {
+		mu.Lock()
+		defer mu.Unlock()
+		records = append(records, adviceRecord{-1, int64(len(b)), advice})
+		return origMadvise(b, advice)
+	}
+
+	advice = func() []adviceRecord {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]adviceRecord(nil), records...)
+	}
+	restore = func() {
+		fadvise, madvise = origFadvise, origMadvise
+	}
+	return advice, restore
+}
+
+func TestReadAheadAdvisesOnlyRanges(t *testing.T) {
+	ctx := context.Background()
+	dir := makeTempDir(t)
+	defer os.RemoveAll(dir)
+
+	chunks := make([][]byte, 256)
+	for i := range chunks {
+		chunks[i] = make([]byte, 1+rand.Intn(2*fileBlockSize))
+		rand.Read(chunks[i])
+	}
+
+	tableData, h, err := buildTable(chunks)
+	require.NoError(t, err)
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, h.String()), tableData, 0666))
+
+	for _, mapped := range []bool{false, true} {
+		advice, restore := recordAdvice()
+
+		func() {
+			defer restore()
+
+			fc := newFDCache(1)
+			defer fc.Drop()
+
+			var mp *mmapPool
+			if mapped {
+				mp = newMmapPool(1)
+				defer mp.Drop()
+			}
+
+			cs, err := newMmapTableReader(dir, h, uint32(len(chunks)), nil, fc, mp, fileBlockSize)
+			require.NoError(t, err)
+			defer cs.Close()
+
+			// reading backwards, no read continues the one before, and random reads give no advice, as the file
+			// descriptor and mapping are shared by other readers
+			for i := len(chunks) - 1; i >= 0; i-- {
+				_, err := cs.get(ctx, computeAddr(chunks[i]), &Stats{})
+				require.NoError(t, err)
+			}
+			assert.Empty(t, advice())
+
+			r, err := cs.reader(ctx)
+			require.NoError(t, err)
+			_, err = ioutil.ReadAll(r)
+			require.NoError(t, err)
+		}()
+
+		records := advice()
+		assert.NotEmpty(t, records, "mapped=%t", mapped)
+		for _, rec := range records {
+			assert.Equal(t, unix.FADV_WILLNEED, rec.advice, "mapped=%t", mapped)
+			assert.True(t, rec.len > 0, "mapped=%t: advice must be given for a range, not the whole file", mapped)
+		}
+	}
+}
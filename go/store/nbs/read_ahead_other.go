@@ -0,0 +1,29 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build !linux
+
+package nbs
+
+import "os"
+
+// adviseFileRead tracks the read in |w| but gives no advice, as the kernel can't be advised on this platform.
+func adviseFileRead(f *os.File, w *readAheadWindow, off int64, n int) {
+	w.read(off, n)
+}
+
+// adviseMappingRead tracks the read in |w| but gives no advice, as the kernel can't be advised on this platform.
+func adviseMappingRead(mm []byte, w *readAheadWindow, off int64, n int) {
+	w.read(off, n)
+}
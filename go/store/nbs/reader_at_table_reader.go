@@ -0,0 +1,105 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nbs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// readerAtTableReaderAt is a tableReaderAt that reads a table file through an io.ReaderAt.
+type readerAtTableReaderAt struct {
+	r io.ReaderAt
+}
+
+func (rtra readerAtTableReaderAt) ReadAtWithStats(ctx context.Context, p []byte, off int64, stats *Stats) (int, error) {
+	t1 := time.Now()
+	defer func() {
+		stats.FileBytesPerRead.Sample(uint64(len(p)))
+		stats.FileReadLatency.SampleTimeSince(t1)
+	}()
+
+	return rtra.r.ReadAt(p, off)
+}
+
+// OpenReaderAt opens the table file named |name|, of |size| bytes, that is read through |r| rather than from the
+// persister's directory, so that it can be read from anywhere, such as an object store that is read from on demand.
+// The index is read from the end of the table file with ReadAt, unless it is already in the persister's index
+// cache, and chunks are read with ReadAt as they are requested.
+func (ftp *fsTablePersister) OpenReaderAt(ctx context.Context, r io.ReaderAt, size int64, name addr, chunkCount uint32, stats *Stats) (chunkSource, error) {
+	return openReaderAt(ctx, r, size, name, chunkCount, ftp.indexCache, stats)
+}
+
+// openReaderAt opens the table file named |name|, of |size| bytes, that is read through |r|. |indexCache| may be nil.
+func openReaderAt(ctx context.Context, r io.ReaderAt, size int64, name addr, chunkCount uint32, indexCache *indexCache, stats *Stats) (cs chunkSource, err error) {
+	tra := readerAtTableReaderAt{r}
+
+	if indexCache != nil {
+		indexCache.lockEntry(name)
+		defer func() {
+			unlockErr := indexCache.unlockEntry(name)
+
+			if err == nil {
+				err = unlockErr
+			}
+		}()
+
+		if index, found := indexCache.get(name); found {
+			if index.chunkCount != chunkCount {
+				return nil, errors.New("unexpected chunk count")
+			}
+
+			return &chunkSourceAdapter{newTableReader(index, tra, fileBlockSize), name}, nil
+		}
+	}
+
+	indexLen := int64(indexSize(chunkCount) + footerSize)
+	if size < indexLen {
+		return nil, fmt.Errorf("table file %s of %d bytes is too small for an index of %d chunks", name, size, chunkCount)
+	}
+
+	t1 := time.Now()
+	buff := make([]byte, indexLen)
+	n, err := r.ReadAt(buff, size-indexLen)
+
+	if n < len(buff) {
+		if err == nil || err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+
+	stats.IndexBytesPerRead.Sample(uint64(len(buff)))
+	stats.IndexReadLatency.SampleTimeSince(t1)
+
+	index, err := parseTableIndex(buff)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if index.chunkCount != chunkCount {
+		return nil, errors.New("unexpected chunk count")
+	}
+
+	if indexCache != nil {
+		indexCache.put(name, index)
+	}
+
+	return &chunkSourceAdapter{newTableReader(index, tra, fileBlockSize), name}, nil
+}
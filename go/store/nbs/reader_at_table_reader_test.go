@@ -0,0 +1,92 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nbs
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingReaderAt is an io.ReaderAt that counts the reads made through it.
+type countingReaderAt struct {
+	r     io.ReaderAt
+	reads int32
+}
+
+func (cra *countingReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	atomic.AddInt32(&cra.reads, 1)
+	return cra.r.ReadAt(p, off)
+}
+
+func TestFSTablePersisterOpenReaderAt(t *testing.T) {
+	ctx := context.Background()
+	dir := makeTempDir(t)
+	defer os.RemoveAll(dir)
+	fc := newFDCache(defaultMaxTables)
+	defer fc.Drop()
+	fts := newFSTablePersister(dir, fc, newIndexCache(1<<20)).(*fsTablePersister)
+
+	tableData, name, err := buildTable(testChunks)
+	require.NoError(t, err)
+	size := int64(len(tableData))
+
+	r := &countingReaderAt{r: bytes.NewReader(tableData)}
+	src, err := fts.OpenReaderAt(ctx, r, size, name, uint32(len(testChunks)), &Stats{})
+	require.NoError(t, err)
+	defer src.Close()
+
+	assert.Equal(t, name, mustAddr(src.hash()))
+	assert.Equal(t, uint32(len(testChunks)), mustUint32(src.count()))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&r.reads))
+
+	for _, c := range testChunks {
+		data, err := src.get(ctx, computeAddr(c), &Stats{})
+		require.NoError(t, err)
+		assert.Equal(t, c, data)
+	}
+	assert.True(t, atomic.LoadInt32(&r.reads) > 1)
+
+	t.Run("index cache", func(t *testing.T) {
+		// the index was cached by the first open, so it isn't read again
+		r := &countingReaderAt{r: bytes.NewReader(tableData)}
+		src, err := fts.OpenReaderAt(ctx, r, size, name, uint32(len(testChunks)), &Stats{})
+		require.NoError(t, err)
+		defer src.Close()
+
+		assert.Equal(t, int32(0), atomic.LoadInt32(&r.reads))
+		assertChunksInReader(testChunks, src, assert.New(t))
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		fts := newFSTablePersister(dir, fc, nil).(*fsTablePersister)
+
+		_, err := fts.OpenReaderAt(ctx, bytes.NewReader(tableData), size, name, uint32(len(testChunks)+1), &Stats{})
+		assert.Error(t, err)
+
+		_, err = fts.OpenReaderAt(ctx, bytes.NewReader(tableData), footerSize, name, uint32(len(testChunks)), &Stats{})
+		assert.Error(t, err)
+
+		// a reader shorter than |size|
+		_, err = fts.OpenReaderAt(ctx, bytes.NewReader(tableData[:size-1]), size, name, uint32(len(testChunks)), &Stats{})
+		assert.Equal(t, io.ErrUnexpectedEOF, err)
+	})
+}
@@ -60,6 +60,9 @@ type Stats struct {
 	ChunksPerPersist                 metrics.Histogram
 	CompressedChunkBytesPerPersist   metrics.Histogram
 	UncompressedChunkBytesPerPersist metrics.Histogram
+	// DedupedChunksPerPersist counts the chunks of persisted memTables that weren't written because the store
+	// already had them. Persists that write every chunk aren't sampled
+	DedupedChunksPerPersist metrics.Histogram
 
 	ConjoinLatency   metrics.Histogram
 	BytesPerConjoin  metrics.Histogram
@@ -122,6 +125,7 @@ BytesPerPersist:                  %s
 ChunksPerPersist:                 %s
 CompressedChunkBytesPerPersist:   %s
 UncompressedChunkBytesPerPersist: %s
+DedupedChunksPerPersist:          %s
 ConjoinLatency:                   %s
 BytesPerConjoin:                  %s
 ChunksPerConjoin:                 %s
@@ -161,6 +165,7 @@ WriteManifestLatency:             %s
 		s.ChunksPerPersist,
 		s.CompressedChunkBytesPerPersist,
 		s.UncompressedChunkBytesPerPersist,
+		s.DedupedChunksPerPersist,
 
 		s.ConjoinLatency,
 		s.BytesPerConjoin,
@@ -54,6 +54,13 @@ const (
 	// StorageVersion is the version of the on-disk Noms Chunks Store data format.
 	StorageVersion = "5"
 
+	// extendedTablesStorageVersion is the storage version of file manifests of stores that may contain table files
+	// in the extended format, which are named by a tableHasher other than sha512TableHasher, or have padding before
+	// their index. The footers of these table files have magic numbers that versions of Dolt which predate the
+	// extended format fail to parse, so the manifests of stores that contain them are given a storage version that
+	// those versions refuse to open, rather than letting them fail on the first such table file they read.
+	extendedTablesStorageVersion = "6"
+
 	defaultMemTableSize uint64 = (1 << 20) * 128 // 128MB
 	defaultMaxTables           = 256
 
@@ -310,11 +317,12 @@ func NewBSStore(ctx context.Context, nbfVerStr string, bs blobstore.Blobstore, m
 	return newNomsBlockStore(ctx, nbfVerStr, mm, p, inlineConjoiner{defaultMaxTables}, memTableSize)
 }
 
-func NewLocalStore(ctx context.Context, nbfVerStr string, dir string, memTableSize uint64) (*NomsBlockStore, error) {
-	return newLocalStore(ctx, nbfVerStr, dir, memTableSize, defaultMaxTables)
+// NewLocalStore returns an nbs implementation backed by the table files in |dir|, configured by |opts|.
+func NewLocalStore(ctx context.Context, nbfVerStr string, dir string, memTableSize uint64, opts ...LocalStoreOption) (*NomsBlockStore, error) {
+	return newLocalStore(ctx, nbfVerStr, dir, memTableSize, defaultMaxTables, opts...)
 }
 
-func newLocalStore(ctx context.Context, nbfVerStr string, dir string, memTableSize uint64, maxTables int) (*NomsBlockStore, error) {
+func newLocalStore(ctx context.Context, nbfVerStr string, dir string, memTableSize uint64, maxTables int, opts ...LocalStoreOption) (*NomsBlockStore, error) {
 	cacheOnce.Do(makeGlobalCaches)
 	err := checkDir(dir)
 
@@ -322,14 +330,32 @@ func newLocalStore(ctx context.Context, nbfVerStr string, dir string, memTableSi
 		return nil, err
 	}
 
-	m, err := getFileManifest(ctx, dir)
+	var o localStoreOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	var m manifest
+	if o.extendedTables {
+		m, err = extendedTablesFileManifest(ctx, dir)
+	} else {
+		m, err = getFileManifest(ctx, dir)
+	}
 
 	if err != nil {
 		return nil, err
 	}
 
 	mm := makeManifestManager(m)
-	p := newFSTablePersister(dir, globalFDCache, globalIndexCache)
+
+	var p tablePersister
+	if o.autoConjoinThreshold > 0 {
+		o.persister = append(o.persister, withAutoConjoin(o.autoConjoinThreshold, func(ctx context.Context) (int, error) {
+			return conjoinUpstream(ctx, mm, p)
+		}))
+	}
+
+	p = newFSTablePersister(dir, globalFDCache, globalIndexCache, o.persister...)
 	nbs, err := newNomsBlockStore(ctx, nbfVerStr, mm, p, inlineConjoiner{maxTables}, memTableSize)
 
 	if err != nil {
@@ -954,7 +980,17 @@ func (nbs *NomsBlockStore) Version() string {
 }
 
 func (nbs *NomsBlockStore) Close() error {
-	return nbs.tables.Close()
+	err := nbs.tables.Close()
+
+	if c, ok := nbs.p.(io.Closer); ok {
+		closeErr := c.Close()
+
+		if err == nil {
+			err = closeErr
+		}
+	}
+
+	return err
 }
 
 func (nbs *NomsBlockStore) Stats() interface{} {
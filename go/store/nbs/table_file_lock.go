@@ -0,0 +1,92 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nbs
+
+import (
+	"os"
+	"runtime"
+)
+
+// Table files are locked with an advisory lock on the table file itself, which is respected by every process using
+// the directory. Readers hold it shared while opening a table file, and writers hold it exclusively on the table file
+// being replaced while renaming a new one into its place, so that a reader never maps a table file while it is being
+// replaced.
+
+// rLockTableFile takes a shared lock on the table file at |path|, blocking until no writer is replacing it, and
+// returns a function that releases it. If the file doesn't exist, there is nothing to lock, and opening it will
+// fail.
+func rLockTableFile(path string) (release func() error, err error) {
+	f, err := os.Open(path)
+
+	if os.IsNotExist(err) {
+		return func() error { return nil }, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	return lockTableFile(f, false)
+}
+
+func lockTableFile(f *os.File, exclusive bool) (release func() error, err error) {
+	err = flockFile(f, exclusive)
+
+	if err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+
+	return func() error {
+		err := funlockFile(f)
+		closeErr := f.Close()
+
+		if err == nil {
+			err = closeErr
+		}
+
+		return err
+	}, nil
+}
+
+// renameTableFile renames |from| to the table file |to|. If |to| already exists, it is locked exclusively while it
+// is replaced. Windows doesn't allow a file to be replaced while it is open, so there the rename fails instead of
+// waiting for readers, and the file isn't locked.
+func renameTableFile(from, to string) error {
+	if runtime.GOOS == "windows" {
+		return os.Rename(from, to)
+	}
+
+	f, err := os.Open(to)
+
+	if os.IsNotExist(err) {
+		return os.Rename(from, to)
+	} else if err != nil {
+		return err
+	}
+
+	release, err := lockTableFile(f, true)
+
+	if err != nil {
+		return err
+	}
+
+	err = os.Rename(from, to)
+	releaseErr := release()
+
+	if err == nil {
+		err = releaseErr
+	}
+
+	return err
+}
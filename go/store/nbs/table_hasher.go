@@ -0,0 +1,82 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nbs
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/binary"
+	"hash"
+)
+
+// sha256MagicNumber is the first 8 bytes of the SHA256 hash of "https://github.com/dolthub/dolt/nbs/sha256".
+const sha256MagicNumber = "\x66\x6d\x2c\x05\xf2\x86\xbd\x8a"
+
+// tableHasher computes the content addresses that table files are named by, from the address suffixes of their
+// chunks in the order the chunks are stored. Each tableHasher has its own footer magic number, so that a table file
+// records which hasher named it and its name can be checked by readers. Chunk addresses are computed by callers
+// and are the same whichever tableHasher is used, so chunks are deduplicated across tables named by different
+// hashers. Only sha512TableHasher's magic number is understood by versions that predate tableHashers, which is why
+// stores with tables named by other hashers are marked by their manifest's storage version.
+type tableHasher struct {
+	magic string
+	// paddedSig ends the footer magic number of tables whose index is preceded by padding. The first
+	// paddedMagicLenSize bytes of such a magic number hold the length of the padding.
+	paddedSig string
+	newHash   func() hash.Hash
+}
+
+// paddedMagicLenSize is the size of the padding length at the start of a padded table's footer magic number.
+const paddedMagicLenSize = uint32Size
+
+var (
+	// sha512TableHasher is the default tableHasher, and the only one used before tableHashers were pluggable.
+	sha512TableHasher = &tableHasher{magicNumber, "\x9d\x17\x4e\xa3", sha512.New}
+	// sha256TableHasher is an alternative tableHasher.
+	sha256TableHasher = &tableHasher{sha256MagicNumber, "\x3b\xc6\x80\x5f", sha256.New}
+
+	tableHashers = []*tableHasher{sha512TableHasher, sha256TableHasher}
+)
+
+// name returns the content address of a table whose chunk address suffixes, in storage order, are |suffixes|.
+func (th *tableHasher) name(suffixes []byte) (name addr) {
+	h := th.newHash()
+	h.Write(suffixes)
+	copy(name[:], h.Sum(nil))
+	return name
+}
+
+// paddedMagic returns the footer magic number of a table named by |th| whose index is preceded by |padding| bytes.
+func (th *tableHasher) paddedMagic(padding uint32) string {
+	magic := make([]byte, magicNumberSize)
+	binary.BigEndian.PutUint32(magic, padding)
+	copy(magic[paddedMagicLenSize:], th.paddedSig)
+	return string(magic)
+}
+
+// tableHasherForMagic returns the tableHasher whose footer magic number is |magic|, and the length of the padding
+// preceding the table's index.
+func tableHasherForMagic(magic []byte) (*tableHasher, uint32, bool) {
+	for _, th := range tableHashers {
+		if string(magic) == th.magic {
+			return th, 0, true
+		}
+
+		if len(magic) == magicNumberSize && string(magic[paddedMagicLenSize:]) == th.paddedSig {
+			return th, binary.BigEndian.Uint32(magic), true
+		}
+	}
+	return nil, 0, false
+}
@@ -24,7 +24,6 @@ package nbs
 import (
 	"bytes"
 	"context"
-	"crypto/sha512"
 	"encoding/binary"
 	"errors"
 	"fmt"
@@ -34,6 +33,9 @@ import (
 	"github.com/dolthub/dolt/go/store/util/sizecache"
 )
 
+// ErrTableNameMismatch is returned when a table file's content address doesn't match the name it was opened by.
+var ErrTableNameMismatch = errors.New("table file name does not match its contents")
+
 // tablePersister allows interaction with persistent storage. It provides
 // primitives for pushing the contents of a memTable to persistent storage,
 // opening persistent tables for reading, and conjoining a number of existing
@@ -109,8 +111,19 @@ func (sic *indexCache) get(name addr) (onHeapTableIndex, bool) {
 }
 
 func (sic *indexCache) put(name addr, idx onHeapTableIndex) {
-	indexSize := uint64(idx.chunkCount) * (addrSize + ordinalSize + lengthSize + uint64Size)
-	sic.cache.Add(name, indexSize, idx)
+	sic.cache.Add(name, indexCacheSize(idx), idx)
+}
+
+// putIfCheap caches |idx| unless making room for it would evict an index that has been read from the cache since
+// it was cached. It's for indices of tables that were just written, which are re-parsed on demand if they're
+// needed, and are less likely to be needed than indices that are already in use. It returns whether |idx| was
+// cached.
+func (sic *indexCache) putIfCheap(name addr, idx onHeapTableIndex) bool {
+	return sic.cache.AddUnlessEvictsUsed(name, indexCacheSize(idx), idx)
+}
+
+func indexCacheSize(idx onHeapTableIndex) uint64 {
+	return uint64(idx.chunkCount) * (addrSize + ordinalSize + lengthSize + uint64Size)
 }
 
 type chunkSourcesByAscendingCount struct {
@@ -213,6 +226,12 @@ func (cp compactionPlan) suffixes() []byte {
 }
 
 func planConjoin(sources chunkSources, stats *Stats) (plan compactionPlan, err error) {
+	return planConjoinHashed(sources, stats, sha512TableHasher)
+}
+
+// planConjoinHashed is like planConjoin, but the merged index's footer records |hasher| as the tableHasher naming
+// the conjoined table.
+func planConjoinHashed(sources chunkSources, stats *Stats, hasher *tableHasher) (plan compactionPlan, err error) {
 	var totalUncompressedData uint64
 	for _, src := range sources {
 		var uncmp uint64
@@ -315,22 +334,47 @@ func planConjoin(sources chunkSources, stats *Stats) (plan compactionPlan, err e
 		pfxPos += ordinalSize
 	}
 
-	writeFooter(plan.mergedIndex[uint64(len(plan.mergedIndex))-footerSize:], plan.chunkCount, totalUncompressedData)
+	writeFooter(plan.mergedIndex[uint64(len(plan.mergedIndex))-footerSize:], plan.chunkCount, totalUncompressedData, hasher.magic)
 
 	stats.BytesPerConjoin.Sample(uint64(plan.totalCompressedData) + uint64(len(plan.mergedIndex)))
 	return plan, nil
 }
 
 func nameFromSuffixes(suffixes []byte) (name addr) {
-	sha := sha512.New()
-	sha.Write(suffixes)
+	return sha512TableHasher.name(suffixes)
+}
+
+// tableNameFromIndex returns the content address of the table indexed by |index|: the hash of the address
+// suffixes of its chunks in the order the chunks are stored, as computed by the tableHasher that wrote the table.
+func tableNameFromIndex(index tableIndex) addr {
+	suffixes := make([]byte, uint64(index.ChunkCount())*addrSuffixSize)
+	ordinals := index.Ordinals()
+
+	for i := uint32(0); i < index.ChunkCount(); i++ {
+		var a addr
+		index.IndexEntry(i, &a)
+		offset := uint64(ordinals[i]) * addrSuffixSize
+		copy(suffixes[offset:offset+addrSuffixSize], a[addrPrefixSize:])
+	}
 
-	var h []byte
-	h = sha.Sum(h) // Appends hash to h
-	copy(name[:], h)
-	return
+	return index.Hasher().name(suffixes)
+}
+
+// verifyTableName returns an error wrapping ErrTableNameMismatch if the content address of |cs| isn't |name|.
+func verifyTableName(cs chunkSource, name addr) error {
+	index, err := cs.index()
+
+	if err != nil {
+		return err
+	}
+
+	if actual := tableNameFromIndex(index); actual != name {
+		return fmt.Errorf("%w: table file %s has content address %s", ErrTableNameMismatch, name.String(), actual.String())
+	}
+
+	return nil
 }
 
 func calcChunkDataLen(index tableIndex) uint64 {
-	return index.TableFileSize() - indexSize(index.ChunkCount()) - footerSize
+	return index.TableFileSize() - index.Padding() - indexSize(index.ChunkCount()) - footerSize
 }
@@ -109,6 +109,9 @@ type onHeapTableIndex struct {
 	prefixes, offsets     []uint64
 	lengths, ordinals     []uint32
 	suffixes              []byte
+	hasher                *tableHasher
+	// padding is the number of bytes between the chunk data and the index
+	padding uint64
 }
 
 type indexEntry interface {
@@ -161,6 +164,8 @@ type mmapTableIndex struct {
 	prefixes              []uint64
 	data                  mmap.MMap
 	refCnt                *int32
+	hasher                *tableHasher
+	padding               uint64
 }
 
 func (i mmapTableIndex) Prefixes() []uint64 {
@@ -195,6 +200,17 @@ func (i mmapTableIndex) Close() error {
 	return nil
 }
 
+func (i mmapTableIndex) Padding() uint64 {
+	return i.padding
+}
+
+func (i mmapTableIndex) Hasher() *tableHasher {
+	if i.hasher == nil {
+		return sha512TableHasher
+	}
+	return i.hasher
+}
+
 func (i mmapTableIndex) Clone() tableIndex {
 	cnt := atomic.AddInt32(i.refCnt, 1)
 	if cnt == 1 {
@@ -301,6 +317,8 @@ func newMmapTableIndex(ti onHeapTableIndex, f *os.File) (mmapTableIndex, error)
 		ti.Prefixes(),
 		arr,
 		refCnt,
+		ti.hasher,
+		ti.padding,
 	}, nil
 }
 
@@ -356,6 +374,14 @@ type tableIndex interface {
 	// Clone returns a |tableIndex| with the same contents which can be
 	// |Close|d independently.
 	Clone() tableIndex
+
+	// Hasher returns the tableHasher that named the indexed table file, as
+	// recorded by its footer's magic number.
+	Hasher() *tableHasher
+
+	// Padding returns the number of bytes between the chunk data and the
+	// index of the indexed table file.
+	Padding() uint64
 }
 
 var _ tableIndex = mmapTableIndex{}
@@ -369,7 +395,9 @@ func parseTableIndex(buff []byte) (onHeapTableIndex, error) {
 	// footer
 	pos -= magicNumberSize
 
-	if string(buff[pos:]) != magicNumber {
+	hasher, padding, ok := tableHasherForMagic(buff[pos:])
+
+	if !ok {
 		return onHeapTableIndex{}, ErrInvalidTableFile
 	}
 
@@ -424,6 +452,8 @@ func parseTableIndex(buff []byte) (onHeapTableIndex, error) {
 		prefixes, offsets,
 		lengths, ordinals,
 		suffixes,
+		hasher,
+		uint64(padding),
 	}, nil
 }
 
@@ -464,7 +494,7 @@ func (ti onHeapTableIndex) TableFileSize() uint64 {
 		return footerSize
 	}
 	len, offset := ti.offsets[ti.chunkCount-1], uint64(ti.lengths[ti.chunkCount-1])
-	return offset + len + indexSize(ti.chunkCount) + footerSize
+	return offset + len + ti.padding + indexSize(ti.chunkCount) + footerSize
 }
 
 // prefixIdx returns the first position in |tr.prefixes| whose value ==
@@ -549,6 +579,17 @@ func (i onHeapTableIndex) Clone() tableIndex {
 	return i
 }
 
+func (i onHeapTableIndex) Padding() uint64 {
+	return i.padding
+}
+
+func (i onHeapTableIndex) Hasher() *tableHasher {
+	if i.hasher == nil {
+		return sha512TableHasher
+	}
+	return i.hasher
+}
+
 // newTableReader parses a valid nbs table byte stream and returns a reader. buff must end with an NBS index
 // and footer, though it may contain an unspecified number of bytes before that data. r should allow
 // retrieving any desired range of bytes from the table.
@@ -22,11 +22,11 @@
 package nbs
 
 import (
-	"crypto/sha512"
 	"encoding/binary"
 	"errors"
 	"fmt"
 	"hash"
+	"math"
 	"sort"
 
 	"github.com/golang/snappy"
@@ -42,6 +42,8 @@ type tableWriter struct {
 	totalUncompressedData uint64
 	prefixes              prefixIndexSlice // TODO: This is in danger of exploding memory
 	blockHash             hash.Hash
+	// magic is the footer magic number of the tableHasher computing |blockHash|
+	magic string
 
 	snapper snappyEncoder
 }
@@ -78,12 +80,18 @@ func suffixesOffset(numChunks uint32) uint64 {
 
 // len(buff) must be >= maxTableSize(numChunks, totalData)
 func newTableWriter(buff []byte, snapper snappyEncoder) *tableWriter {
+	return newHashedTableWriter(buff, snapper, sha512TableHasher)
+}
+
+// newHashedTableWriter returns a tableWriter whose table is named by |hasher|.
+func newHashedTableWriter(buff []byte, snapper snappyEncoder, hasher *tableHasher) *tableWriter {
 	if snapper == nil {
 		snapper = realSnappyEncoder{}
 	}
 	return &tableWriter{
 		buff:      buff,
-		blockHash: sha512.New(),
+		blockHash: hasher.newHash(),
+		magic:     hasher.magic,
 		snapper:   snapper,
 	}
 }
@@ -195,10 +203,11 @@ func (tw *tableWriter) writeIndex() error {
 }
 
 func (tw *tableWriter) writeFooter() {
-	tw.pos += writeFooter(tw.buff[tw.pos:], uint32(len(tw.prefixes)), tw.totalUncompressedData)
+	tw.pos += writeFooter(tw.buff[tw.pos:], uint32(len(tw.prefixes)), tw.totalUncompressedData, tw.magic)
 }
 
-func writeFooter(dst []byte, chunkCount uint32, uncData uint64) (consumed uint64) {
+// writeFooter writes a table footer ending with |magic|, the magic number of the tableHasher naming the table.
+func writeFooter(dst []byte, chunkCount uint32, uncData uint64, magic string) (consumed uint64) {
 	// chunk count
 	binary.BigEndian.PutUint32(dst[consumed:], chunkCount)
 	consumed += uint32Size
@@ -208,7 +217,76 @@ func writeFooter(dst []byte, chunkCount uint32, uncData uint64) (consumed uint64
 	consumed += uint64Size
 
 	// magic number
-	copy(dst[consumed:], magicNumber)
+	copy(dst[consumed:], magic)
 	consumed += magicNumberSize
 	return
 }
+
+// tablePadding returns the number of bytes of padding that align an index following |dataLen| bytes of chunk data
+// to a multiple of |alignment| bytes. An |alignment| of zero means no alignment.
+func tablePadding(dataLen, alignment uint64) uint64 {
+	if alignment == 0 {
+		return 0
+	}
+	return (alignment - dataLen%alignment) % alignment
+}
+
+// setFooterPadding rewrites the magic number of the footer at the end of |buff| to record that the table's index
+// is preceded by |padding| bytes of padding.
+func setFooterPadding(buff []byte, padding uint64) error {
+	if uint64(len(buff)) < footerSize {
+		return ErrInvalidTableFile
+	}
+
+	magic := buff[uint64(len(buff))-magicNumberSize:]
+	hasher, _, ok := tableHasherForMagic(magic)
+
+	if !ok {
+		return ErrInvalidTableFile
+	}
+
+	if padding > math.MaxUint32 {
+		return fmt.Errorf("table padding of %d bytes is too large", padding)
+	}
+
+	if padding == 0 {
+		copy(magic, hasher.magic)
+	} else {
+		copy(magic, hasher.paddedMagic(uint32(padding)))
+	}
+
+	return nil
+}
+
+// alignTable returns the table file |data| with padding inserted between its chunk data and its index, so that the
+// index begins at a multiple of |alignment| bytes. The padding is recorded in the footer. The table's name, which
+// is computed from its chunk addresses, is unchanged.
+func alignTable(data []byte, alignment uint64) ([]byte, error) {
+	if uint64(len(data)) < footerSize {
+		return nil, ErrInvalidTableFile
+	}
+
+	chunkCount := binary.BigEndian.Uint32(data[uint64(len(data))-footerSize:])
+	tail := indexSize(chunkCount) + footerSize
+
+	if uint64(len(data)) < tail {
+		return nil, ErrInvalidTableFile
+	}
+
+	dataLen := uint64(len(data)) - tail
+	padding := tablePadding(dataLen, alignment)
+
+	if padding == 0 {
+		return data, nil
+	}
+
+	aligned := make([]byte, uint64(len(data))+padding)
+	copy(aligned, data[:dataLen])
+	copy(aligned[dataLen+padding:], data[dataLen:])
+
+	if err := setFooterPadding(aligned, padding); err != nil {
+		return nil, err
+	}
+
+	return aligned, nil
+}
@@ -0,0 +1,134 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nbs
+
+import (
+	"context"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// replicaChunkDiff is the difference between the chunks stored by two replicas of a table file set.
+type replicaChunkDiff struct {
+	// missingFromA are the addresses of chunks stored only by the second replica.
+	missingFromA []addr
+	// missingFromB are the addresses of chunks stored only by the first replica.
+	missingFromB []addr
+}
+
+// Equivalent returns true if both replicas store the same chunks.
+func (d replicaChunkDiff) Equivalent() bool {
+	return len(d.missingFromA) == 0 && len(d.missingFromB) == 0
+}
+
+// CompareReplicaChunks compares the union of the chunk addresses in the table files of |a| against the union of
+// those in the table files of |b|, reading only table indexes. The two replicas are equivalent if they store the
+// same chunks, even if those chunks are split into table files differently. Each list in the result is sorted.
+func CompareReplicaChunks(ctx context.Context, a, b *fsTablePersister) (replicaChunkDiff, error) {
+	aAddrs, err := a.chunkAddrs(ctx)
+
+	if err != nil {
+		return replicaChunkDiff{}, err
+	}
+
+	bAddrs, err := b.chunkAddrs(ctx)
+
+	if err != nil {
+		return replicaChunkDiff{}, err
+	}
+
+	onlyA, onlyB, _ := diffSortedAddrs(aAddrs, bAddrs)
+	return replicaChunkDiff{missingFromA: onlyB, missingFromB: onlyA}, nil
+}
+
+// chunkAddrs returns the address of every chunk in the table files in |ftp|'s directory, sorted and without
+// duplicates.
+func (ftp *fsTablePersister) chunkAddrs(ctx context.Context) (addrSlice, error) {
+	names, err := ftp.ListTables()
+
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[addr]struct{})
+	for _, name := range names {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		chunkCount, err := tableFileChunkCount(filepath.Join(ftp.dir, name.String()))
+
+		if err != nil {
+			return nil, err
+		}
+
+		cs, err := ftp.Open(ctx, name, chunkCount, nil)
+
+		if err != nil {
+			return nil, err
+		}
+
+		addrs, err := sortedIndexAddrs(cs)
+		cs.Close()
+
+		if err != nil {
+			return nil, err
+		}
+
+		for _, a := range addrs {
+			seen[a] = struct{}{}
+		}
+	}
+
+	addrs := make(addrSlice, 0, len(seen))
+	for a := range seen {
+		addrs = append(addrs, a)
+	}
+
+	sort.Sort(addrs)
+	return addrs, nil
+}
+
+// tableFileChunkCount reads the chunk count from the footer of the table file at |path|.
+func tableFileChunkCount(path string) (uint32, error) {
+	f, err := os.Open(path)
+
+	if err != nil {
+		return 0, err
+	}
+
+	defer f.Close()
+
+	fi, err := f.Stat()
+
+	if err != nil {
+		return 0, err
+	}
+
+	if fi.Size() < footerSize {
+		return 0, ErrInvalidTableFile
+	}
+
+	footer := make([]byte, footerSize)
+	_, err = f.ReadAt(footer, fi.Size()-footerSize)
+
+	if err != nil {
+		return 0, err
+	}
+
+	return binary.BigEndian.Uint32(footer), nil
+}
@@ -0,0 +1,87 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nbs
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompareReplicaChunks(t *testing.T) {
+	ctx := context.Background()
+	fc := newFDCache(defaultMaxTables)
+	defer fc.Drop()
+
+	newReplica := func(tables ...[][]byte) *fsTablePersister {
+		dir := makeTempDir(t)
+		fts := newFSTablePersister(dir, fc, nil).(*fsTablePersister)
+		for _, chunx := range tables {
+			_, err := persistTableData(fts, chunx...)
+			require.NoError(t, err)
+		}
+		return fts
+	}
+
+	// the same chunks split into table files differently
+	a := newReplica(testChunks[:1], testChunks[1:])
+	defer os.RemoveAll(a.dir)
+	b := newReplica(testChunks[:2], testChunks[2:])
+	defer os.RemoveAll(b.dir)
+
+	d, err := CompareReplicaChunks(ctx, a, b)
+	require.NoError(t, err)
+	assert.True(t, d.Equivalent())
+	assert.Empty(t, d.missingFromA)
+	assert.Empty(t, d.missingFromB)
+
+	// the last chunk is missing from the second replica, which has a chunk of its own
+	extra := []byte("extra")
+	c := newReplica(testChunks[:2], [][]byte{extra})
+	defer os.RemoveAll(c.dir)
+
+	d, err = CompareReplicaChunks(ctx, a, c)
+	require.NoError(t, err)
+	assert.False(t, d.Equivalent())
+	assert.Equal(t, []addr{computeAddr(extra)}, d.missingFromA)
+	assert.Equal(t, []addr{computeAddr(testChunks[2])}, d.missingFromB)
+
+	d, err = CompareReplicaChunks(ctx, c, a)
+	require.NoError(t, err)
+	assert.Equal(t, []addr{computeAddr(testChunks[2])}, d.missingFromA)
+	assert.Equal(t, []addr{computeAddr(extra)}, d.missingFromB)
+}
+
+func TestTableFileChunkCount(t *testing.T) {
+	dir := makeTempDir(t)
+	defer os.RemoveAll(dir)
+
+	name, err := writeTableData(dir, testChunks...)
+	require.NoError(t, err)
+
+	chunkCount, err := tableFileChunkCount(filepath.Join(dir, name.String()))
+	require.NoError(t, err)
+	assert.Equal(t, uint32(len(testChunks)), chunkCount)
+
+	short := filepath.Join(dir, "short")
+	require.NoError(t, ioutil.WriteFile(short, []byte("short"), 0666))
+	_, err = tableFileChunkCount(short)
+	assert.Equal(t, ErrInvalidTableFile, err)
+}
@@ -0,0 +1,452 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package edits
+
+import (
+	"bufio"
+	"container/heap"
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+
+	"github.com/dolthub/dolt/go/store/chunks"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// DefaultKVPRunFanIn is the number of runs merged at a time by MergeKVPRuns when no fan-in is given.
+const DefaultKVPRunFanIn = 16
+
+var ErrInvalidFanIn = errors.New("fan-in must be at least 2")
+
+// KVPRun is a sorted run of KVPs spilled to a file.
+type KVPRun struct {
+	path    string
+	numKVPs int64
+}
+
+// Path returns the path of the file backing the run.
+func (run *KVPRun) Path() string {
+	return run.path
+}
+
+// NumEdits returns the number of KVPs in the run.
+func (run *KVPRun) NumEdits() int64 {
+	return run.numKVPs
+}
+
+// Remove deletes the file backing the run.
+func (run *KVPRun) Remove() error {
+	return os.Remove(run.path)
+}
+
+// WriteKVPRun writes the KVPs provided by |itr|, which must be in key order, to a new file in |dir|.
+func WriteKVPRun(ctx context.Context, nbf *types.NomsBinFormat, dir string, itr types.EditProvider) (run *KVPRun, err error) {
+	f, err := ioutil.TempFile(dir, "kvp_run_*")
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		closeErr := f.Close()
+
+		if err == nil {
+			err = closeErr
+		}
+
+		if err != nil {
+			_ = os.Remove(f.Name())
+			run = nil
+		}
+	}()
+
+	wr := bufio.NewWriter(f)
+	numKVPs := int64(0)
+
+	for {
+		kvp, err := itr.Next()
+
+		if err != nil {
+			return nil, err
+		}
+
+		if kvp == nil {
+			break
+		}
+
+		if err := writeKVP(ctx, nbf, wr, kvp); err != nil {
+			return nil, err
+		}
+
+		numKVPs++
+	}
+
+	if err := wr.Flush(); err != nil {
+		return nil, err
+	}
+
+	return &KVPRun{f.Name(), numKVPs}, nil
+}
+
+// writeKVP writes the key and then the value of |kvp|, each as a length-prefixed encoded value. A nil value is
+// written with a length of zero.
+func writeKVP(ctx context.Context, nbf *types.NomsBinFormat, wr io.Writer, kvp *types.KVP) error {
+	k, err := kvp.Key.Value(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	if err := writeEncodedValue(nbf, wr, k); err != nil {
+		return err
+	}
+
+	if kvp.Val == nil {
+		return writeEncodedValue(nbf, wr, nil)
+	}
+
+	v, err := kvp.Val.Value(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	return writeEncodedValue(nbf, wr, v)
+}
+
+func writeEncodedValue(nbf *types.NomsBinFormat, wr io.Writer, v types.Value) error {
+	var data []byte
+	if v != nil {
+		c, err := types.EncodeValue(v, nbf)
+
+		if err != nil {
+			return err
+		}
+
+		data = c.Data()
+	}
+
+	var size [4]byte
+	binary.BigEndian.PutUint32(size[:], uint32(len(data)))
+
+	if _, err := wr.Write(size[:]); err != nil {
+		return err
+	}
+
+	_, err := wr.Write(data)
+	return err
+}
+
+// KVPRunReader is an EditProvider that reads the KVPs of a KVPRun in order. It holds the run's file open until
+// Close is called.
+type KVPRunReader struct {
+	run *KVPRun
+	f   *os.File
+	rd  *bufio.Reader
+	vrw types.ValueReadWriter
+}
+
+// Open opens the run for reading. Values are decoded using |vrw|.
+func (run *KVPRun) Open(vrw types.ValueReadWriter) (*KVPRunReader, error) {
+	f, err := os.Open(run.path)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &KVPRunReader{run, f, bufio.NewReader(f), vrw}, nil
+}
+
+// Next returns the next KVP, or nil once every KVP in the run has been read.
+func (rdr *KVPRunReader) Next() (*types.KVP, error) {
+	k, err := rdr.readEncodedValue()
+
+	if err == io.EOF {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	} else if k == nil {
+		return nil, errors.New("corrupt kvp run: missing key")
+	}
+
+	v, err := rdr.readEncodedValue()
+
+	if err == io.EOF {
+		return nil, io.ErrUnexpectedEOF
+	} else if err != nil {
+		return nil, err
+	}
+
+	kvp := &types.KVP{Key: k}
+	if v != nil {
+		kvp.Val = v
+	}
+
+	return kvp, nil
+}
+
+func (rdr *KVPRunReader) readEncodedValue() (types.Value, error) {
+	var size [4]byte
+
+	if _, err := io.ReadFull(rdr.rd, size[:]); err != nil {
+		return nil, err
+	}
+
+	n := binary.BigEndian.Uint32(size[:])
+	if n == 0 {
+		return nil, nil
+	}
+
+	data := make([]byte, n)
+	if _, err := io.ReadFull(rdr.rd, data); err == io.EOF {
+		return nil, io.ErrUnexpectedEOF
+	} else if err != nil {
+		return nil, err
+	}
+
+	return types.DecodeValue(chunks.NewChunk(data), rdr.vrw)
+}
+
+// NumEdits returns the number of KVPs in the run.
+func (rdr *KVPRunReader) NumEdits() int64 {
+	return rdr.run.numKVPs
+}
+
+// Close closes the run's file.
+func (rdr *KVPRunReader) Close() error {
+	return rdr.f.Close()
+}
+
+// MergeKVPRuns merges |runs| into a single sorted run in |dir|. Runs are merged at most |fanIn| at a time into
+// intermediate runs until one run remains, so no more than |fanIn| runs are open for reading at once regardless of
+// how many runs there are. KVPs with equal keys are written in the order of the runs they came from. The input
+// runs are consumed: each is removed once it has been merged.
+func MergeKVPRuns(ctx context.Context, vrw types.ValueReadWriter, dir string, runs []*KVPRun, fanIn int) (*KVPRun, error) {
+	if fanIn < 2 {
+		return nil, ErrInvalidFanIn
+	}
+
+	if len(runs) == 0 {
+		return WriteKVPRun(ctx, vrw.Format(), dir, types.EmptyEditProvider{})
+	}
+
+	for len(runs) > 1 {
+		merged := make([]*KVPRun, 0, (len(runs)+fanIn-1)/fanIn)
+
+		for start := 0; start < len(runs); start += fanIn {
+			end := start + fanIn
+			if end > len(runs) {
+				end = len(runs)
+			}
+
+			group := runs[start:end]
+			if len(group) == 1 {
+				merged = append(merged, group[0])
+				continue
+			}
+
+			run, err := mergeRunGroup(ctx, vrw, dir, group)
+
+			if err != nil {
+				return nil, err
+			}
+
+			merged = append(merged, run)
+		}
+
+		runs = merged
+	}
+
+	return runs[0], nil
+}
+
+// mergeRunGroup merges |runs| into a new run and removes them.
+func mergeRunGroup(ctx context.Context, vrw types.ValueReadWriter, dir string, runs []*KVPRun) (*KVPRun, error) {
+	readers := make([]*KVPRunReader, 0, len(runs))
+	defer func() {
+		for _, rdr := range readers {
+			_ = rdr.Close()
+		}
+	}()
+
+	for _, run := range runs {
+		rdr, err := run.Open(vrw)
+
+		if err != nil {
+			return nil, err
+		}
+
+		readers = append(readers, rdr)
+	}
+
+	itr, err := newKVPMergeItr(vrw.Format(), readers)
+
+	if err != nil {
+		return nil, err
+	}
+
+	merged, err := WriteKVPRun(ctx, vrw.Format(), dir, itr)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// the inputs must be closed before they can be removed on some platforms
+	for _, rdr := range readers {
+		if err := rdr.Close(); err != nil {
+			_ = merged.Remove()
+			return nil, err
+		}
+	}
+	readers = nil
+
+	for _, run := range runs {
+		if err := run.Remove(); err != nil {
+			return nil, err
+		}
+	}
+
+	return merged, nil
+}
+
+// kvpMergeItr is an EditProvider that performs a k-way merge of sorted EditProviders.
+type kvpMergeItr struct {
+	h        *kvpHeap
+	numEdits int64
+}
+
+func newKVPMergeItr(nbf *types.NomsBinFormat, itrs []*KVPRunReader) (*kvpMergeItr, error) {
+	h := &kvpHeap{nbf: nbf}
+	numEdits := int64(0)
+
+	for i, itr := range itrs {
+		numEdits += itr.NumEdits()
+		kvp, err := itr.Next()
+
+		if err != nil {
+			return nil, err
+		}
+
+		if kvp != nil {
+			h.entries = append(h.entries, kvpHeapEntry{kvp, i, itr})
+		}
+	}
+
+	heap.Init(h)
+
+	if h.err != nil {
+		return nil, h.err
+	}
+
+	return &kvpMergeItr{h, numEdits}, nil
+}
+
+// Next returns the least KVP remaining across all the merged iterators.
+func (itr *kvpMergeItr) Next() (*types.KVP, error) {
+	if itr.h.Len() == 0 {
+		return nil, nil
+	}
+
+	top := &itr.h.entries[0]
+	kvp := top.kvp
+
+	next, err := top.itr.Next()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if next == nil {
+		heap.Pop(itr.h)
+	} else {
+		top.kvp = next
+		heap.Fix(itr.h, 0)
+	}
+
+	if itr.h.err != nil {
+		return nil, itr.h.err
+	}
+
+	return kvp, nil
+}
+
+// NumEdits returns the total number of KVPs in the merged iterators.
+func (itr *kvpMergeItr) NumEdits() int64 {
+	return itr.numEdits
+}
+
+type kvpHeapEntry struct {
+	kvp *types.KVP
+	ord int
+	itr types.EditProvider
+}
+
+// kvpHeap is a heap.Interface ordering entries by key, and by the order of their iterators for equal keys. As
+// heap.Interface can't return errors, the first error from comparing keys is kept in |err|.
+type kvpHeap struct {
+	entries []kvpHeapEntry
+	nbf     *types.NomsBinFormat
+	err     error
+}
+
+func (h *kvpHeap) Len() int {
+	return len(h.entries)
+}
+
+func (h *kvpHeap) Less(i, j int) bool {
+	ei, ej := h.entries[i], h.entries[j]
+	isLess, err := ei.kvp.Key.Less(h.nbf, ej.kvp.Key)
+
+	if err != nil {
+		if h.err == nil {
+			h.err = err
+		}
+
+		return false
+	}
+
+	if isLess {
+		return true
+	}
+
+	isGreater, err := ej.kvp.Key.Less(h.nbf, ei.kvp.Key)
+
+	if err != nil {
+		if h.err == nil {
+			h.err = err
+		}
+
+		return false
+	}
+
+	return !isGreater && ei.ord < ej.ord
+}
+
+func (h *kvpHeap) Swap(i, j int) {
+	h.entries[i], h.entries[j] = h.entries[j], h.entries[i]
+}
+
+func (h *kvpHeap) Push(x interface{}) {
+	h.entries = append(h.entries, x.(kvpHeapEntry))
+}
+
+func (h *kvpHeap) Pop() interface{} {
+	last := h.entries[len(h.entries)-1]
+	h.entries = h.entries[:len(h.entries)-1]
+	return last
+}
@@ -0,0 +1,139 @@
+// Copyright 2026 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package edits
+
+import (
+	"context"
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func TestMergeKVPRuns(t *testing.T) {
+	const numRuns = 37
+	const fanIn = 4
+
+	ctx := context.Background()
+	vrw := types.NewMemoryValueStore()
+	nbf := vrw.Format()
+
+	dir, err := ioutil.TempDir("", "TestMergeKVPRuns")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	rng := rand.New(rand.NewSource(0))
+	runs := make([]*KVPRun, numRuns)
+	var allKeys []int
+
+	for i := range runs {
+		var kvps types.KVPSlice
+		n := rng.Intn(50)
+		for j := 0; j < n; j++ {
+			k := rng.Intn(500)
+			allKeys = append(allKeys, k)
+
+			// every value records the run it came from, so the order of equal keys can be checked
+			kvp := types.KVP{Key: types.Uint(k), Val: types.Int(i)}
+			if j%7 == 0 {
+				kvp.Val = nil
+			}
+			kvps = append(kvps, kvp)
+		}
+
+		require.NoError(t, types.SortWithErroringLess(types.KVPSort{Values: kvps, NBF: nbf}))
+		p := kvpSliceProvider(kvps)
+		runs[i], err = WriteKVPRun(ctx, nbf, dir, &p)
+		require.NoError(t, err)
+		require.Equal(t, int64(len(kvps)), runs[i].NumEdits())
+	}
+
+	merged, err := MergeKVPRuns(ctx, vrw, dir, runs, fanIn)
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(allKeys)), merged.NumEdits())
+
+	files, err := ioutil.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, files, 1, "intermediate and input runs should be removed")
+
+	rdr, err := merged.Open(vrw)
+	require.NoError(t, err)
+	defer rdr.Close()
+
+	sort.Ints(allKeys)
+	var prev *types.KVP
+	for i, k := range allKeys {
+		kvp, err := rdr.Next()
+		require.NoError(t, err)
+		require.NotNil(t, kvp)
+		require.Equal(t, types.Uint(k), kvp.Key)
+
+		if prev != nil && prev.Key.(types.Uint) == kvp.Key.(types.Uint) && prev.Val != nil && kvp.Val != nil {
+			assert.LessOrEqual(t, int64(prev.Val.(types.Int)), int64(kvp.Val.(types.Int)), "kvp %d out of run order", i)
+		}
+		prev = kvp
+	}
+
+	kvp, err := rdr.Next()
+	require.NoError(t, err)
+	assert.Nil(t, kvp)
+}
+
+func TestMergeKVPRunsEdgeCases(t *testing.T) {
+	ctx := context.Background()
+	vrw := types.NewMemoryValueStore()
+
+	dir, err := ioutil.TempDir("", "TestMergeKVPRunsEdgeCases")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	_, err = MergeKVPRuns(ctx, vrw, dir, nil, 1)
+	assert.Equal(t, ErrInvalidFanIn, err)
+
+	merged, err := MergeKVPRuns(ctx, vrw, dir, nil, DefaultKVPRunFanIn)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), merged.NumEdits())
+
+	rdr, err := merged.Open(vrw)
+	require.NoError(t, err)
+	kvp, err := rdr.Next()
+	assert.NoError(t, err)
+	assert.Nil(t, kvp)
+	require.NoError(t, rdr.Close())
+	require.NoError(t, merged.Remove())
+}
+
+// kvpSliceProvider is an EditProvider over a sorted KVPSlice.
+type kvpSliceProvider types.KVPSlice
+
+func (p *kvpSliceProvider) Next() (*types.KVP, error) {
+	if len(*p) == 0 {
+		return nil, nil
+	}
+
+	kvp := &(*p)[0]
+	*p = (*p)[1:]
+	return kvp, nil
+}
+
+func (p *kvpSliceProvider) NumEdits() int64 {
+	return int64(len(*p))
+}
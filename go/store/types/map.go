@@ -29,6 +29,7 @@ import (
 	"golang.org/x/sync/errgroup"
 
 	"github.com/dolthub/dolt/go/store/d"
+	"github.com/dolthub/dolt/go/store/hash"
 )
 
 var ErrKeysNotOrdered = errors.New("streaming map keys not ordered")
@@ -182,6 +183,35 @@ LOOP:
 	return newMap(seq.(orderedSequence)), nil
 }
 
+// IsInOrder returns whether every key in |m| is greater than the key before it, as the keys of a map must be. Maps
+// built by this package always are, so a map that isn't was corrupted, and can't be searched or diffed correctly.
+func (m Map) IsInOrder(ctx context.Context) (bool, error) {
+	it, err := m.Iterator(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	var lastK Value
+	for {
+		k, _, err := it.Next(ctx)
+		if err != nil {
+			return false, err
+		}
+
+		if k == nil {
+			return true, nil
+		}
+
+		if lastK != nil {
+			inOrder, err := lastK.Less(m.Format(), k)
+			if err != nil || !inOrder {
+				return false, err
+			}
+		}
+		lastK = k
+	}
+}
+
 // Diff computes the diff from |last| to |m| using the top-down algorithm,
 // which completes as fast as possible while taking longer to return early
 // results than left-to-right.
@@ -211,6 +241,83 @@ func (m Map) DiffLeftRight(ctx context.Context, last Map, changes chan<- ValueCh
 	return orderedSequenceDiffLeftRight(ctx, last.orderedSequence, m.orderedSequence, changes)
 }
 
+// DiffLeftRightInRange computes the diff from |last| to |m| for the keys in
+// [|start|, |end|) using the left-to-right streaming approach. Both maps are
+// positioned at |start| by descending their trees, so entries before |start|
+// are not scanned. A nil |start| or |end| leaves that side of the range open.
+func (m Map) DiffLeftRightInRange(ctx context.Context, last Map, start, end Value, changes chan<- ValueChanged) error {
+	if m.Equals(last) {
+		return nil
+	}
+	return orderedSequenceDiffLeftRightInRange(ctx, last.orderedSequence, m.orderedSequence, start, end, changes)
+}
+
+// MapChunkSummary describes a single chunk referenced by the root of a Map.
+type MapChunkSummary struct {
+	// Hash is the hash of the chunk.
+	Hash hash.Hash
+	// NumLeaves is the number of map entries beneath the chunk.
+	NumLeaves uint64
+}
+
+// RootChunks summarizes the chunks referenced by the root of |m| without
+// reading any of them. If the root of |m| is a leaf, the whole map is returned
+// as a single summary.
+func (m Map) RootChunks() ([]MapChunkSummary, error) {
+	if m.orderedSequence.isLeaf() {
+		h, err := m.Hash(m.Format())
+
+		if err != nil {
+			return nil, err
+		}
+
+		return []MapChunkSummary{{Hash: h, NumLeaves: m.Len()}}, nil
+	}
+
+	tups, err := m.orderedSequence.(metaSequence).tuples()
+
+	if err != nil {
+		return nil, err
+	}
+
+	summaries := make([]MapChunkSummary, len(tups))
+	for i, mt := range tups {
+		ref, err := mt.ref()
+
+		if err != nil {
+			return nil, err
+		}
+
+		summaries[i] = MapChunkSummary{Hash: ref.TargetHash(), NumLeaves: mt.numLeaves()}
+	}
+
+	return summaries, nil
+}
+
+// WithValueReadWriter returns a copy of |m| that reads the parts of its tree
+// that aren't held in memory through the ValueReadWriter returned by |wrap|,
+// which is called with the one |m| reads them through.
+func (m Map) WithValueReadWriter(wrap func(vrw ValueReadWriter) ValueReadWriter) (Map, error) {
+	vrw := m.valueReadWriter()
+	if vrw == nil {
+		return m, nil
+	}
+
+	c, err := EncodeValue(m, m.Format())
+
+	if err != nil {
+		return EmptyMap, err
+	}
+
+	v, err := DecodeValue(c, wrap(vrw))
+
+	if err != nil {
+		return EmptyMap, err
+	}
+
+	return v.(Map), nil
+}
+
 // Collection interface
 
 func (m Map) asSequence() sequence {
@@ -33,10 +33,12 @@ import (
 	"github.com/google/uuid"
 	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 
 	"github.com/dolthub/dolt/go/store/chunks"
 	"github.com/dolthub/dolt/go/store/d"
+	"github.com/dolthub/dolt/go/store/hash"
 )
 
 const testMapSize = 8000
@@ -480,6 +482,35 @@ func TestMapDiff(t *testing.T) {
 	assert.Equal(t, testMapModified, mapDiffModified, "testMap.diff != map.diff")
 }
 
+func TestMapRootChunks(t *testing.T) {
+	smallTestChunks()
+	defer normalProductionChunks()
+
+	vrw := newTestValueStore()
+
+	leaf, err := NewMap(context.Background(), vrw, Float(1), Float(1))
+	require.NoError(t, err)
+	chunks, err := leaf.RootChunks()
+	require.NoError(t, err)
+	require.Len(t, chunks, 1)
+	assert.Equal(t, mustHash(leaf.Hash(vrw.Format())), chunks[0].Hash)
+	assert.Equal(t, uint64(1), chunks[0].NumLeaves)
+
+	m := newRandomTestMap(64*2, newNumber).toMap(vrw)
+	chunks, err = m.RootChunks()
+	require.NoError(t, err)
+	assert.True(t, len(chunks) > 1)
+
+	total := uint64(0)
+	for _, c := range chunks {
+		v, err := vrw.ReadValue(context.Background(), c.Hash)
+		require.NoError(t, err)
+		assert.NotNil(t, v)
+		total += c.NumLeaves
+	}
+	assert.Equal(t, m.Len(), total)
+}
+
 func TestMapMutationReadWriteCount(t *testing.T) {
 	// This test is a sanity check that we are reading a "reasonable" number of
 	// sequences while mutating maps.
@@ -2108,3 +2139,198 @@ func TestMapWithNil(t *testing.T) {
 		NewSet(context.Background(), vrw, String("a"), String("b"), Float(42), nil)
 	})
 }
+
+func TestMapIsInOrder(t *testing.T) {
+	ctx := context.Background()
+	vrw := newTestValueStore()
+
+	m, err := NewMap(ctx, vrw, Int(1), String("a"), Int(2), String("b"), Int(3), String("c"))
+	require.NoError(t, err)
+	inOrder, err := m.IsInOrder(ctx)
+	require.NoError(t, err)
+	assert.True(t, inOrder)
+
+	empty, err := NewMap(ctx, vrw)
+	require.NoError(t, err)
+	inOrder, err = empty.IsInOrder(ctx)
+	require.NoError(t, err)
+	assert.True(t, inOrder)
+
+	for _, keys := range [][]Value{{Int(2), Int(1), Int(3)}, {Int(1), Int(2), Int(2)}} {
+		entries := make([]mapEntry, len(keys))
+		for i, k := range keys {
+			entries[i] = mapEntry{key: k, value: String("v")}
+		}
+		seq, err := newMapLeafSequence(vrw, entries...)
+		require.NoError(t, err)
+
+		inOrder, err = newMap(seq).IsInOrder(ctx)
+		require.NoError(t, err)
+		assert.False(t, inOrder, "%v", keys)
+	}
+}
+
+func accumulateMapDiffRange(m1, m2 Map, start, end Value) (changes []ValueChanged, err error) {
+	ch := make(chan ValueChanged)
+	go func() {
+		defer close(ch)
+		err = m1.DiffLeftRightInRange(context.Background(), m2, start, end, ch)
+	}()
+	for change := range ch {
+		changes = append(changes, change)
+	}
+	return changes, err
+}
+
+func TestMapDiffLeftRightInRange(t *testing.T) {
+	smallTestChunks()
+	defer normalProductionChunks()
+
+	ctx := context.Background()
+	vrw := newTestValueStore()
+
+	m1, err := newSortedTestMap(1000, newNumber).toMap(vrw).Edit().
+		Set(Float(10), String("early")).
+		Set(Float(500), String("middle")).
+		Remove(Float(700)).
+		Set(Float(2000), String("late")).Map(ctx)
+	require.NoError(t, err)
+	m2 := newSortedTestMap(1000, newNumber).toMap(vrw)
+
+	all, err := accumulateMapDiffRange(m1, m2, nil, nil)
+	require.NoError(t, err)
+	require.Len(t, all, 4)
+
+	tests := []struct {
+		start, end Value
+	}{
+		{Float(0), Float(1000)},
+		{Float(400), nil},
+		{nil, Float(500)},
+		{Float(500), Float(501)},
+		{Float(501), Float(700)},
+		{Float(700), Float(2000)},
+		{Float(3000), nil},
+	}
+
+	for _, test := range tests {
+		var expected []ValueChanged
+		for _, c := range all {
+			if test.start != nil {
+				if isLess, err := c.Key.Less(vrw.Format(), test.start); err != nil {
+					require.NoError(t, err)
+				} else if isLess {
+					continue
+				}
+			}
+			if test.end != nil {
+				if isLess, err := c.Key.Less(vrw.Format(), test.end); err != nil {
+					require.NoError(t, err)
+				} else if !isLess {
+					continue
+				}
+			}
+			expected = append(expected, c)
+		}
+
+		actual, err := accumulateMapDiffRange(m1, m2, test.start, test.end)
+		require.NoError(t, err)
+		assert.Equal(t, expected, actual, "[%v, %v)", test.start, test.end)
+	}
+}
+
+// readCountingValueStore counts the values read through it.
+type readCountingValueStore struct {
+	ValueReadWriter
+	reads int
+}
+
+func (vs *readCountingValueStore) ReadValue(ctx context.Context, h hash.Hash) (Value, error) {
+	vs.reads++
+	return vs.ValueReadWriter.ReadValue(ctx, h)
+}
+
+func (vs *readCountingValueStore) ReadManyValues(ctx context.Context, hashes hash.HashSlice) (ValueSlice, error) {
+	vs.reads += len(hashes)
+	return vs.ValueReadWriter.ReadManyValues(ctx, hashes)
+}
+
+func TestMapWithValueReadWriter(t *testing.T) {
+	smallTestChunks()
+	defer normalProductionChunks()
+
+	ctx := context.Background()
+	vrw := newTestValueStore()
+	m := newSortedTestMap(1000, newNumber).toMap(vrw)
+
+	counting := &readCountingValueStore{}
+	wrapped, err := m.WithValueReadWriter(func(vrw ValueReadWriter) ValueReadWriter {
+		counting.ValueReadWriter = vrw
+		return counting
+	})
+	require.NoError(t, err)
+	assert.True(t, m.Equals(wrapped))
+
+	n := 0
+	err = wrapped.IterAll(ctx, func(k, v Value) error {
+		n++
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1000, n)
+	assert.True(t, counting.reads > 0)
+}
+
+func BenchmarkMapDiffLeftRightInRange(b *testing.B) {
+	ctx := context.Background()
+	ts := &chunks.TestStorage{}
+	cs := ts.NewView()
+	vs := newValueStoreWithCacheAndPending(cs, 0, 0)
+
+	const size = 100000
+	kvs := make([]Value, 0, 2*size)
+	for i := 0; i < size; i++ {
+		kvs = append(kvs, Float(i), Float(i))
+	}
+	last, err := NewMap(ctx, vs, kvs...)
+	require.NoError(b, err)
+
+	me := last.Edit()
+	for i := 0; i < size; i += size / 100 {
+		me.Set(Float(i), String("changed"))
+	}
+	current, err := me.Map(ctx)
+	require.NoError(b, err)
+
+	lastRef, err := vs.WriteValue(ctx, last)
+	require.NoError(b, err)
+	currentRef, err := vs.WriteValue(ctx, current)
+	require.NoError(b, err)
+	rt, err := vs.Root(ctx)
+	require.NoError(b, err)
+	_, err = vs.Commit(ctx, rt, rt)
+	require.NoError(b, err)
+
+	lastV, err := lastRef.TargetValue(ctx, vs)
+	require.NoError(b, err)
+	currentV, err := currentRef.TargetValue(ctx, vs)
+	require.NoError(b, err)
+	last, current = lastV.(Map), currentV.(Map)
+
+	run := func(b *testing.B, start, end Value) {
+		reads := cs.Reads()
+		for i := 0; i < b.N; i++ {
+			changes, err := accumulateMapDiffRange(current, last, start, end)
+			require.NoError(b, err)
+			require.NotEmpty(b, changes)
+		}
+		b.ReportMetric(float64(cs.Reads()-reads)/float64(b.N), "reads/op")
+	}
+
+	b.Run("full", func(b *testing.B) {
+		run(b, nil, nil)
+	})
+	b.Run("late range", func(b *testing.B) {
+		run(b, Float(size-size/10), nil)
+	})
+}
@@ -192,23 +192,64 @@ func orderedSequenceDiffInternalNodes(ctx context.Context, last orderedSequence,
 // Streams the diff from |last| to |current| into |changes|, using a left-right approach.
 // Left-right immediately descends to the first change and starts streaming changes, but compared to top-down it's serial and much slower to calculate the full diff.
 func orderedSequenceDiffLeftRight(ctx context.Context, last orderedSequence, current orderedSequence, changes chan<- ValueChanged) error {
-	lastCur, err := newCursorAt(ctx, last, emptyKey, false, false)
+	return orderedSequenceDiffLeftRightInRange(ctx, last, current, nil, nil, changes)
+}
+
+// Streams the diff from |last| to |current| for the keys in [|start|, |end|) into |changes|, using a left-right approach.
+// Both cursors are positioned at |start| by descending the sequences, so keys before |start| are never read. A nil
+// |start| begins at the first key and a nil |end| runs to the last key.
+func orderedSequenceDiffLeftRightInRange(ctx context.Context, last orderedSequence, current orderedSequence, start, end Value, changes chan<- ValueChanged) error {
+	lastCur, err := newCursorAtValue(ctx, last, start, false, false)
 	if err != nil {
 		return err
 	}
 
-	currentCur, err := newCursorAt(ctx, current, emptyKey, false, false)
+	currentCur, err := newCursorAtValue(ctx, current, start, false, false)
 	if err != nil {
 		return err
 	}
 
-	for lastCur.valid() && currentCur.valid() {
+	var endKey *orderedKey
+	if end != nil {
+		k, err := newOrderedKey(end, last.format())
+		if err != nil {
+			return err
+		}
+
+		endKey = &k
+	}
+
+	inRange := func(cur *sequenceCursor) (bool, error) {
+		return cursorBeforeKey(cur, endKey, last.format())
+	}
+
+	bothInRange := func() (bool, error) {
+		if ok, err := inRange(lastCur); err != nil || !ok {
+			return false, err
+		}
+
+		return inRange(currentCur)
+	}
+
+	for {
+		if ok, err := bothInRange(); err != nil {
+			return err
+		} else if !ok {
+			break
+		}
+
 		err := fastForward(ctx, lastCur, currentCur)
 		if err != nil {
 			return err
 		}
 
-		for lastCur.valid() && currentCur.valid() {
+		for {
+			if ok, err := bothInRange(); err != nil {
+				return err
+			} else if !ok {
+				break
+			}
+
 			equals, err := lastCur.seq.getCompareFn(currentCur.seq)(lastCur.idx, currentCur.idx)
 			if err != nil {
 				return err
@@ -290,7 +331,13 @@ func orderedSequenceDiffLeftRight(ctx context.Context, last orderedSequence, cur
 		}
 	}
 
-	for lastCur.valid() {
+	for {
+		if ok, err := inRange(lastCur); err != nil {
+			return err
+		} else if !ok {
+			break
+		}
+
 		lastKey, err := getCurrentKey(lastCur)
 		if err != nil {
 			return err
@@ -311,7 +358,13 @@ func orderedSequenceDiffLeftRight(ctx context.Context, last orderedSequence, cur
 		}
 	}
 
-	for currentCur.valid() {
+	for {
+		if ok, err := inRange(currentCur); err != nil {
+			return err
+		} else if !ok {
+			break
+		}
+
 		currKey, err := getCurrentKey(currentCur)
 		if err != nil {
 			return err
@@ -335,6 +388,24 @@ func orderedSequenceDiffLeftRight(ctx context.Context, last orderedSequence, cur
 	return nil
 }
 
+// cursorBeforeKey returns whether |cur| is valid and, if |end| is non-nil, positioned at a key before |end|.
+func cursorBeforeKey(cur *sequenceCursor, end *orderedKey, nbf *NomsBinFormat) (bool, error) {
+	if !cur.valid() {
+		return false, nil
+	}
+
+	if end == nil {
+		return true, nil
+	}
+
+	key, err := getCurrentKey(cur)
+	if err != nil {
+		return false, err
+	}
+
+	return key.Less(nbf, *end)
+}
+
 /**
  * Advances |a| and |b| past their common sequence of equal values.
  */
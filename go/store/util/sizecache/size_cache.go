@@ -36,6 +36,8 @@ type sizeCacheEntry struct {
 	size     uint64
 	lruEntry *list.Element
 	value    interface{}
+	// used says whether the entry has been gotten since it was added
+	used bool
 }
 
 type SizeCache struct {
@@ -87,6 +89,10 @@ func (c *SizeCache) Get(key interface{}) (interface{}, bool) {
 	defer c.mu.Unlock()
 
 	if entry, ok := c.entry(key); ok {
+		if !entry.used {
+			entry.used = true
+			c.cache[key] = entry
+		}
 		return entry.value, true
 	}
 	return nil, false
@@ -106,25 +112,59 @@ func (c *SizeCache) Add(key interface{}, size uint64, value interface{}) {
 			return
 		}
 
-		newEl := c.lru.PushBack(key)
-		ce := sizeCacheEntry{size: size, lruEntry: newEl, value: value}
-		c.cache[key] = ce
-		c.totalSize += ce.size
-		for el := c.lru.Front(); el != nil && c.totalSize > c.maxSize; {
-			key1 := el.Value
-			ce, ok := c.cache[key1]
-			if !ok {
-				d.Panic("SizeCache is missing expected value")
-			}
-			next := el.Next()
-			delete(c.cache, key1)
-			c.totalSize -= ce.size
-			c.lru.Remove(el)
-			if c.expireCb != nil {
-				c.expireCb(key1)
-			}
-			el = next
+		c.add(key, size, value)
+	}
+}
+
+// AddUnlessEvictsUsed is like Add, but it doesn't add the entry if making room for it would evict an entry that
+// has been gotten since it was added. It's for values that are cheap to recreate and may not be needed again, which
+// shouldn't displace values that are known to be in use. It returns whether the entry is in the cache.
+func (c *SizeCache) AddUnlessEvictsUsed(key interface{}, size uint64, value interface{}) bool {
+	if size > c.maxSize {
+		return false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.entry(key); ok {
+		return true
+	}
+
+	freed := uint64(0)
+	for el := c.lru.Front(); el != nil && c.totalSize+size-freed > c.maxSize; el = el.Next() {
+		ce := c.cache[el.Value]
+		if ce.used {
+			return false
+		}
+		freed += ce.size
+	}
+
+	c.add(key, size, value)
+	return true
+}
+
+// add adds an entry that isn't in the cache and expires entries until the cache is within maxSize. Callers must
+// hold c.mu.
+func (c *SizeCache) add(key interface{}, size uint64, value interface{}) {
+	newEl := c.lru.PushBack(key)
+	ce := sizeCacheEntry{size: size, lruEntry: newEl, value: value}
+	c.cache[key] = ce
+	c.totalSize += ce.size
+	for el := c.lru.Front(); el != nil && c.totalSize > c.maxSize; {
+		key1 := el.Value
+		ce, ok := c.cache[key1]
+		if !ok {
+			d.Panic("SizeCache is missing expected value")
+		}
+		next := el.Next()
+		delete(c.cache, key1)
+		c.totalSize -= ce.size
+		c.lru.Remove(el)
+		if c.expireCb != nil {
+			c.expireCb(key1)
 		}
+		el = next
 	}
 }
 
@@ -173,3 +173,37 @@ func TestZeroSizeCache(t *testing.T) {
 	_, ok := c.Get(hashFromString("data1"))
 	assert.False(ok)
 }
+
+func TestAddUnlessEvictsUsed(t *testing.T) {
+	assert := assert.New(t)
+
+	c := New(400)
+	assert.True(c.AddUnlessEvictsUsed(hashFromString("data-1"), 200, "data-1"))
+	assert.True(c.AddUnlessEvictsUsed(hashFromString("data-2"), 200, "data-2"))
+
+	// data-1 is unused, so it's evicted
+	assert.True(c.AddUnlessEvictsUsed(hashFromString("data-3"), 200, "data-3"))
+	_, ok := c.Get(hashFromString("data-1"))
+	assert.False(ok)
+
+	// data-2 and data-3 are used, so data-4 isn't added
+	_, ok = c.Get(hashFromString("data-2"))
+	assert.True(ok)
+	_, ok = c.Get(hashFromString("data-3"))
+	assert.True(ok)
+	assert.False(c.AddUnlessEvictsUsed(hashFromString("data-4"), 200, "data-4"))
+	_, ok = c.Get(hashFromString("data-4"))
+	assert.False(ok)
+	_, ok = c.Get(hashFromString("data-2"))
+	assert.True(ok)
+	assert.Equal(uint64(400), c.totalSize)
+
+	// entries already in the cache are reported as added
+	assert.True(c.AddUnlessEvictsUsed(hashFromString("data-3"), 200, "data-3"))
+	assert.False(c.AddUnlessEvictsUsed(hashFromString("big-data"), 800, "big-data"))
+
+	// Add evicts used entries
+	c.Add(hashFromString("data-5"), 400, "data-5")
+	_, ok = c.Get(hashFromString("data-2"))
+	assert.False(ok)
+}